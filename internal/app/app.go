@@ -2,17 +2,25 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/mxmrykov/polonium-auth/internal/auth"
 	"github.com/mxmrykov/polonium-auth/internal/config"
 	"github.com/mxmrykov/polonium-auth/internal/repository"
 	"github.com/mxmrykov/polonium-auth/internal/server/httpHost"
+	"github.com/mxmrykov/polonium-auth/internal/service"
 )
 
 type (
 	Application struct {
-		cfg        *config.PAuth
-		httpServer httpHost.IServer
+		cfg           *config.PAuth
+		httpServer    httpHost.IServer
+		privateServer httpHost.IServer
+		keyRing       *auth.KeyRing
+
+		ctx    context.Context
+		cancel context.CancelFunc
 	}
 
 	repositories struct {
@@ -21,13 +29,29 @@ type (
 		emailer repository.IEmailer
 		vault   repository.IAuthVault
 	}
+
+	services struct {
+		auth         service.IAuth
+		oauth        service.IOAuth
+		totp         service.ITOTP
+		ssh          service.ISSH
+		pki          service.IPKI
+		clients      service.IClients
+		consent      service.IConsent
+		registration service.IRegistration
+		federation   service.IFederation
+		saml         service.ISAML
+	}
 )
 
 func New(cfg *config.PAuth) (*Application, error) {
-	httpServer := httpHost.New(cfg)
+	ctx, cancel := context.WithCancel(context.Background())
 	a := &Application{
-		cfg:        cfg,
-		httpServer: httpServer,
+		cfg:           cfg,
+		httpServer:    httpHost.New(&cfg.PublicServer),
+		privateServer: httpHost.New(&cfg.PrivateServer),
+		ctx:           ctx,
+		cancel:        cancel,
 	}
 
 	repos, err := a.initRepositories()
@@ -35,20 +59,59 @@ func New(cfg *config.PAuth) (*Application, error) {
 		return nil, fmt.Errorf("cannot init app: %v", err)
 	}
 
-	jwtProcessor, err := a.initJwtProcessor()
+	jwtProcessor, err := a.initJwtProcessor(repos)
 	if err != nil {
 		return nil, fmt.Errorf("cannot init jwt processor: %v", err)
 	}
 
-	a.setupRoutesAPIV1(repos, jwtProcessor)
+	sshCA, err := a.initSSHCA(repos)
+	if err != nil {
+		return nil, fmt.Errorf("cannot init ssh ca: %v", err)
+	}
+
+	x509CA, err := a.initX509CA(repos)
+	if err != nil {
+		return nil, fmt.Errorf("cannot init x509 ca: %v", err)
+	}
+
+	samlIdP, err := a.initSAMLIdP(repos)
+	if err != nil {
+		return nil, fmt.Errorf("cannot init saml idp: %v", err)
+	}
+
+	services := a.initServices(repos, jwtProcessor, sshCA, x509CA, samlIdP)
+	dpopVerifier := auth.NewDPoPVerifier(
+		repos.authRdb,
+		cfg.PublicServer.URL,
+		cfg.Auth.DPoP.ProofWindow,
+		cfg.Auth.DPoP.NonceTTL,
+		cfg.Auth.DPoP.RequireNonce,
+	)
+
+	a.setupRoutesAPIV1(repos, services, jwtProcessor, dpopVerifier)
+	a.setupRoutesPrivate(services)
 
 	return a, nil
 }
 
 func (a *Application) Run() error {
-	return a.httpServer.Start()
+	go a.keyRing.Run(a.ctx, a.cfg.Auth.Keys.Sync)
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- a.privateServer.Start()
+	}()
+	go func() {
+		errs <- a.httpServer.Start()
+	}()
+
+	return <-errs
 }
 
 func (a *Application) Stop(ctx context.Context) error {
-	return a.httpServer.Stop(ctx)
+	a.cancel()
+	return errors.Join(
+		a.httpServer.Stop(ctx),
+		a.privateServer.Stop(ctx),
+	)
 }
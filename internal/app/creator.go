@@ -1,9 +1,11 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mxmrykov/polonium-auth/internal/auth"
@@ -11,12 +13,14 @@ import (
 	"github.com/mxmrykov/polonium-auth/internal/server/httpHost/handlers"
 	"github.com/mxmrykov/polonium-auth/internal/server/httpHost/middlewares"
 	"github.com/mxmrykov/polonium-auth/internal/service"
-	"github.com/mxmrykov/polonium-auth/pkg/utils"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
 )
 
 func (a *Application) setupRoutesAPIV1(
 	repositories *repositories,
+	services *services,
 	jProcessor *auth.JWTProcessor,
+	dpop *auth.DPoPVerifier,
 ) {
 	apiV1 := a.httpServer.Router().Group("/ext-auth/api/v1")
 
@@ -26,27 +30,182 @@ func (a *Application) setupRoutesAPIV1(
 		apiV1.OPTIONS("*any", func(c *gin.Context) {
 			c.Writer.WriteHeader(http.StatusOK)
 		})
-		//apiV1.Use(middlewares.AuthMW(jProcessor, repositories.authRdb))
+	}
+
+	// ---===Well-known documents===---
+	{
+		wellKnownGroup := a.httpServer.Router().Group("/")
+		wellKnownGroup.Use(gin.Recovery(), middlewares.LogMW(), middlewares.CorsMW())
+		wellKnownHandlers := handlers.NewWellKnown(jProcessor, a.cfg.PublicServer.URL)
+		wellKnownGroup.GET(vars.PathJWKS, wellKnownHandlers.JWKS)
+		wellKnownGroup.GET(vars.PathOpenIDConfiguration, wellKnownHandlers.OpenIDConfiguration)
 	}
 
 	// ---===Routing===---
 	{
 		signupGroup := apiV1.Group("/signup")
 		authGroup := apiV1.Group("/auth")
-		authService, totpService := service.NewAuth(
-			repositories.authPg,
-			repositories.authRdb,
-			repositories.emailer,
-			repositories.vault,
-			jProcessor,
-		), service.NewTOTP(repositories.vault)
-		extAuthHandlers := handlers.NewExtAuth(authService, totpService)
+		oauthGroup := apiV1.Group("/oauth")
+		sshGroup := apiV1.Group("/ssh")
+		pkiGroup := apiV1.Group("/pki")
+		federationGroup := apiV1.Group("/federation")
+		samlGroup := apiV1.Group("/saml/:tenant")
+		extAuthHandlers := handlers.NewExtAuth(
+			services.auth,
+			services.totp,
+			a.cfg.Auth.DefaultClient,
+			a.cfg.Auth.LoginClients,
+		)
+		oauthHandlers := handlers.NewOAuth(
+			services.auth,
+			services.oauth,
+			services.totp,
+			jProcessor.Issuer(),
+			a.cfg.PublicServer.URL,
+		)
+		sshHandlers := handlers.NewSSH(services.ssh)
+		pkiHandlers := handlers.NewPKI(services.pki)
+		consentHandlers := handlers.NewConsent(services.consent)
+		registrationHandlers := handlers.NewRegistration(services.registration, a.cfg.PublicServer.URL)
+		federationHandlers := handlers.NewFederation(services.federation, a.cfg.Federation.ReturnURL)
+		samlHandlers := handlers.NewSAML(services.auth, services.totp, services.saml)
 		signupGroup.POST("/general/check", extAuthHandlers.SignupCheck)
 		signupGroup.POST("/email/check", extAuthHandlers.SignupConfirmEmail)
 		signupGroup.POST("/general/qr", extAuthHandlers.GetQRCode)
-		signupGroup.POST("/general/verify", extAuthHandlers.Complete)
+		dpopMW := middlewares.DPoPMW(dpop)
+		signupGroup.POST("/general/verify", dpopMW, extAuthHandlers.Complete)
 		authGroup.POST("/validate", extAuthHandlers.Authorize)
-		authGroup.POST("/complete", extAuthHandlers.Complete)
+		authGroup.POST("/complete", dpopMW, extAuthHandlers.Complete)
+		authGroup.POST("/token/refresh", dpopMW, extAuthHandlers.RefreshToken)
+
+		authMW := middlewares.AuthMW(jProcessor, repositories.authRdb, dpop, a.cfg.Auth.Audience)
+		authGroup.POST("/logout", authMW, extAuthHandlers.Logout)
+		authGroup.POST("/logout/all", authMW, extAuthHandlers.LogoutAll)
+
+		sessionsGroup := authGroup.Group("/sessions", authMW)
+		sessionsGroup.GET("", extAuthHandlers.Sessions)
+		sessionsGroup.DELETE("/:session", extAuthHandlers.RevokeSession)
+
+		consentsGroup := authGroup.Group("/consents", authMW)
+		consentsGroup.GET("", consentHandlers.Consents)
+		consentsGroup.DELETE("/:client", consentHandlers.RevokeConsent)
+
+		oauthGroup.POST("/revoke", middlewares.TokenClientAuthMW(services.clients), oauthHandlers.Revoke)
+		oauthGroup.GET("/authorize", oauthHandlers.Authorize)
+		oauthGroup.POST("/authorize", oauthHandlers.AuthorizeLogin)
+		oauthGroup.POST("/authorize/consent", oauthHandlers.AuthorizeConsent)
+		oauthGroup.POST("/token", dpopMW, middlewares.TokenClientAuthMW(services.clients), oauthHandlers.Token)
+		oauthGroup.POST("/device_authorization", middlewares.TokenClientAuthMW(services.clients), oauthHandlers.DeviceAuthorization)
+		oauthGroup.GET("/device", oauthHandlers.Device)
+		oauthGroup.POST("/device", oauthHandlers.DeviceLogin)
+		// registered clients get tokens for their own audience, userinfo takes
+		// any of our access tokens carrying the openid scope
+		userInfoMW := middlewares.AuthMW(jProcessor, repositories.authRdb, dpop, "", vars.ScopeOpenID)
+		oauthGroup.GET("/userinfo", userInfoMW, oauthHandlers.UserInfo)
+		oauthGroup.POST("/userinfo", userInfoMW, oauthHandlers.UserInfo)
+
+		registerGroup := oauthGroup.Group("/register")
+		registerGroup.POST("", authMW, registrationHandlers.Register)
+		registerGroup.GET("/:client_id", registrationHandlers.GetRegistration)
+		registerGroup.PUT("/:client_id", registrationHandlers.UpdateRegistration)
+		registerGroup.DELETE("/:client_id", registrationHandlers.DeleteRegistration)
+
+		clientsGroup := oauthGroup.Group("/clients", authMW, middlewares.AdminMW(a.cfg.Auth.Admins))
+		clientsGroup.GET("", registrationHandlers.ListClients)
+		clientsGroup.POST("/:client_id/approve", registrationHandlers.ApproveClient)
+		clientsGroup.POST("/:client_id/reject", registrationHandlers.RejectClient)
+
+		federationGroup.GET("/providers", federationHandlers.Providers)
+		federationGroup.GET("/:provider/login", federationHandlers.Login)
+		federationGroup.GET("/:provider/callback", federationHandlers.Callback)
+
+		samlGroup.GET("/metadata", samlHandlers.Metadata)
+		samlGroup.GET("/sso", samlHandlers.SSO)
+		samlGroup.POST("/sso", samlHandlers.SSOPost)
+		samlGroup.POST("/sso/login", samlHandlers.Login)
+
+		sshGroup.GET("/ca", sshHandlers.CAPublicKey)
+		sshSignMW := middlewares.AuthMW(jProcessor, repositories.authRdb, dpop, a.cfg.Auth.Audience, vars.ScopeSSHSign)
+		sshGroup.POST("/certificates", sshSignMW, sshHandlers.SignCertificate)
+
+		pkiGroup.GET("/ca", pkiHandlers.CACertificate)
+		pkiGroup.GET("/crl", pkiHandlers.CRL)
+		pkiGroup.GET("/certificates/:serial/status", pkiHandlers.CertificateStatus)
+		deploymentGroup := pkiGroup.Group(
+			"/deployers/:deployer/deployments/:deployment",
+			authMW,
+			middlewares.DeployerMW("deployer"),
+		)
+		deploymentGroup.POST("/certificates", pkiHandlers.IssueCertificate)
+		deploymentGroup.DELETE("/certificates", pkiHandlers.RevokeCertificates)
+	}
+}
+
+func (a *Application) setupRoutesPrivate(services *services) {
+	apiV1 := a.privateServer.Router().Group("/int-auth/api/v1")
+
+	// ---===Middlewares, global setup===---
+	{
+		apiV1.Use(gin.Recovery(), middlewares.LogMW())
+	}
+
+	// ---===Routing===---
+	{
+		intAuthHandlers := handlers.NewIntAuth(services.auth)
+		apiV1.POST(
+			"/introspect",
+			middlewares.ClientAuthMW(services.clients, vars.ScopeIntrospect),
+			intAuthHandlers.Introspect,
+		)
+	}
+}
+
+func (a *Application) initServices(
+	repositories *repositories,
+	jProcessor *auth.JWTProcessor,
+	sshCA *auth.SSHCA,
+	x509CA *auth.X509CA,
+	samlIdP *auth.SAMLIdP,
+) *services {
+	clients := service.NewClients(a.cfg.Auth.Clients, repositories.authPg)
+	authService := service.NewAuth(
+		repositories.authPg,
+		repositories.authRdb,
+		repositories.emailer,
+		repositories.vault,
+		clients,
+		jProcessor,
+	)
+
+	return &services{
+		auth: authService,
+		oauth: service.NewOAuth(
+			authService,
+			clients,
+			repositories.authPg,
+			repositories.authRdb,
+			jProcessor,
+			a.cfg.Auth.AuthCodeTTL,
+			a.cfg.Auth.DeviceCodeTTL,
+			a.cfg.Auth.DeviceInterval,
+			a.cfg.Auth.ConsentTTL,
+			a.cfg.Auth.DeviceAttempts,
+		),
+		totp:         service.NewTOTP(repositories.vault),
+		ssh:          service.NewSSH(repositories.authPg, sshCA),
+		pki:          service.NewPKI(repositories.authPg, x509CA),
+		clients:      clients,
+		consent:      service.NewConsent(repositories.authPg, repositories.authRdb),
+		registration: service.NewRegistration(repositories.authPg),
+		federation: service.NewFederation(
+			authService,
+			repositories.authPg,
+			repositories.authRdb,
+			a.cfg.Federation,
+			a.cfg.PublicServer.URL,
+			a.cfg.Auth.DefaultClient,
+		),
+		saml: service.NewSAML(repositories.authPg, samlIdP, a.cfg.SAML, a.cfg.PublicServer.URL),
 	}
 }
 
@@ -71,16 +230,91 @@ func (a *Application) initRepositories() (*repositories, error) {
 	}, nil
 }
 
-func (a *Application) initJwtProcessor() (*auth.JWTProcessor, error) {
-	rsa, err := utils.GenerateRSAKeys(1100 + rand.Intn(899))
+func (a *Application) initJwtProcessor(repositories *repositories) (*auth.JWTProcessor, error) {
+	keyRing, err := auth.NewKeyRing(
+		repositories.vault,
+		a.cfg.Auth.Keys.Algorithm,
+		a.cfg.Auth.Keys.Rotation,
+		a.cfg.Auth.Keys.Lead,
+		a.cfg.Auth.Keys.Grace,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("cannot init RSA keys: %v", err)
+		return nil, err
+	}
+	a.keyRing = keyRing
+
+	ctx, cancel := context.WithTimeout(a.ctx, 30*time.Second)
+	defer cancel()
+
+	if err := a.keyRing.Sync(ctx); err != nil {
+		return nil, fmt.Errorf("cannot init signing keys: %v", err)
+	}
+
+	format, err := auth.NewTokenFormat(a.cfg.Auth.TokenFormat, a.keyRing)
+	if err != nil {
+		return nil, err
 	}
 
-	return auth.NewRSAJWTProcessor(
-		rsa.PrivateKey, rsa.PublicKey,
+	if a.cfg.Auth.EncryptTokens {
+		if format, err = auth.NewEncryptedFormat(format, auth.NewAudienceKeys(repositories.vault)); err != nil {
+			return nil, err
+		}
+	}
+
+	return auth.NewJWTProcessor(
+		a.keyRing,
+		format,
+		repositories.authRdb,
 		a.cfg.Auth.Access,
 		a.cfg.Auth.Refresh,
 		a.cfg.Auth.Issuer,
 	), nil
 }
+
+func (a *Application) initSSHCA(repositories *repositories) (*auth.SSHCA, error) {
+	sshCA := auth.NewSSHCA(repositories.vault, a.cfg.Auth.CertExp)
+
+	ctx, cancel := context.WithTimeout(a.ctx, 30*time.Second)
+	defer cancel()
+
+	if err := sshCA.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	return sshCA, nil
+}
+
+func (a *Application) initX509CA(repositories *repositories) (*auth.X509CA, error) {
+	x509CA, err := auth.NewX509CA(
+		repositories.vault,
+		a.cfg.Auth.CertSecret,
+		a.cfg.Auth.CertExp,
+		a.cfg.Auth.TrustDomain,
+		strings.TrimRight(a.cfg.PublicServer.URL, "/")+vars.PathPKICRL,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, 30*time.Second)
+	defer cancel()
+
+	if err := x509CA.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	return x509CA, nil
+}
+
+func (a *Application) initSAMLIdP(repositories *repositories) (*auth.SAMLIdP, error) {
+	samlIdP := auth.NewSAMLIdP(repositories.vault, a.cfg.SAML.AssertionTTL)
+
+	ctx, cancel := context.WithTimeout(a.ctx, 30*time.Second)
+	defer cancel()
+
+	if err := samlIdP.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	return samlIdP, nil
+}
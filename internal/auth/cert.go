@@ -1 +1,196 @@
 package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// certClockSkew backdates certificates so that peers with a slightly
+	// late clock accept them right away.
+	certClockSkew = 5 * time.Minute
+
+	sshExtensionSign = "ssh-sign@polonium.ws"
+)
+
+var sshExtensions = map[string]string{
+	"permit-pty":              "",
+	"permit-user-rc":          "",
+	"permit-port-forwarding":  "",
+	"permit-agent-forwarding": "",
+}
+
+// SSHCA signs OpenSSH user certificates with an Ed25519 key kept in Vault.
+type SSHCA struct {
+	vault    repository.IAuthVault
+	validity time.Duration
+
+	mu     sync.RWMutex
+	signer ssh.Signer
+}
+
+func NewSSHCA(vault repository.IAuthVault, validity time.Duration) *SSHCA {
+	return &SSHCA{
+		vault:    vault,
+		validity: validity,
+	}
+}
+
+// Load reads the CA key from Vault and creates it on the first start.
+func (s *SSHCA) Load(ctx context.Context) error {
+	stored, err := s.vault.GetSSHCAKey(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot read ssh ca key: %v", err)
+	}
+
+	if stored == "" {
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return fmt.Errorf("cannot generate ssh ca key: %v", err)
+		}
+
+		der, err := x509.MarshalPKCS8PrivateKey(private)
+		if err != nil {
+			return fmt.Errorf("cannot marshal ssh ca key: %v", err)
+		}
+
+		stored = base64.StdEncoding.EncodeToString(der)
+		if putErr := s.vault.PutSSHCAKey(ctx, stored); putErr != nil {
+			// another instance may have created the key concurrently
+			if stored, err = s.vault.GetSSHCAKey(ctx); err != nil || stored == "" {
+				return fmt.Errorf("cannot store ssh ca key: %v", putErr)
+			}
+		}
+	}
+
+	der, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		return fmt.Errorf("cannot decode ssh ca key: %v", err)
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return fmt.Errorf("cannot parse ssh ca key: %v", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		return fmt.Errorf("cannot create ssh ca signer: %v", err)
+	}
+
+	s.mu.Lock()
+	s.signer = signer
+	s.mu.Unlock()
+
+	return nil
+}
+
+// PublicKey is the CA line for the TrustedUserCAKeys file of sshd.
+func (s *SSHCA) PublicKey() (string, error) {
+	signer, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	return string(ssh.MarshalAuthorizedKey(signer.PublicKey())), nil
+}
+
+// Sign issues a user certificate for the key. The principals are the user
+// email and deployer id. The key id names the user and an extension carries
+// a fingerprint of the user's ssh_sign, never the secret itself, so that
+// hosts can tell which account it was issued to.
+func (s *SSHCA) Sign(user *model.User, key ssh.PublicKey, sourceAddress []string) (*ssh.Certificate, error) {
+	signer, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := key.(*ssh.Certificate); ok {
+		return nil, fmt.Errorf("%w: key is already a certificate", vars.ErrInvalidPublicKey)
+	}
+
+	for _, cidr := range sourceAddress {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			if net.ParseIP(cidr) == nil {
+				return nil, fmt.Errorf("%w: %s", vars.ErrInvalidSourceAddress, cidr)
+			}
+		}
+	}
+
+	serial := make([]byte, 8)
+	if _, err := rand.Read(serial); err != nil {
+		return nil, fmt.Errorf("cannot generate certificate serial: %v", err)
+	}
+
+	criticalOptions := map[string]string{}
+	if len(sourceAddress) > 0 {
+		criticalOptions["source-address"] = strings.Join(sourceAddress, ",")
+	}
+
+	extensions := make(map[string]string, len(sshExtensions)+1)
+	for name, value := range sshExtensions {
+		extensions[name] = value
+	}
+	extensions[sshExtensionSign] = signFingerprint(user.SshSign)
+
+	principals := []string{user.Email}
+	if user.Deployer != "" {
+		principals = append(principals, user.Deployer)
+	}
+
+	now := time.Now()
+	cert := &ssh.Certificate{
+		Key:             key,
+		Serial:          binary.BigEndian.Uint64(serial),
+		CertType:        ssh.UserCert,
+		KeyId:           fmt.Sprintf("%s:%s", user.Email, user.Id),
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(now.Add(-certClockSkew).Unix()),
+		ValidBefore:     uint64(now.Add(s.validity).Unix()),
+		Permissions: ssh.Permissions{
+			CriticalOptions: criticalOptions,
+			Extensions:      extensions,
+		},
+	}
+
+	if err := cert.SignCert(rand.Reader, signer); err != nil {
+		return nil, fmt.Errorf("cannot sign certificate: %v", err)
+	}
+
+	return cert, nil
+}
+
+func (s *SSHCA) current() (ssh.Signer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.signer == nil {
+		return nil, errors.New("ssh ca is not loaded")
+	}
+
+	return s.signer, nil
+}
+
+// signFingerprint identifies the ssh_sign of a user without revealing it,
+// certificates being readable by every host they are shown to.
+func signFingerprint(sign string) string {
+	sum := sha256.Sum256([]byte(sign))
+
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"golang.org/x/crypto/ssh"
+)
+
+func newSSHKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestSSHCALoad(t *testing.T) {
+	vault := newMemVault()
+
+	first := NewSSHCA(vault, time.Hour)
+	if _, err := first.PublicKey(); err == nil {
+		t.Fatal("PublicKey() of a CA that is not loaded succeeded")
+	}
+
+	if err := first.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	second := NewSSHCA(vault, time.Hour)
+	if err := second.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	firstKey, _ := first.PublicKey()
+	secondKey, _ := second.PublicKey()
+	if firstKey != secondKey || !strings.HasPrefix(firstKey, ssh.KeyAlgoED25519+" ") {
+		t.Errorf("PublicKey() = %q and %q, want the same ed25519 key", firstKey, secondKey)
+	}
+}
+
+func TestSSHCASign(t *testing.T) {
+	ca := NewSSHCA(newMemVault(), time.Hour)
+	if err := ca.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	user := &model.User{
+		Email:   "user@polonium.ws",
+		Id:      "user-id",
+		SshSign: "0123456789abcdef0123456789abcdef",
+	}
+	deployer := *user
+	deployer.Deployer = "deployer-id"
+
+	key := newSSHKey(t)
+	issued, err := ca.Sign(user, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		user          *model.User
+		key           ssh.PublicKey
+		sourceAddress []string
+		principals    []string
+		wantErr       error
+	}{
+		{name: "user", user: user, key: key, principals: []string{"user@polonium.ws"}},
+		{name: "deployer", user: &deployer, key: key, principals: []string{"user@polonium.ws", "deployer-id"}},
+		{
+			name:          "source address",
+			user:          user,
+			key:           key,
+			sourceAddress: []string{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32"},
+			principals:    []string{"user@polonium.ws"},
+		},
+		{name: "certificate as key", user: user, key: issued, wantErr: vars.ErrInvalidPublicKey},
+		{name: "malformed source address", user: user, key: key, sourceAddress: []string{"10.0.0.0/33"}, wantErr: vars.ErrInvalidSourceAddress},
+		{name: "hostname as source address", user: user, key: key, sourceAddress: []string{"example.com"}, wantErr: vars.ErrInvalidSourceAddress},
+	}
+
+	caKey, _ := ca.PublicKey()
+	authority, _, _, _, err := ssh.ParseAuthorizedKey([]byte(caKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return string(auth.Marshal()) == string(authority.Marshal())
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, err := ca.Sign(tt.user, tt.key, tt.sourceAddress)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Sign() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			for _, principal := range tt.principals {
+				if err := checker.CheckCert(principal, cert); err != nil {
+					t.Errorf("CheckCert(%s) error = %v", principal, err)
+				}
+			}
+
+			if !slices.Equal(cert.ValidPrincipals, tt.principals) {
+				t.Errorf("principals = %v, want %v", cert.ValidPrincipals, tt.principals)
+			}
+
+			if got := cert.CriticalOptions["source-address"]; got != strings.Join(tt.sourceAddress, ",") {
+				t.Errorf("source-address = %q, want %v", got, tt.sourceAddress)
+			}
+
+			if cert.CertType != ssh.UserCert || time.Until(time.Unix(int64(cert.ValidBefore), 0)) > time.Hour {
+				t.Errorf("certificate type %d valid before %d", cert.CertType, cert.ValidBefore)
+			}
+
+			if _, ok := cert.Extensions["permit-pty"]; !ok {
+				t.Error("certificate does not permit a pty")
+			}
+
+			// the certificate is public, the ssh_sign of the user is not
+			if cert.Extensions[sshExtensionSign] != signFingerprint(user.SshSign) {
+				t.Errorf("%s = %q", sshExtensionSign, cert.Extensions[sshExtensionSign])
+			}
+			if strings.Contains(string(cert.Marshal()), user.SshSign[:8]) {
+				t.Error("certificate reveals the ssh_sign of the user")
+			}
+		})
+	}
+}
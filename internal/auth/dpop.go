@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+const (
+	dpopProofType  = "dpop+jwt"
+	dpopNonceBytes = 16
+)
+
+// DPoPAlgorithms are the asymmetric algorithms accepted for DPoP proofs.
+var DPoPAlgorithms = []string{
+	jwt.SigningMethodEdDSA.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodES384.Alg(),
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodPS256.Alg(),
+}
+
+type (
+	DPoPStore interface {
+		UseDPoPProof(jti string, ttl time.Duration) (bool, error)
+		NewDPoPNonce(nonce string, ttl time.Duration) error
+		IsDPoPNonceValid(nonce string) (bool, error)
+	}
+
+	DPoPClaims struct {
+		HTM   string `json:"htm"`
+		HTU   string `json:"htu"`
+		ATH   string `json:"ath,omitempty"`
+		Nonce string `json:"nonce,omitempty"`
+		jwt.RegisteredClaims
+	}
+
+	// DPoPVerifier checks DPoP proofs (RFC 9449) sent to the public server.
+	DPoPVerifier struct {
+		store            DPoPStore
+		baseURL          string
+		window, nonceTTL time.Duration
+		requireNonce     bool
+	}
+)
+
+func NewDPoPVerifier(
+	store DPoPStore,
+	baseURL string,
+	window, nonceTTL time.Duration,
+	requireNonce bool,
+) *DPoPVerifier {
+	return &DPoPVerifier{
+		store:        store,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		window:       window,
+		nonceTTL:     nonceTTL,
+		requireNonce: requireNonce,
+	}
+}
+
+// Verify checks the proof sent with a request to path and returns the
+// thumbprint of its key. accessToken is empty on the token endpoints.
+func (d *DPoPVerifier) Verify(proof, method, path, accessToken string) (string, error) {
+	var jwk jose.JSONWebKey
+
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if typ, _ := token.Header["typ"].(string); typ != dpopProofType {
+			return nil, fmt.Errorf("unexpected proof type: %v", token.Header["typ"])
+		}
+
+		raw, err := json.Marshal(token.Header["jwk"])
+		if err != nil {
+			return nil, err
+		}
+
+		if err := jwk.UnmarshalJSON(raw); err != nil {
+			return nil, fmt.Errorf("cannot parse proof key: %v", err)
+		}
+
+		if !jwk.Valid() || !jwk.IsPublic() {
+			return nil, errors.New("proof key must be a public key")
+		}
+
+		return jwk.Key, nil
+	}
+
+	claims := new(DPoPClaims)
+	if _, err := jwt.ParseWithClaims(proof, claims, keyFunc, jwt.WithValidMethods(DPoPAlgorithms)); err != nil {
+		return "", fmt.Errorf("%w: %v", vars.ErrInvalidDPoPProof, err)
+	}
+
+	if claims.ID == "" || claims.IssuedAt == nil {
+		return "", fmt.Errorf("%w: jti and iat are required", vars.ErrInvalidDPoPProof)
+	}
+
+	if claims.HTM != method {
+		return "", fmt.Errorf("%w: htm does not match", vars.ErrInvalidDPoPProof)
+	}
+
+	if !sameURL(claims.HTU, d.baseURL+path) {
+		return "", fmt.Errorf("%w: htu does not match", vars.ErrInvalidDPoPProof)
+	}
+
+	if age := time.Since(claims.IssuedAt.Time); age > d.window || age < -d.window {
+		return "", fmt.Errorf("%w: iat is outside of the accepted window", vars.ErrInvalidDPoPProof)
+	}
+
+	if accessToken != "" && claims.ATH != TokenHash(accessToken) {
+		return "", fmt.Errorf("%w: ath does not match", vars.ErrInvalidDPoPProof)
+	}
+
+	if d.requireNonce {
+		if claims.Nonce == "" {
+			return "", vars.ErrUseDPoPNonce
+		}
+
+		valid, err := d.store.IsDPoPNonceValid(claims.Nonce)
+		if err != nil {
+			return "", fmt.Errorf("cannot check dpop nonce: %v", err)
+		}
+
+		if !valid {
+			return "", vars.ErrUseDPoPNonce
+		}
+	}
+
+	thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", vars.ErrInvalidDPoPProof, err)
+	}
+
+	// a proof is only accepted inside the window, so it is enough to remember
+	// its jti for twice as long
+	fresh, err := d.store.UseDPoPProof(claims.ID, 2*d.window)
+	if err != nil {
+		return "", fmt.Errorf("cannot register dpop proof: %v", err)
+	}
+
+	if !fresh {
+		return "", vars.ErrDPoPProofReplayed
+	}
+
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
+
+// NewNonce issues a server nonce for clients to put into their next proofs.
+// It returns an empty nonce when nonces are not required.
+func (d *DPoPVerifier) NewNonce() (string, error) {
+	if !d.requireNonce {
+		return "", nil
+	}
+
+	raw := make([]byte, dpopNonceBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("cannot generate dpop nonce: %v", err)
+	}
+
+	nonce := base64.RawURLEncoding.EncodeToString(raw)
+	if err := d.store.NewDPoPNonce(nonce, d.nonceTTL); err != nil {
+		return "", fmt.Errorf("cannot store dpop nonce: %v", err)
+	}
+
+	return nonce, nil
+}
+
+// TokenHash is the `ath` value of a DPoP proof for the access token.
+func TokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// sameURL compares the URLs without their query and fragment.
+func sameURL(htu, expected string) bool {
+	got, err := url.Parse(htu)
+	if err != nil {
+		return false
+	}
+
+	want, err := url.Parse(expected)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(got.Scheme, want.Scheme) &&
+		strings.EqualFold(got.Host, want.Host) &&
+		got.EscapedPath() == want.EscapedPath()
+}
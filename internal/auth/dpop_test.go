@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+const dpopBaseURL = "https://auth.polonium.ws"
+
+type memDPoPStore struct {
+	mu     sync.Mutex
+	proofs map[string]bool
+	nonces map[string]bool
+}
+
+func newMemDPoPStore() *memDPoPStore {
+	return &memDPoPStore{proofs: map[string]bool{}, nonces: map[string]bool{}}
+}
+
+func (m *memDPoPStore) UseDPoPProof(jti string, _ time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.proofs[jti] {
+		return false, nil
+	}
+	m.proofs[jti] = true
+	return true, nil
+}
+
+func (m *memDPoPStore) NewDPoPNonce(nonce string, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nonces[nonce] = true
+	return nil
+}
+
+func (m *memDPoPStore) IsDPoPNonceValid(nonce string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nonces[nonce], nil
+}
+
+type dpopProof struct {
+	typ    string
+	jwk    any
+	claims DPoPClaims
+	method jwt.SigningMethod
+	key    any
+}
+
+func newDPoPProof(key *ecdsa.PrivateKey, method, path, jti string) *dpopProof {
+	claims := DPoPClaims{
+		HTM: method,
+		HTU: dpopBaseURL + path,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       jti,
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return &dpopProof{
+		typ:    dpopProofType,
+		jwk:    jose.JSONWebKey{Key: &key.PublicKey},
+		claims: claims,
+		method: jwt.SigningMethodES256,
+		key:    key,
+	}
+}
+
+func (p *dpopProof) sign(t *testing.T) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(p.method, p.claims)
+	token.Header["typ"] = p.typ
+	token.Header["jwk"] = p.jwk
+
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func TestDPoPVerify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thumbprint, err := (&jose.JSONWebKey{Key: &key.PublicKey}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jkt := base64.RawURLEncoding.EncodeToString(thumbprint)
+
+	const (
+		path  = "/ext-auth/api/v1/oauth/token"
+		token = "access-token"
+	)
+
+	tests := []struct {
+		name        string
+		accessToken string
+		edit        func(p *dpopProof)
+		err         error
+	}{
+		{name: "valid proof"},
+		{name: "valid proof for access token", accessToken: token, edit: func(p *dpopProof) {
+			p.claims.ATH = TokenHash(token)
+		}},
+		{name: "query and fragment are ignored", edit: func(p *dpopProof) {
+			p.claims.HTU += "?a=1#b"
+		}},
+		{name: "wrong type", edit: func(p *dpopProof) {
+			p.typ = "JWT"
+		}, err: vars.ErrInvalidDPoPProof},
+		{name: "private key in header", edit: func(p *dpopProof) {
+			p.jwk = jose.JSONWebKey{Key: p.key}
+		}, err: vars.ErrInvalidDPoPProof},
+		{name: "symmetric algorithm", edit: func(p *dpopProof) {
+			p.method, p.key = jwt.SigningMethodHS256, []byte("secret")
+		}, err: vars.ErrInvalidDPoPProof},
+		{name: "missing jti", edit: func(p *dpopProof) {
+			p.claims.ID = ""
+		}, err: vars.ErrInvalidDPoPProof},
+		{name: "wrong method", edit: func(p *dpopProof) {
+			p.claims.HTM = "GET"
+		}, err: vars.ErrInvalidDPoPProof},
+		{name: "wrong url", edit: func(p *dpopProof) {
+			p.claims.HTU = dpopBaseURL + "/ext-auth/api/v1/oauth/revoke"
+		}, err: vars.ErrInvalidDPoPProof},
+		{name: "wrong host", edit: func(p *dpopProof) {
+			p.claims.HTU = "https://evil.example" + path
+		}, err: vars.ErrInvalidDPoPProof},
+		{name: "issued too long ago", edit: func(p *dpopProof) {
+			p.claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+		}, err: vars.ErrInvalidDPoPProof},
+		{name: "issued in the future", edit: func(p *dpopProof) {
+			p.claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+		}, err: vars.ErrInvalidDPoPProof},
+		{name: "missing access token hash", accessToken: token, err: vars.ErrInvalidDPoPProof},
+		{name: "hash of another access token", accessToken: token, edit: func(p *dpopProof) {
+			p.claims.ATH = TokenHash("other-token")
+		}, err: vars.ErrInvalidDPoPProof},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := NewDPoPVerifier(newMemDPoPStore(), dpopBaseURL+"/", time.Minute, time.Minute, false)
+
+			proof := newDPoPProof(key, "POST", path, fmt.Sprintf("jti-%d", i))
+			if tt.edit != nil {
+				tt.edit(proof)
+			}
+
+			got, err := verifier.Verify(proof.sign(t), "POST", path, tt.accessToken)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+
+			if tt.err == nil && got != jkt {
+				t.Errorf("Verify() = %s, want %s", got, jkt)
+			}
+		})
+	}
+}
+
+func TestDPoPVerifyReplay(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewDPoPVerifier(newMemDPoPStore(), dpopBaseURL, time.Minute, time.Minute, false)
+	proof := newDPoPProof(key, "POST", "/token", "jti").sign(t)
+
+	if _, err := verifier.Verify(proof, "POST", "/token", ""); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+
+	if _, err := verifier.Verify(proof, "POST", "/token", ""); !errors.Is(err, vars.ErrDPoPProofReplayed) {
+		t.Fatalf("second use error = %v, want %v", err, vars.ErrDPoPProofReplayed)
+	}
+}
+
+func TestDPoPVerifyNonce(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewDPoPVerifier(newMemDPoPStore(), dpopBaseURL, time.Minute, time.Minute, true)
+	nonce, err := verifier.NewNonce()
+	if err != nil || nonce == "" {
+		t.Fatalf("NewNonce() = %q, %v", nonce, err)
+	}
+
+	tests := []struct {
+		name  string
+		nonce string
+		err   error
+	}{
+		{name: "issued nonce", nonce: nonce},
+		{name: "missing nonce", err: vars.ErrUseDPoPNonce},
+		{name: "unknown nonce", nonce: "unknown", err: vars.ErrUseDPoPNonce},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof := newDPoPProof(key, "POST", "/token", fmt.Sprintf("jti-%d", i))
+			proof.claims.Nonce = tt.nonce
+
+			if _, err := verifier.Verify(proof.sign(t), "POST", "/token", ""); !errors.Is(err, tt.err) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
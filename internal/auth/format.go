@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	TokenFormatJWT    = "jwt"
+	TokenFormatPASETO = "paseto"
+	TokenFormatJWE    = "jwe"
+)
+
+type (
+	// TokenFormat turns claims into a signed token and back. Decode only
+	// checks the signature, claims are validated by the JWTProcessor.
+	TokenFormat interface {
+		Name() string
+		Encode(claims *CustomClaims) (string, error)
+		Decode(token string) (*CustomClaims, error)
+	}
+
+	jwtFormat struct {
+		keys KeyProvider
+	}
+)
+
+func NewTokenFormat(name string, keys KeyProvider) (TokenFormat, error) {
+	switch name {
+	case TokenFormatJWT:
+		return &jwtFormat{keys: keys}, nil
+	case TokenFormatPASETO:
+		// v4.public is defined for Ed25519 only
+		if keys.Algorithm() != jwt.SigningMethodEdDSA.Alg() {
+			return nil, fmt.Errorf("paseto v4.public requires EdDSA keys, got %s", keys.Algorithm())
+		}
+		return &pasetoFormat{keys: keys}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported token format: %s", name)
+}
+
+func (f *jwtFormat) Name() string {
+	return TokenFormatJWT
+}
+
+func (f *jwtFormat) Encode(claims *CustomClaims) (string, error) {
+	return signJWT(f.keys, claims)
+}
+
+func (f *jwtFormat) Decode(tokenString string) (*CustomClaims, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token has no key id")
+		}
+
+		key, err := f.keys.VerificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
+		}
+		return key.Public, nil
+	}
+
+	token, err := jwt.ParseWithClaims(
+		tokenString, &CustomClaims{}, keyFunc,
+		jwt.WithValidMethods([]string{f.keys.Algorithm()}),
+		jwt.WithoutClaimsValidation(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*CustomClaims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
+	}
+
+	return claims, nil
+}
+
+func signJWT(keys KeyProvider, claims jwt.Claims) (string, error) {
+	key, err := keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Kid
+	return token.SignedString(key.Private)
+}
@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+)
+
+type (
+	// memVault keeps the signing key sets, the audience keys, the secrets
+	// of the CAs and the SAML key in memory and counts the reads of the
+	// signing key sets.
+	memVault struct {
+		repository.IAuthVault
+
+		mu             sync.Mutex
+		keys           map[string]*model.SigningKeySet
+		versions       map[string]int
+		reads          atomic.Int32
+		encryptionKeys map[string]*model.EncryptionKey
+		sshCAKey       string
+		x509CA         *model.X509CA
+		samlKey        *model.SAMLKey
+	}
+
+	// denylist is a Denylist over a set of revoked token ids.
+	denylist struct {
+		revoked map[string]bool
+		err     error
+	}
+
+	// staticKeys is a KeyProvider with a single Ed25519 key.
+	staticKeys struct {
+		key *Key
+	}
+)
+
+func newMemVault() *memVault {
+	return &memVault{
+		keys:           map[string]*model.SigningKeySet{},
+		versions:       map[string]int{},
+		encryptionKeys: map[string]*model.EncryptionKey{},
+	}
+}
+
+func (m *memVault) GetSigningKeys(_ context.Context, alg string) (*model.SigningKeySet, int, error) {
+	m.reads.Add(1)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	set := &model.SigningKeySet{}
+	if stored, ok := m.keys[alg]; ok {
+		set.Keys = append(set.Keys, stored.Keys...)
+	}
+	return set, m.versions[alg], nil
+}
+
+func (m *memVault) PutSigningKeys(_ context.Context, alg string, set *model.SigningKeySet, version int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if version != m.versions[alg] {
+		return errors.New("check-and-set failed")
+	}
+	m.keys[alg], m.versions[alg] = set, version+1
+	return nil
+}
+
+func (m *memVault) GetEncryptionKey(_ context.Context, audience string) (*model.EncryptionKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.encryptionKeys[audience], nil
+}
+
+func (m *memVault) PutEncryptionKey(_ context.Context, audience string, key *model.EncryptionKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.encryptionKeys[audience] = key
+	return nil
+}
+
+func (m *memVault) GetSSHCAKey(context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sshCAKey, nil
+}
+
+func (m *memVault) PutSSHCAKey(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sshCAKey != "" {
+		return errors.New("check-and-set failed")
+	}
+	m.sshCAKey = key
+	return nil
+}
+
+func (m *memVault) GetX509CA(context.Context) (*model.X509CA, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.x509CA, nil
+}
+
+func (m *memVault) PutX509CA(_ context.Context, ca *model.X509CA) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.x509CA != nil {
+		return errors.New("check-and-set failed")
+	}
+	m.x509CA = ca
+	return nil
+}
+
+func (m *memVault) GetSAMLKey(context.Context) (*model.SAMLKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.samlKey, nil
+}
+
+func (m *memVault) PutSAMLKey(_ context.Context, key *model.SAMLKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.samlKey != nil {
+		return errors.New("check-and-set failed")
+	}
+	m.samlKey = key
+	return nil
+}
+
+func newDenylist(revoked ...string) *denylist {
+	d := &denylist{revoked: map[string]bool{}}
+	for _, jti := range revoked {
+		d.revoked[jti] = true
+	}
+
+	return d
+}
+
+func (d *denylist) IsTokenRevoked(jti string) (bool, error) {
+	return d.revoked[jti], d.err
+}
+
+func newStaticKeys(t *testing.T) *staticKeys {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &staticKeys{key: &Key{
+		Kid:     "test-kid",
+		Method:  jwt.SigningMethodEdDSA,
+		Private: private,
+		Public:  public,
+	}}
+}
+
+func (s *staticKeys) Algorithm() string {
+	return jwt.SigningMethodEdDSA.Alg()
+}
+
+func (s *staticKeys) SigningKey() (*Key, error) {
+	return s.key, nil
+}
+
+func (s *staticKeys) VerificationKey(kid string) (*Key, error) {
+	if kid != s.key.Kid {
+		return nil, errors.New("unknown key")
+	}
+	return s.key, nil
+}
+
+func (s *staticKeys) PublicKeys() []jose.JSONWebKey {
+	return []jose.JSONWebKey{{Key: s.key.Public, KeyID: s.key.Kid}}
+}
+
+func (s *staticKeys) CacheTTL() time.Duration {
+	return time.Minute
+}
+
+func testClaims(audience ...string) *CustomClaims {
+	now := time.Now()
+	return &CustomClaims{
+		UserID:   "user-id",
+		Email:    "user@polonium.ws",
+		Session:  "session",
+		ClientID: "client",
+		Scope:    "openid",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti",
+			Audience:  audience,
+			Issuer:    "issuer",
+			Subject:   "access",
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+)
+
+type (
+	// IDTokenClaims is an OpenID Connect ID token. ID tokens are always
+	// JWTs, whatever the access token format is.
+	IDTokenClaims struct {
+		Email         string           `json:"email,omitempty"`
+		EmailVerified *bool            `json:"email_verified,omitempty"`
+		Deployer      string           `json:"deployer,omitempty"`
+		AuthTime      *jwt.NumericDate `json:"auth_time,omitempty"`
+		Nonce         string           `json:"nonce,omitempty"`
+		AMR           []string         `json:"amr,omitempty"`
+		ACR           string           `json:"acr,omitempty"`
+		AZP           string           `json:"azp,omitempty"`
+		jwt.RegisteredClaims
+	}
+
+	IDTokenParams struct {
+		ClientID string
+		Nonce    string
+		AuthTime time.Time
+		AMR      []string
+		ACR      string
+	}
+)
+
+// GenerateIDToken signs the user claims, already filtered by scope, for
+// the client in params.
+func (j *JWTProcessor) GenerateIDToken(info *model.UserInfo, params *IDTokenParams) (string, error) {
+	now := time.Now()
+	claims := IDTokenClaims{
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Deployer:      info.Deployer,
+		AuthTime:      jwt.NewNumericDate(params.AuthTime),
+		Nonce:         params.Nonce,
+		AMR:           params.AMR,
+		ACR:           params.ACR,
+		AZP:           params.ClientID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Audience:  jwt.ClaimStrings{params.ClientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.access)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    j.issuer,
+			Subject:   info.Sub,
+		},
+	}
+
+	return signJWT(j.keys, &claims)
+}
@@ -0,0 +1,297 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+// headerAudience replicates the audience into the JWE header (RFC 7519,
+// section 5.3) so that the matching key can be found before decryption.
+const headerAudience jose.HeaderKey = "aud"
+
+type (
+	EncryptionKeyProvider interface {
+		// EncryptionKey returns the key pair of the audience, creating it
+		// when the audience has none yet.
+		EncryptionKey(audience string) (*jose.JSONWebKey, error)
+		// DecryptionKey never creates keys for unknown audiences.
+		DecryptionKey(audience string) (*jose.JSONWebKey, error)
+	}
+
+	// AudienceKeys keeps one P-256 key pair per audience in Vault. The
+	// audience service reads its private key from the same Vault path.
+	AudienceKeys struct {
+		vault repository.IAuthVault
+
+		mu         sync.RWMutex
+		byAudience map[string]*jose.JSONWebKey
+	}
+
+	// encryptedFormat signs with the inner JWT format and encrypts the
+	// result to the token audience with ECDH-ES and A256GCM.
+	encryptedFormat struct {
+		inner TokenFormat
+		keys  EncryptionKeyProvider
+	}
+)
+
+func NewAudienceKeys(vault repository.IAuthVault) *AudienceKeys {
+	return &AudienceKeys{
+		vault:      vault,
+		byAudience: map[string]*jose.JSONWebKey{},
+	}
+}
+
+func (a *AudienceKeys) EncryptionKey(audience string) (*jose.JSONWebKey, error) {
+	return a.key(audience, true)
+}
+
+func (a *AudienceKeys) DecryptionKey(audience string) (*jose.JSONWebKey, error) {
+	return a.key(audience, false)
+}
+
+func (a *AudienceKeys) key(audience string, create bool) (*jose.JSONWebKey, error) {
+	a.mu.RLock()
+	key, ok := a.byAudience[audience]
+	a.mu.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stored, err := a.vault.GetEncryptionKey(ctx, audience)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read encryption key: %v", err)
+	}
+
+	if stored == nil && !create {
+		return nil, fmt.Errorf("no encryption key for audience %s", audience)
+	}
+
+	if stored == nil {
+		if stored, err = newEncryptionKey(audience); err != nil {
+			return nil, err
+		}
+
+		if err := a.vault.PutEncryptionKey(ctx, audience, stored); err != nil {
+			// another instance may have created the key concurrently
+			if stored, err = a.vault.GetEncryptionKey(ctx, audience); err != nil || stored == nil {
+				return nil, fmt.Errorf("cannot store encryption key: %v", err)
+			}
+		}
+	}
+
+	if key, err = parseEncryptionKey(stored); err != nil {
+		return nil, fmt.Errorf("cannot parse encryption key of %s: %v", audience, err)
+	}
+
+	a.mu.Lock()
+	a.byAudience[audience] = key
+	a.mu.Unlock()
+
+	return key, nil
+}
+
+// NewEncryptedFormat wraps a JWT format so that every token is encrypted to
+// its audience. Tokens granted several audiences are encrypted to the first.
+func NewEncryptedFormat(inner TokenFormat, keys EncryptionKeyProvider) (TokenFormat, error) {
+	if inner.Name() != TokenFormatJWT {
+		return nil, fmt.Errorf("encrypted tokens require the %s format, got %s", TokenFormatJWT, inner.Name())
+	}
+
+	return &encryptedFormat{
+		inner: inner,
+		keys:  keys,
+	}, nil
+}
+
+func (f *encryptedFormat) Name() string {
+	return TokenFormatJWE
+}
+
+// Encode encrypts the token to a single audience. A token granted several
+// audiences is narrowed to the first one, which is the requested audience
+// or else the primary audience of the client.
+func (f *encryptedFormat) Encode(claims *CustomClaims) (string, error) {
+	if len(claims.Audience) == 0 {
+		return "", fmt.Errorf("%w: encrypted tokens must name an audience", vars.ErrInvalidAudience)
+	}
+
+	if len(claims.Audience) > 1 {
+		narrowed := *claims
+		narrowed.Audience = claims.Audience[:1]
+		claims = &narrowed
+	}
+
+	signed, err := f.inner.Encode(claims)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := f.keys.EncryptionKey(claims.Audience[0])
+	if err != nil {
+		return "", err
+	}
+
+	return Encrypt(signed, claims.Audience[0], key)
+}
+
+func (f *encryptedFormat) Decode(token string) (*CustomClaims, error) {
+	encrypted, err := parseEncrypted(token)
+	if err != nil {
+		return nil, err
+	}
+
+	audience, _ := encrypted.Header.ExtraHeaders[headerAudience].(string)
+	if audience == "" {
+		return nil, errors.New("encrypted token has no audience header")
+	}
+
+	key, err := f.keys.DecryptionKey(audience)
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := decrypt(encrypted, key)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := f.inner.Decode(signed)
+	if err != nil {
+		return nil, err
+	}
+
+	// the unprotected copy of the audience must not differ from the signed one
+	if len(claims.Audience) != 1 || claims.Audience[0] != audience {
+		return nil, errors.New("encrypted token audience does not match its claims")
+	}
+
+	return claims, nil
+}
+
+// Encrypt wraps a signed token into a compact JWE for the audience key.
+func Encrypt(signed, audience string, key *jose.JSONWebKey) (string, error) {
+	encrypter, err := jose.NewEncrypter(
+		jose.A256GCM,
+		jose.Recipient{Algorithm: jose.ECDH_ES, Key: key.Public().Key, KeyID: key.KeyID},
+		(&jose.EncrypterOptions{}).WithContentType("JWT").WithHeader(headerAudience, audience),
+	)
+	if err != nil {
+		return "", fmt.Errorf("cannot create encrypter: %v", err)
+	}
+
+	encrypted, err := encrypter.Encrypt([]byte(signed))
+	if err != nil {
+		return "", fmt.Errorf("cannot encrypt token: %v", err)
+	}
+
+	return encrypted.CompactSerialize()
+}
+
+// Decrypt returns the signed token inside a compact JWE. Consumers holding
+// the private audience key verify the result as a regular JWT.
+func Decrypt(token string, key *jose.JSONWebKey) (string, error) {
+	encrypted, err := parseEncrypted(token)
+	if err != nil {
+		return "", err
+	}
+
+	return decrypt(encrypted, key)
+}
+
+func parseEncrypted(token string) (*jose.JSONWebEncryption, error) {
+	if strings.Count(token, ".") != 4 {
+		return nil, errors.New("token is not encrypted")
+	}
+
+	encrypted, err := jose.ParseEncrypted(
+		token,
+		[]jose.KeyAlgorithm{jose.ECDH_ES},
+		[]jose.ContentEncryption{jose.A256GCM},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse encrypted token: %v", err)
+	}
+
+	return encrypted, nil
+}
+
+func decrypt(encrypted *jose.JSONWebEncryption, key *jose.JSONWebKey) (string, error) {
+	if encrypted.Header.KeyID != key.KeyID {
+		return "", fmt.Errorf("unknown encryption key: %s", encrypted.Header.KeyID)
+	}
+
+	signed, err := encrypted.Decrypt(key.Key)
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt token: %v", err)
+	}
+
+	return string(signed), nil
+}
+
+func newEncryptionKey(audience string) (*model.EncryptionKey, error) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate encryption key: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal encryption key: %v", err)
+	}
+
+	thumbprint, err := (&jose.JSONWebKey{Key: &private.PublicKey}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute encryption key id: %v", err)
+	}
+
+	return &model.EncryptionKey{
+		Kid:       base64.RawURLEncoding.EncodeToString(thumbprint),
+		Audience:  audience,
+		Private:   base64.StdEncoding.EncodeToString(der),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func parseEncryptionKey(stored *model.EncryptionKey) (*jose.JSONWebKey, error) {
+	der, err := base64.StdEncoding.DecodeString(stored.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	ecPrivate, ok := private.(*ecdsa.PrivateKey)
+	if !ok || ecPrivate.Curve != elliptic.P256() {
+		return nil, errors.New("encryption key is not a P-256 key")
+	}
+
+	return &jose.JSONWebKey{
+		Key:       ecPrivate,
+		KeyID:     stored.Kid,
+		Algorithm: string(jose.ECDH_ES),
+		Use:       "enc",
+	}, nil
+}
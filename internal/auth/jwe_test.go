@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+func TestEncryptedFormat(t *testing.T) {
+	format, err := NewEncryptedFormat(&jwtFormat{keys: newStaticKeys(t)}, NewAudienceKeys(newMemVault()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		audience []string
+		want     []string
+		err      error
+	}{
+		{name: "single audience", audience: []string{"api"}, want: []string{"api"}},
+		{name: "several audiences narrowed to the first", audience: []string{"api", "other"}, want: []string{"api"}},
+		{name: "no audience", err: vars.ErrInvalidAudience},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := format.Encode(testClaims(tt.audience...))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Encode() error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			claims, err := format.Decode(token)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if !slices.Equal(claims.Audience, tt.want) {
+				t.Errorf("audience = %v, want %v", claims.Audience, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncryptedFormatRejectsOtherFormats(t *testing.T) {
+	if _, err := NewEncryptedFormat(&pasetoFormat{keys: newStaticKeys(t)}, NewAudienceKeys(newMemVault())); err == nil {
+		t.Fatal("paseto inner format accepted")
+	}
+}
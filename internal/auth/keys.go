@@ -0,0 +1,394 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/pkg/utils"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	rsaKeyBits        = 2048
+	hmacKeyBytes      = 32
+	keyReloadInterval = 10 * time.Second
+)
+
+var supportedAlgorithms = map[string]jwt.SigningMethod{
+	jwt.SigningMethodEdDSA.Alg(): jwt.SigningMethodEdDSA,
+	jwt.SigningMethodES256.Alg(): jwt.SigningMethodES256,
+	jwt.SigningMethodRS256.Alg(): jwt.SigningMethodRS256,
+	jwt.SigningMethodHS256.Alg(): jwt.SigningMethodHS256,
+}
+
+type (
+	KeyProvider interface {
+		Algorithm() string
+		SigningKey() (*Key, error)
+		VerificationKey(kid string) (*Key, error)
+		PublicKeys() []jose.JSONWebKey
+		CacheTTL() time.Duration
+	}
+
+	Key struct {
+		Kid     string
+		Method  jwt.SigningMethod
+		Private interface{}
+		Public  interface{}
+
+		activeFrom time.Time
+		expiresAt  time.Time
+	}
+
+	// KeyRing keeps the signing keys shared by all instances in Vault.
+	// A successor key is published `lead` before it starts signing, and a
+	// replaced key stays valid for verification during the `grace` window.
+	KeyRing struct {
+		vault                 repository.IAuthVault
+		method                jwt.SigningMethod
+		rotation, lead, grace time.Duration
+
+		mu         sync.RWMutex
+		keys       []*Key
+		byKid      map[string]*Key
+		lastReload time.Time
+		reloads    singleflight.Group
+	}
+)
+
+func NewKeyRing(
+	vault repository.IAuthVault,
+	alg string,
+	rotation, lead, grace time.Duration,
+) (*KeyRing, error) {
+	method, ok := supportedAlgorithms[alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	return &KeyRing{
+		vault:    vault,
+		method:   method,
+		rotation: rotation,
+		lead:     lead,
+		grace:    grace,
+		byKid:    map[string]*Key{},
+	}, nil
+}
+
+func (k *KeyRing) Algorithm() string {
+	return k.method.Alg()
+}
+
+// Sync rotates the key set stored in Vault when it is due and reloads it.
+func (k *KeyRing) Sync(ctx context.Context) error {
+	set, version, err := k.vault.GetSigningKeys(ctx, k.Algorithm())
+	if err != nil {
+		return fmt.Errorf("cannot read signing keys: %v", err)
+	}
+
+	now := time.Now()
+	next, changed, err := k.plan(set, now)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		if err := k.vault.PutSigningKeys(ctx, k.Algorithm(), next, version); err != nil {
+			// another instance may have rotated the set concurrently
+			log.Warn().Err(err).Msg("cannot store signing keys, reloading")
+			return k.reload(ctx)
+		}
+	}
+
+	return k.load(next, now)
+}
+
+func (k *KeyRing) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Sync(ctx); err != nil {
+				log.Err(err).Msg("cannot sync jwt signing keys")
+			}
+		}
+	}
+}
+
+// CacheTTL is how long a consumer may cache the public keys and still see
+// a successor key before it starts signing.
+func (k *KeyRing) CacheTTL() time.Duration {
+	return k.lead / 2
+}
+
+// PublicKeys lists the published key, the signing key and every key that
+// is still in its grace window. HMAC secrets are never published.
+func (k *KeyRing) PublicKeys() []jose.JSONWebKey {
+	if _, ok := k.method.(*jwt.SigningMethodHMAC); ok {
+		return []jose.JSONWebKey{}
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]jose.JSONWebKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		if !key.expiresAt.IsZero() && now.After(key.expiresAt) {
+			continue
+		}
+
+		keys = append(keys, jose.JSONWebKey{
+			Key:       key.Public,
+			KeyID:     key.Kid,
+			Algorithm: key.Method.Alg(),
+			Use:       "sig",
+		})
+	}
+
+	return keys
+}
+
+func (k *KeyRing) SigningKey() (*Key, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := time.Now()
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if !k.keys[i].activeFrom.After(now) {
+			return k.keys[i], nil
+		}
+	}
+
+	return nil, errors.New("no active signing key")
+}
+
+func (k *KeyRing) VerificationKey(kid string) (*Key, error) {
+	k.mu.RLock()
+	key, ok := k.byKid[kid]
+	stale := time.Since(k.lastReload) > keyReloadInterval
+	k.mu.RUnlock()
+
+	if !ok && stale {
+		if err := k.reloadStale(); err != nil {
+			return nil, err
+		}
+
+		k.mu.RLock()
+		key, ok = k.byKid[kid]
+		k.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	if !key.expiresAt.IsZero() && time.Now().After(key.expiresAt) {
+		return nil, fmt.Errorf("signing key expired: %s", kid)
+	}
+
+	return key, nil
+}
+
+// reloadStale reloads the key set once for all callers that missed a kid
+// at the same time, and not again until keyReloadInterval has passed.
+func (k *KeyRing) reloadStale() error {
+	_, err, _ := k.reloads.Do("reload", func() (interface{}, error) {
+		k.mu.RLock()
+		stale := time.Since(k.lastReload) > keyReloadInterval
+		k.mu.RUnlock()
+
+		if !stale {
+			return nil, nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		return nil, k.reload(ctx)
+	})
+
+	return err
+}
+
+func (k *KeyRing) reload(ctx context.Context) error {
+	set, _, err := k.vault.GetSigningKeys(ctx, k.Algorithm())
+	if err != nil {
+		return fmt.Errorf("cannot read signing keys: %v", err)
+	}
+
+	return k.load(set, time.Now())
+}
+
+func (k *KeyRing) plan(set *model.SigningKeySet, now time.Time) (*model.SigningKeySet, bool, error) {
+	keys := make([]model.SigningKey, 0, len(set.Keys)+1)
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].ActiveFrom.Before(set.Keys[j].ActiveFrom)
+	})
+
+	changed := false
+	for i, key := range set.Keys {
+		if i+1 < len(set.Keys) && now.After(set.Keys[i+1].ActiveFrom.Add(k.grace)) {
+			changed = true
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	var activeFrom time.Time
+	switch {
+	case len(keys) == 0:
+		activeFrom = now
+	case keys[len(keys)-1].ActiveFrom.After(now):
+		// successor is already published
+	case !now.Before(keys[len(keys)-1].ActiveFrom.Add(k.rotation - k.lead)):
+		activeFrom = keys[len(keys)-1].ActiveFrom.Add(k.rotation)
+		if activeFrom.Before(now.Add(k.lead)) {
+			activeFrom = now.Add(k.lead)
+		}
+	}
+
+	if !activeFrom.IsZero() {
+		key, err := k.newKey(now, activeFrom)
+		if err != nil {
+			return nil, false, err
+		}
+		keys = append(keys, *key)
+		changed = true
+	}
+
+	return &model.SigningKeySet{Keys: keys}, changed, nil
+}
+
+func (k *KeyRing) load(set *model.SigningKeySet, now time.Time) error {
+	keys := make([]*Key, 0, len(set.Keys))
+	byKid := make(map[string]*Key, len(set.Keys))
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].ActiveFrom.Before(set.Keys[j].ActiveFrom)
+	})
+
+	for i, stored := range set.Keys {
+		key, err := k.parseKey(&stored)
+		if err != nil {
+			return fmt.Errorf("cannot parse signing key %s: %v", stored.Kid, err)
+		}
+
+		if i+1 < len(set.Keys) {
+			key.expiresAt = set.Keys[i+1].ActiveFrom.Add(k.grace)
+		}
+
+		keys = append(keys, key)
+		byKid[key.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return errors.New("signing key set is empty")
+	}
+
+	k.mu.Lock()
+	k.keys, k.byKid, k.lastReload = keys, byKid, now
+	k.mu.Unlock()
+
+	return nil
+}
+
+func (k *KeyRing) newKey(now, activeFrom time.Time) (*model.SigningKey, error) {
+	var (
+		private interface{}
+		err     error
+	)
+
+	switch k.method {
+	case jwt.SigningMethodEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case jwt.SigningMethodES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case jwt.SigningMethodRS256:
+		var pair *model.RSAKeyPair
+		if pair, err = utils.GenerateRSAKeys(rsaKeyBits); err == nil {
+			private = pair.PrivateKey
+		}
+	case jwt.SigningMethodHS256:
+		secret := make([]byte, hmacKeyBytes)
+		_, err = rand.Read(secret)
+		private = secret
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate signing key: %v", err)
+	}
+
+	der, ok := private.([]byte)
+	if !ok {
+		if der, err = x509.MarshalPKCS8PrivateKey(private); err != nil {
+			return nil, fmt.Errorf("cannot marshal signing key: %v", err)
+		}
+	}
+
+	return &model.SigningKey{
+		Kid:        strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Alg:        k.Algorithm(),
+		Private:    base64.StdEncoding.EncodeToString(der),
+		CreatedAt:  now,
+		ActiveFrom: activeFrom,
+	}, nil
+}
+
+func (k *KeyRing) parseKey(stored *model.SigningKey) (*Key, error) {
+	if stored.Alg != k.Algorithm() {
+		return nil, fmt.Errorf("unexpected signing algorithm: %s", stored.Alg)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(stored.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		Kid:        stored.Kid,
+		Method:     k.method,
+		activeFrom: stored.ActiveFrom,
+	}
+
+	if _, ok := k.method.(*jwt.SigningMethodHMAC); ok {
+		key.Private, key.Public = der, der
+		return key, nil
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot sign")
+	}
+
+	key.Private, key.Public = private, signer.Public()
+	return key, nil
+}
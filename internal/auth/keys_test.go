@@ -0,0 +1,279 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+)
+
+const (
+	testRotation = 24 * time.Hour
+	testLead     = time.Hour
+	testGrace    = 2 * time.Hour
+)
+
+func newTestKeyRing(t *testing.T, vault *memVault, alg string) *KeyRing {
+	t.Helper()
+
+	ring, err := NewKeyRing(vault, alg, testRotation, testLead, testGrace)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ring
+}
+
+// storedKey generates a key for the ring that starts signing at activeFrom.
+func storedKey(t *testing.T, ring *KeyRing, activeFrom time.Time) model.SigningKey {
+	t.Helper()
+
+	key, err := ring.newKey(activeFrom, activeFrom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return *key
+}
+
+func TestKeyRingPlan(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// keys are the activation times of the stored keys
+		keys []time.Time
+		// kept are the indexes of the stored keys left in the plan
+		kept        []int
+		changed     bool
+		successorAt time.Time
+	}{
+		{
+			name:        "empty set starts signing now",
+			changed:     true,
+			successorAt: now,
+		},
+		{
+			name: "inside rotation",
+			keys: []time.Time{now.Add(-time.Hour)},
+			kept: []int{0},
+		},
+		{
+			name:        "inside lead publishes the successor at rotation",
+			keys:        []time.Time{now.Add(-testRotation + testLead)},
+			kept:        []int{0},
+			changed:     true,
+			successorAt: now.Add(testLead),
+		},
+		{
+			name:        "overdue rotation still publishes lead ahead",
+			keys:        []time.Time{now.Add(-testRotation - testLead + 30*time.Minute)},
+			kept:        []int{0},
+			changed:     true,
+			successorAt: now.Add(testLead),
+		},
+		{
+			name: "successor already published",
+			keys: []time.Time{now.Add(-testRotation + 30*time.Minute), now.Add(30 * time.Minute)},
+			kept: []int{0, 1},
+		},
+		{
+			name: "replaced key inside grace",
+			keys: []time.Time{now.Add(-testRotation - time.Hour), now.Add(-time.Hour)},
+			kept: []int{0, 1},
+		},
+		{
+			name:    "replaced key past grace is pruned",
+			keys:    []time.Time{now.Add(-testRotation - 3*time.Hour), now.Add(-3 * time.Hour)},
+			kept:    []int{1},
+			changed: true,
+		},
+		{
+			name:    "keys replaced before the previous one are pruned",
+			keys:    []time.Time{now.Add(-3 * testRotation), now.Add(-2 * testRotation), now.Add(-time.Hour)},
+			kept:    []int{1, 2},
+			changed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring := newTestKeyRing(t, newMemVault(), "EdDSA")
+
+			set := &model.SigningKeySet{}
+			for _, activeFrom := range tt.keys {
+				set.Keys = append(set.Keys, storedKey(t, ring, activeFrom))
+			}
+			stored := append([]model.SigningKey(nil), set.Keys...)
+
+			next, changed, err := ring.plan(set, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if changed != tt.changed {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+
+			want := len(tt.kept)
+			if !tt.successorAt.IsZero() {
+				want++
+			}
+			if len(next.Keys) != want {
+				t.Fatalf("got %d keys, want %d", len(next.Keys), want)
+			}
+
+			for i, idx := range tt.kept {
+				if next.Keys[i].Kid != stored[idx].Kid {
+					t.Errorf("key %d = %s, want stored key %d", i, next.Keys[i].Kid, idx)
+				}
+			}
+
+			if !tt.successorAt.IsZero() {
+				successor := next.Keys[len(next.Keys)-1]
+				if !successor.ActiveFrom.Equal(tt.successorAt) {
+					t.Errorf("successor active from %s, want %s", successor.ActiveFrom, tt.successorAt)
+				}
+				if _, err := ring.parseKey(&successor); err != nil {
+					t.Errorf("successor does not parse: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyRingSync(t *testing.T) {
+	vault := newMemVault()
+	ring := newTestKeyRing(t, vault, "EdDSA")
+
+	if err := ring.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ring.SigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a second instance picks up the same key instead of rotating again
+	other := newTestKeyRing(t, vault, "EdDSA")
+	if err := other.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := other.SigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if otherKey.Kid != key.Kid || vault.versions["EdDSA"] != 1 {
+		t.Errorf("second sync signs with %s at version %d, want %s at version 1", otherKey.Kid, vault.versions["EdDSA"], key.Kid)
+	}
+}
+
+func TestKeyRingLookupGrace(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		replaced time.Duration
+		wantErr  bool
+	}{
+		{name: "inside grace", replaced: testGrace - time.Minute},
+		{name: "past grace", replaced: testGrace + time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring := newTestKeyRing(t, newMemVault(), "EdDSA")
+			old := storedKey(t, ring, now.Add(-testRotation))
+			current := storedKey(t, ring, now.Add(-tt.replaced))
+
+			if err := ring.load(&model.SigningKeySet{Keys: []model.SigningKey{old, current}}, now); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := ring.VerificationKey(current.Kid); err != nil {
+				t.Errorf("current key: %v", err)
+			}
+
+			_, err := ring.VerificationKey(old.Kid)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("replaced key error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyRingPublicKeys(t *testing.T) {
+	now := time.Now()
+	ring := newTestKeyRing(t, newMemVault(), "ES256")
+	expired := storedKey(t, ring, now.Add(-2*testRotation))
+	replaced := storedKey(t, ring, now.Add(-testRotation))
+	current := storedKey(t, ring, now.Add(-testGrace-time.Minute))
+	successor := storedKey(t, ring, now.Add(testLead))
+
+	if err := ring.load(&model.SigningKeySet{Keys: []model.SigningKey{expired, replaced, current, successor}}, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// the replaced key has been out of its grace window for a minute
+	want := []string{current.Kid, successor.Kid}
+
+	keys := ring.PublicKeys()
+	if len(keys) != len(want) {
+		t.Fatalf("published %d keys, want %d", len(keys), len(want))
+	}
+
+	for i, key := range keys {
+		if key.KeyID != want[i] {
+			t.Errorf("key %d = %s, want %s", i, key.KeyID, want[i])
+		}
+		if !key.IsPublic() || key.Use != "sig" || key.Algorithm != "ES256" {
+			t.Errorf("key %s is not a public ES256 signing key", key.KeyID)
+		}
+	}
+}
+
+func TestKeyRingLookupReloadsOnce(t *testing.T) {
+	vault := newMemVault()
+	ring := newTestKeyRing(t, vault, "EdDSA")
+
+	if err := ring.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// another instance rotated in a key this one has not seen yet
+	rotated := storedKey(t, ring, time.Now())
+	vault.keys["EdDSA"].Keys = append(vault.keys["EdDSA"].Keys, rotated)
+
+	ring.lastReload = time.Now().Add(-2 * keyReloadInterval)
+	reads := vault.reads.Load()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ring.VerificationKey(rotated.Kid); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := vault.reads.Load() - reads; got != 1 {
+		t.Errorf("vault read %d times, want once", got)
+	}
+
+	// an unknown kid right after a reload does not hit the vault again
+	if _, err := ring.VerificationKey("unknown"); err == nil {
+		t.Error("unknown kid was accepted")
+	}
+
+	if got := vault.reads.Load() - reads; got != 1 {
+		t.Errorf("vault read %d times after an unknown kid, want once", got)
+	}
+}
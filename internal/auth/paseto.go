@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const pasetoV4PublicHeader = "v4.public."
+
+// PASETO registered claims hold times as RFC 3339 strings while
+// CustomClaims keeps the numeric dates of JWT.
+var pasetoTimeClaims = []string{"exp", "nbf", "iat"}
+
+type (
+	// pasetoFormat issues PASETO v4.public tokens. The key id travels in the
+	// footer so that rotated keys keep verifying.
+	pasetoFormat struct {
+		keys KeyProvider
+	}
+
+	pasetoFooter struct {
+		Kid string `json:"kid"`
+	}
+)
+
+func (f *pasetoFormat) Name() string {
+	return TokenFormatPASETO
+}
+
+func (f *pasetoFormat) Encode(claims *CustomClaims) (string, error) {
+	key, err := f.keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	private, ok := key.Private.(ed25519.PrivateKey)
+	if !ok {
+		return "", errors.New("paseto signing key is not an ed25519 key")
+	}
+
+	message, err := pasetoMarshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal claims: %v", err)
+	}
+
+	footer, err := json.Marshal(pasetoFooter{Kid: key.Kid})
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal footer: %v", err)
+	}
+
+	signature := ed25519.Sign(private, pae([]byte(pasetoV4PublicHeader), message, footer, nil))
+
+	return pasetoV4PublicHeader +
+		base64.RawURLEncoding.EncodeToString(append(message, signature...)) + "." +
+		base64.RawURLEncoding.EncodeToString(footer), nil
+}
+
+func (f *pasetoFormat) Decode(token string) (*CustomClaims, error) {
+	if !strings.HasPrefix(token, pasetoV4PublicHeader) {
+		return nil, errors.New("token is not a paseto v4.public token")
+	}
+
+	parts := strings.Split(token[len(pasetoV4PublicHeader):], ".")
+	if len(parts) != 2 {
+		return nil, errors.New("token has no footer")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(payload) <= ed25519.SignatureSize {
+		return nil, errors.New("malformed token payload")
+	}
+
+	footer, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed token footer")
+	}
+
+	var meta pasetoFooter
+	if err := json.Unmarshal(footer, &meta); err != nil || meta.Kid == "" {
+		return nil, errors.New("token has no key id")
+	}
+
+	key, err := f.keys.VerificationKey(meta.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	public, ok := key.Public.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("paseto verification key is not an ed25519 key")
+	}
+
+	split := len(payload) - ed25519.SignatureSize
+	message, signature := payload[:split], payload[split:]
+	if !ed25519.Verify(public, pae([]byte(pasetoV4PublicHeader), message, footer, nil), signature) {
+		return nil, errors.New("token signature is invalid")
+	}
+
+	claims := new(CustomClaims)
+	if err := pasetoUnmarshal(message, claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %v", err)
+	}
+
+	return claims, nil
+}
+
+// pae is the pre-authentication encoding of the PASETO specification.
+func pae(pieces ...[]byte) []byte {
+	out := binary.LittleEndian.AppendUint64(nil, uint64(len(pieces))&(1<<63-1))
+	for _, piece := range pieces {
+		out = binary.LittleEndian.AppendUint64(out, uint64(len(piece))&(1<<63-1))
+		out = append(out, piece...)
+	}
+
+	return out
+}
+
+func pasetoMarshal(claims *CustomClaims) ([]byte, error) {
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	for _, name := range pasetoTimeClaims {
+		if seconds, ok := fields[name].(float64); ok {
+			fields[name] = time.Unix(int64(seconds), 0).UTC().Format(time.RFC3339)
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+func pasetoUnmarshal(message []byte, claims *CustomClaims) error {
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(message, &fields); err != nil {
+		return err
+	}
+
+	for _, name := range pasetoTimeClaims {
+		value, ok := fields[name].(string)
+		if !ok {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("claim %s is not an RFC 3339 time: %v", name, err)
+		}
+		fields[name] = t.Unix()
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, claims)
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/pkg/verifier"
+)
+
+func TestPAE(t *testing.T) {
+	// test vectors of the PASETO specification, docs/01-Protocol-Versions/Common.md,
+	// shared with the tests of pkg/verifier
+	tests := []struct {
+		name   string
+		pieces [][]byte
+		want   string
+	}{
+		{name: "no pieces", want: "0000000000000000"},
+		{name: "one empty piece", pieces: [][]byte{{}}, want: "0100000000000000" + "0000000000000000"},
+		{name: "two empty pieces", pieces: [][]byte{{}, {}}, want: "0200000000000000" + "0000000000000000" + "0000000000000000"},
+		{
+			name:   "one piece",
+			pieces: [][]byte{[]byte("Paragon")},
+			want:   "0100000000000000" + "0700000000000000" + hex.EncodeToString([]byte("Paragon")),
+		},
+		{
+			name:   "two pieces",
+			pieces: [][]byte{[]byte("Paragon"), []byte("Initiative")},
+			want: "0200000000000000" +
+				"0700000000000000" + hex.EncodeToString([]byte("Paragon")) +
+				"0a00000000000000" + hex.EncodeToString([]byte("Initiative")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hex.EncodeToString(pae(tt.pieces...)); got != tt.want {
+				t.Errorf("pae() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPASETOFormat(t *testing.T) {
+	format := &pasetoFormat{keys: newStaticKeys(t)}
+
+	token, err := format.Encode(testClaims("api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(token, pasetoV4PublicHeader) {
+		t.Fatalf("token %s is not v4.public", token)
+	}
+
+	claims, err := format.Decode(token)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if claims.UserID != "user-id" || !slices.Equal(claims.Audience, []string{"api"}) || claims.ExpiresAt == nil {
+		t.Errorf("Decode() = %+v", claims)
+	}
+
+	parts := strings.Split(token, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "other version", token: strings.Replace(token, "v4.public.", "v3.public.", 1)},
+		{name: "local purpose", token: strings.Replace(token, "v4.public.", "v4.local.", 1)},
+		{name: "tampered payload", token: "v4.public." + base64.RawURLEncoding.EncodeToString(
+			bytes.Replace(payload, []byte("user-id"), []byte("user-ID"), 1),
+		) + "." + parts[3]},
+		{name: "tampered footer", token: strings.Join(parts[:3], ".") + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(`{"kid":"other"}`))},
+		{name: "no footer", token: strings.Join(parts[:3], ".")},
+		{name: "truncated", token: "v4.public." + base64.RawURLEncoding.EncodeToString(payload[:32]) + "." + parts[3]},
+		{name: "not base64", token: "v4.public.%%%." + parts[3]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := format.Decode(tt.token); err == nil {
+				t.Fatal("Decode() accepted an invalid token")
+			}
+		})
+	}
+}
+
+// TestPASETOVerifier checks that the verifier SDK, which has its own PASETO
+// decoder, accepts the tokens issued here.
+func TestPASETOVerifier(t *testing.T) {
+	keys := newStaticKeys(t)
+	jp := NewJWTProcessor(keys, &pasetoFormat{keys: keys}, newDenylist(), time.Minute, time.Hour, "issuer")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(jp.JWKS())
+	}))
+	defer srv.Close()
+
+	v, err := verifier.New(verifier.Config{BaseURL: srv.URL, Issuer: "issuer", Audience: "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := jp.GenerateAccessToken(
+		&model.User{Id: "user-id", Deployer: "deployer"},
+		&model.Session{ID: "session", Audience: []string{"api"}, Scope: "read"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := v.Verify(context.Background(), token, "read")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if claims.UserID != "user-id" || claims.Deployer != "deployer" || claims.Session != "session" {
+		t.Errorf("Verify() = %+v", claims)
+	}
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"regexp"
+)
+
+// A verifier is 43 to 128 unreserved characters (RFC 7636, section 4.1),
+// an S256 challenge is the 43 characters of an encoded SHA-256 hash.
+var (
+	pkceVerifier  = regexp.MustCompile(`^[A-Za-z0-9._~-]{43,128}$`)
+	pkceChallenge = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+)
+
+func IsCodeChallengeValid(challenge string) bool {
+	return pkceChallenge.MatchString(challenge)
+}
+
+// CodeChallenge derives the S256 challenge of a verifier.
+func CodeChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// VerifyCodeVerifier checks the verifier against an S256 challenge.
+func VerifyCodeVerifier(challenge, verifier string) bool {
+	if !pkceVerifier.MatchString(verifier) {
+		return false
+	}
+
+	computed := CodeChallenge(verifier)
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+// verifier and challenge of RFC 7636, appendix B
+const (
+	rfcVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	rfcChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func TestCodeChallenge(t *testing.T) {
+	if got := CodeChallenge(rfcVerifier); got != rfcChallenge {
+		t.Errorf("CodeChallenge() = %s, want %s", got, rfcChallenge)
+	}
+}
+
+func TestIsCodeChallengeValid(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge string
+		want      bool
+	}{
+		{name: "s256", challenge: rfcChallenge, want: true},
+		{name: "empty", challenge: ""},
+		{name: "too short", challenge: rfcChallenge[:42]},
+		{name: "too long", challenge: rfcChallenge + "A"},
+		{name: "padded", challenge: rfcChallenge[:42] + "="},
+		{name: "standard base64", challenge: rfcChallenge[:42] + "+"},
+		{name: "plain verifier", challenge: rfcVerifier + "AAAA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCodeChallengeValid(tt.challenge); got != tt.want {
+				t.Errorf("IsCodeChallengeValid(%q) = %t, want %t", tt.challenge, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCodeVerifier(t *testing.T) {
+	long := strings.Repeat("a", 128)
+
+	tests := []struct {
+		name      string
+		challenge string
+		verifier  string
+		want      bool
+	}{
+		{name: "rfc 7636 example", challenge: rfcChallenge, verifier: rfcVerifier, want: true},
+		{name: "longest verifier", challenge: CodeChallenge(long), verifier: long, want: true},
+		{name: "unreserved characters", challenge: CodeChallenge(strings.Repeat("a.b_c~d-", 6)), verifier: strings.Repeat("a.b_c~d-", 6), want: true},
+		{name: "other verifier", challenge: rfcChallenge, verifier: strings.Repeat("a", 43)},
+		{name: "plain method", challenge: rfcVerifier, verifier: rfcVerifier},
+		{name: "empty verifier", challenge: CodeChallenge(""), verifier: ""},
+		{name: "too short", challenge: CodeChallenge(rfcVerifier[:42]), verifier: rfcVerifier[:42]},
+		{name: "too long", challenge: CodeChallenge(long + "a"), verifier: long + "a"},
+		{name: "reserved character", challenge: CodeChallenge(rfcVerifier + "/"), verifier: rfcVerifier + "/"},
+		{name: "empty challenge", challenge: "", verifier: rfcVerifier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyCodeVerifier(tt.challenge, tt.verifier); got != tt.want {
+				t.Errorf("VerifyCodeVerifier() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
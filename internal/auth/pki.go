@@ -0,0 +1,459 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+const (
+	caValidity  = 10 * 365 * 24 * time.Hour
+	crlValidity = 15 * time.Minute
+
+	caKeyInfo = "polonium x509 ca key"
+)
+
+// Revocation reasons from RFC 5280, section 5.3.1.
+const (
+	RevocationUnspecified          = 0
+	RevocationKeyCompromise        = 1
+	RevocationSuperseded           = 4
+	RevocationCessationOfOperation = 5
+)
+
+var (
+	RevocationReasons = map[string]int{
+		"unspecified":            RevocationUnspecified,
+		"key_compromise":         RevocationKeyCompromise,
+		"superseded":             RevocationSuperseded,
+		"cessation_of_operation": RevocationCessationOfOperation,
+	}
+
+	spiffeSegment = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// X509CA issues short-lived mTLS client certificates to deployment agents.
+// The CA key is kept in Vault sealed with the cert secret.
+type X509CA struct {
+	vault       repository.IAuthVault
+	sealKey     []byte
+	validity    time.Duration
+	trustDomain string
+	crlURL      string
+
+	mu   sync.RWMutex
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func NewX509CA(
+	vault repository.IAuthVault,
+	secret string,
+	validity time.Duration,
+	trustDomain, crlURL string,
+) (*X509CA, error) {
+	if secret == "" {
+		return nil, errors.New("cert secret is required")
+	}
+
+	if !spiffeSegment.MatchString(trustDomain) {
+		return nil, fmt.Errorf("invalid spiffe trust domain: %s", trustDomain)
+	}
+
+	sealKey, err := hkdf.Key(sha256.New, []byte(secret), nil, caKeyInfo, 32)
+	if err != nil {
+		return nil, fmt.Errorf("cannot derive ca seal key: %v", err)
+	}
+
+	return &X509CA{
+		vault:       vault,
+		sealKey:     sealKey,
+		validity:    validity,
+		trustDomain: trustDomain,
+		crlURL:      crlURL,
+	}, nil
+}
+
+// Load reads the CA from Vault and creates it on the first start.
+func (x *X509CA) Load(ctx context.Context) error {
+	stored, err := x.vault.GetX509CA(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot read x509 ca: %v", err)
+	}
+
+	if stored == nil {
+		if stored, err = x.newCA(); err != nil {
+			return err
+		}
+
+		if putErr := x.vault.PutX509CA(ctx, stored); putErr != nil {
+			// another instance may have created the CA concurrently
+			if stored, err = x.vault.GetX509CA(ctx); err != nil || stored == nil {
+				return fmt.Errorf("cannot store x509 ca: %v", putErr)
+			}
+		}
+	}
+
+	der, err := base64.StdEncoding.DecodeString(stored.Certificate)
+	if err != nil {
+		return fmt.Errorf("cannot decode x509 ca certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return fmt.Errorf("cannot parse x509 ca certificate: %v", err)
+	}
+
+	key, err := x.open(stored.Private)
+	if err != nil {
+		return fmt.Errorf("cannot open x509 ca key: %v", err)
+	}
+
+	public, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !public.Equal(cert.PublicKey) {
+		return errors.New("x509 ca key does not match its certificate")
+	}
+
+	x.mu.Lock()
+	x.cert, x.key = cert, key
+	x.mu.Unlock()
+
+	return nil
+}
+
+// Certificate returns the CA certificate in PEM.
+func (x *X509CA) Certificate() ([]byte, error) {
+	cert, _, err := x.current()
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), nil
+}
+
+// SpiffeID is the identity the deployment agent presents over mTLS.
+func (x *X509CA) SpiffeID(deployer, deployment string) (string, error) {
+	if !spiffeSegment.MatchString(deployer) || !spiffeSegment.MatchString(deployment) {
+		return "", fmt.Errorf("%w: deployer and deployment cannot be used in a spiffe id", vars.ErrInvalidCSR)
+	}
+
+	return (&url.URL{
+		Scheme: "spiffe",
+		Host:   x.trustDomain,
+		Path:   fmt.Sprintf("/deployer/%s/deployment/%s", deployer, deployment),
+	}).String(), nil
+}
+
+// Issue signs the CSR of a deployment agent. The CSR may only ask for the
+// identity of the deployment: its id as common name, its SPIFFE ID and its
+// ip address.
+func (x *X509CA) Issue(csrPEM string, deployment *model.Deployment) (*x509.Certificate, error) {
+	caCert, caKey, err := x.current()
+	if err != nil {
+		return nil, err
+	}
+
+	csr, err := parseCSR(csrPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	spiffeID, err := x.SpiffeID(deployment.Deployer, deployment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkCSRIdentity(csr, deployment, spiffeID); err != nil {
+		return nil, err
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	uri, _ := url.Parse(spiffeID)
+	now := time.Now()
+	notAfter := now.Add(x.validity)
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:         deployment.ID,
+			OrganizationalUnit: []string{deployment.Deployer},
+		},
+		URIs:                  []*url.URL{uri},
+		IPAddresses:           csr.IPAddresses,
+		NotBefore:             now.Add(-certClockSkew),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		CRLDistributionPoints: []string{x.crlURL},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, csr.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign certificate: %v", err)
+	}
+
+	return x509.ParseCertificate(der)
+}
+
+// CRL builds a DER encoded revocation list valid for crlValidity.
+func (x *X509CA) CRL(revoked []model.DeploymentCertificate) ([]byte, time.Time, error) {
+	caCert, caKey, err := x.current()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	entries := make([]x509.RevocationListEntry, 0, len(revoked))
+	for _, cert := range revoked {
+		serial, ok := new(big.Int).SetString(cert.Serial, 16)
+		if !ok || cert.RevokedAt == nil {
+			continue
+		}
+
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: *cert.RevokedAt,
+			ReasonCode:     cert.RevocationReason,
+		})
+	}
+
+	now := time.Now()
+	nextUpdate := now.Add(crlValidity)
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(now.UnixNano()),
+		ThisUpdate:                now,
+		NextUpdate:                nextUpdate,
+		RevokedCertificateEntries: entries,
+	}, caCert, caKey)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("cannot sign crl: %v", err)
+	}
+
+	return crl, nextUpdate, nil
+}
+
+// StatusValidity is how long a certificate status answer may be cached,
+// the same as a CRL.
+func (x *X509CA) StatusValidity() time.Duration {
+	return crlValidity
+}
+
+func (x *X509CA) current() (*x509.Certificate, crypto.Signer, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if x.cert == nil {
+		return nil, nil, errors.New("x509 ca is not loaded")
+	}
+
+	return x.cert, x.key, nil
+}
+
+func (x *X509CA) newCA() (*model.X509CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate x509 ca key: %v", err)
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   "polonium deployment CA",
+			Organization: []string{x.trustDomain},
+		},
+		NotBefore:             now.Add(-certClockSkew),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create x509 ca certificate: %v", err)
+	}
+
+	sealed, err := x.seal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.X509CA{
+		Certificate: base64.StdEncoding.EncodeToString(der),
+		Private:     sealed,
+		CreatedAt:   now,
+	}, nil
+}
+
+func (x *X509CA) seal(key crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal x509 ca key: %v", err)
+	}
+
+	gcm, err := x.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("cannot generate nonce: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, der, nil)), nil
+}
+
+func (x *X509CA) open(sealed string) (crypto.Signer, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := x.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) < gcm.NonceSize() {
+		return nil, errors.New("sealed key is too short")
+	}
+
+	der, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("wrong cert secret or corrupted key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("ca key cannot sign")
+	}
+
+	return signer, nil
+}
+
+func (x *X509CA) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(x.sealKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// SerialString is the form certificate serials are stored and looked up in.
+func SerialString(serial *big.Int) string {
+	return hex.EncodeToString(serial.Bytes())
+}
+
+func parseCSR(csrPEM string) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("%w: pem encoded request expected", vars.ErrInvalidCSR)
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", vars.ErrInvalidCSR, err)
+	}
+
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("%w: %v", vars.ErrInvalidCSR, err)
+	}
+
+	switch key := csr.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() && key.Curve != elliptic.P384() {
+			return nil, fmt.Errorf("%w: unsupported curve", vars.ErrInvalidCSR)
+		}
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("%w: rsa keys must have at least 2048 bits", vars.ErrInvalidCSR)
+		}
+	case ed25519.PublicKey:
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type", vars.ErrInvalidCSR)
+	}
+
+	return csr, nil
+}
+
+func checkCSRIdentity(csr *x509.CertificateRequest, deployment *model.Deployment, spiffeID string) error {
+	if cn := csr.Subject.CommonName; cn != "" && cn != deployment.ID {
+		return fmt.Errorf("%w: common name must be the deployment id", vars.ErrInvalidCSR)
+	}
+
+	if len(csr.DNSNames) > 0 || len(csr.EmailAddresses) > 0 {
+		return fmt.Errorf("%w: dns and email names are not allowed", vars.ErrInvalidCSR)
+	}
+
+	for _, uri := range csr.URIs {
+		if uri.String() != spiffeID {
+			return fmt.Errorf("%w: uri %s does not match %s", vars.ErrInvalidCSR, uri, spiffeID)
+		}
+	}
+
+	deploymentIP := net.ParseIP(deployment.IP)
+	for _, ip := range csr.IPAddresses {
+		if deploymentIP == nil || !bytes.Equal(ip.To16(), deploymentIP.To16()) {
+			return fmt.Errorf("%w: ip %s does not match the deployment", vars.ErrInvalidCSR, ip)
+		}
+	}
+
+	return nil
+}
+
+func newSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate certificate serial: %v", err)
+	}
+
+	// a zero serial is not allowed
+	return serial.Add(serial, big.NewInt(1)), nil
+}
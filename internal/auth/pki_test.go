@@ -0,0 +1,261 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+const testSpiffeID = "spiffe://polonium.ws/deployer/deployer-id/deployment/deployment-id"
+
+var testDeployment = &model.Deployment{ID: "deployment-id", Deployer: "deployer-id", IP: "10.0.0.1"}
+
+func newCSR(t *testing.T, key crypto.Signer, template *x509.CertificateRequest) []byte {
+	t.Helper()
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return der
+}
+
+func csrPEM(der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func newECKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func newRSAKey(t *testing.T, bits int) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestParseCSR(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := newCSR(t, edKey, &x509.CertificateRequest{})
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		csr     string
+		wantErr bool
+	}{
+		{name: "p256", csr: csrPEM(newCSR(t, newECKey(t, elliptic.P256()), &x509.CertificateRequest{}))},
+		{name: "p384", csr: csrPEM(newCSR(t, newECKey(t, elliptic.P384()), &x509.CertificateRequest{}))},
+		{name: "ed25519", csr: csrPEM(newCSR(t, edKey, &x509.CertificateRequest{}))},
+		{name: "rsa 2048", csr: csrPEM(newCSR(t, newRSAKey(t, 2048), &x509.CertificateRequest{}))},
+		{name: "p521", csr: csrPEM(newCSR(t, newECKey(t, elliptic.P521()), &x509.CertificateRequest{})), wantErr: true},
+		{name: "rsa 1024", csr: csrPEM(newCSR(t, newRSAKey(t, 1024), &x509.CertificateRequest{})), wantErr: true},
+		{name: "bad signature", csr: csrPEM(tampered), wantErr: true},
+		{name: "not pem", csr: "csr", wantErr: true},
+		{
+			name:    "certificate pem",
+			csr:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: newCSR(t, edKey, &x509.CertificateRequest{})})),
+			wantErr: true,
+		},
+		{name: "malformed der", csr: csrPEM([]byte("csr")), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCSR(tt.csr)
+			if tt.wantErr {
+				if !errors.Is(err, vars.ErrInvalidCSR) {
+					t.Fatalf("parseCSR() error = %v, want %v", err, vars.ErrInvalidCSR)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCSR() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckCSRIdentity(t *testing.T) {
+	spiffeID, _ := url.Parse(testSpiffeID)
+	otherID, _ := url.Parse("spiffe://polonium.ws/deployer/deployer-id/deployment/other")
+	noIP := *testDeployment
+	noIP.IP = ""
+
+	tests := []struct {
+		name       string
+		csr        *x509.CertificateRequest
+		deployment *model.Deployment
+		wantErr    bool
+	}{
+		{name: "empty", csr: &x509.CertificateRequest{}},
+		{
+			name: "full identity",
+			csr: &x509.CertificateRequest{
+				Subject:     pkix.Name{CommonName: "deployment-id"},
+				URIs:        []*url.URL{spiffeID},
+				IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+			},
+		},
+		{name: "other common name", csr: &x509.CertificateRequest{Subject: pkix.Name{CommonName: "other"}}, wantErr: true},
+		{name: "dns name", csr: &x509.CertificateRequest{DNSNames: []string{"polonium.ws"}}, wantErr: true},
+		{name: "email", csr: &x509.CertificateRequest{EmailAddresses: []string{"user@polonium.ws"}}, wantErr: true},
+		{name: "other spiffe id", csr: &x509.CertificateRequest{URIs: []*url.URL{otherID}}, wantErr: true},
+		{name: "other ip", csr: &x509.CertificateRequest{IPAddresses: []net.IP{net.ParseIP("10.0.0.2")}}, wantErr: true},
+		{
+			name:       "ip of a deployment without one",
+			csr:        &x509.CertificateRequest{IPAddresses: []net.IP{net.ParseIP("10.0.0.1")}},
+			deployment: &noIP,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := tt.deployment
+			if deployment == nil {
+				deployment = testDeployment
+			}
+
+			err := checkCSRIdentity(tt.csr, deployment, testSpiffeID)
+			if tt.wantErr != errors.Is(err, vars.ErrInvalidCSR) {
+				t.Fatalf("checkCSRIdentity() error = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewX509CA(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      string
+		trustDomain string
+		wantErr     bool
+	}{
+		{name: "valid", secret: "secret", trustDomain: "polonium.ws"},
+		{name: "no secret", trustDomain: "polonium.ws", wantErr: true},
+		{name: "trust domain with a path", secret: "secret", trustDomain: "polonium.ws/ca", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewX509CA(newMemVault(), tt.secret, time.Hour, tt.trustDomain, "https://polonium.ws/crl")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewX509CA() error = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestX509CA(t *testing.T) {
+	vault := newMemVault()
+	ca, err := NewX509CA(vault, "secret", time.Hour, "polonium.ws", "https://polonium.ws/crl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ca.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// the key is sealed with the cert secret
+	other, _ := NewX509CA(vault, "other secret", time.Hour, "polonium.ws", "https://polonium.ws/crl")
+	if err := other.Load(context.Background()); err == nil {
+		t.Fatal("Load() opened the ca key with another secret")
+	}
+
+	caPEM, err := ca.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caPEM)
+
+	key := newECKey(t, elliptic.P256())
+	cert, err := ca.Issue(csrPEM(newCSR(t, key, &x509.CertificateRequest{
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+	})), testDeployment)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	if cert.Subject.CommonName != "deployment-id" || len(cert.URIs) != 1 || cert.URIs[0].String() != testSpiffeID {
+		t.Errorf("certificate identity = %s %v", cert.Subject.CommonName, cert.URIs)
+	}
+
+	if !cert.PublicKey.(*ecdsa.PublicKey).Equal(key.Public()) || time.Until(cert.NotAfter) > time.Hour {
+		t.Errorf("certificate key or validity do not match the request")
+	}
+
+	if _, err := ca.Issue(csrPEM(newCSR(t, key, &x509.CertificateRequest{
+		DNSNames: []string{"polonium.ws"},
+	})), testDeployment); !errors.Is(err, vars.ErrInvalidCSR) {
+		t.Errorf("Issue() error = %v, want %v", err, vars.ErrInvalidCSR)
+	}
+
+	revokedAt := time.Now()
+	der, _, err := ca.CRL([]model.DeploymentCertificate{{
+		Serial:           SerialString(cert.SerialNumber),
+		RevokedAt:        &revokedAt,
+		RevocationReason: RevocationKeyCompromise,
+	}})
+	if err != nil {
+		t.Fatalf("CRL() error = %v", err)
+	}
+
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caCert, _, _ := ca.current()
+	if err := crl.CheckSignatureFrom(caCert); err != nil {
+		t.Errorf("CRL signature error = %v", err)
+	}
+
+	if len(crl.RevokedCertificateEntries) != 1 ||
+		crl.RevokedCertificateEntries[0].SerialNumber.Cmp(cert.SerialNumber) != 0 ||
+		crl.RevokedCertificateEntries[0].ReasonCode != RevocationKeyCompromise {
+		t.Errorf("CRL entries = %+v", crl.RevokedCertificateEntries)
+	}
+}
@@ -0,0 +1,497 @@
+package auth
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+const (
+	samlMaxRequest = 64 << 10
+	samlTimeFormat = "2006-01-02T15:04:05Z"
+
+	samlProtocolNS  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	samlAssertionNS = "urn:oasis:names:tc:SAML:2.0:assertion"
+	samlMetadataNS  = "urn:oasis:names:tc:SAML:2.0:metadata"
+	xmldsigNS       = "http://www.w3.org/2000/09/xmldsig#"
+
+	samlBindingRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	samlBindingPOST     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlNameIDEmail     = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	samlStatusSuccess   = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlBearer          = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	samlAttrNameBasic   = "urn:oasis:names:tc:SAML:2.0:attrname-format:basic"
+	samlMFA             = "https://refeds.org/profile/mfa"
+
+	xmlExcC14N      = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	xmlEnveloped    = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	xmlRSASHA256    = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	xmlDigestSHA256 = "http://www.w3.org/2001/04/xmlenc#sha256"
+)
+
+type (
+	// SAMLIdP signs SAML 2.0 assertions with an RSA key and a self-signed
+	// certificate kept in Vault, which service providers pin from the
+	// metadata.
+	SAMLIdP struct {
+		vault    repository.IAuthVault
+		validity time.Duration
+
+		mu   sync.RWMutex
+		cert *x509.Certificate
+		key  *rsa.PrivateKey
+	}
+
+	// SAMLAuthnRequest is the part of an AuthnRequest the IdP acts on.
+	SAMLAuthnRequest struct {
+		ID           string
+		Issuer       string
+		ACSURL       string
+		Destination  string
+		IssueInstant time.Time
+	}
+
+	// SAMLAssertion describes the login to assert to a service provider.
+	SAMLAssertion struct {
+		Issuer       string
+		Audience     string
+		Recipient    string
+		InResponseTo string
+		NameID       string
+		SessionIndex string
+		AuthnInstant time.Time
+		// AuthnContext is the authentication context class, the REFEDS MFA
+		// profile by default since users log in with a password and a TOTP
+		// code.
+		AuthnContext string
+		Attributes   []SAMLAttribute
+	}
+
+	SAMLAttribute struct {
+		Name   string
+		Values []string
+	}
+
+	samlAuthnRequest struct {
+		XMLName                     xml.Name  `xml:"urn:oasis:names:tc:SAML:2.0:protocol AuthnRequest"`
+		ID                          string    `xml:"ID,attr"`
+		Version                     string    `xml:"Version,attr"`
+		IssueInstant                time.Time `xml:"IssueInstant,attr"`
+		Destination                 string    `xml:"Destination,attr"`
+		AssertionConsumerServiceURL string    `xml:"AssertionConsumerServiceURL,attr"`
+		ProtocolBinding             string    `xml:"ProtocolBinding,attr"`
+		Issuer                      string    `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	}
+
+	samlEntityDescriptor struct {
+		XMLName          xml.Name             `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+		EntityID         string               `xml:"entityID,attr"`
+		IDPSSODescriptor samlIDPSSODescriptor `xml:"IDPSSODescriptor"`
+	}
+
+	samlIDPSSODescriptor struct {
+		ProtocolSupportEnumeration string            `xml:"protocolSupportEnumeration,attr"`
+		WantAuthnRequestsSigned    bool              `xml:"WantAuthnRequestsSigned,attr"`
+		KeyDescriptor              samlKeyDescriptor `xml:"KeyDescriptor"`
+		NameIDFormat               string            `xml:"NameIDFormat"`
+		SingleSignOnServices       []samlEndpoint    `xml:"SingleSignOnService"`
+	}
+
+	samlKeyDescriptor struct {
+		Use     string      `xml:"use,attr"`
+		KeyInfo samlKeyInfo `xml:"http://www.w3.org/2000/09/xmldsig# KeyInfo"`
+	}
+
+	samlKeyInfo struct {
+		X509Certificate string `xml:"X509Data>X509Certificate"`
+	}
+
+	samlEndpoint struct {
+		Binding  string `xml:"Binding,attr"`
+		Location string `xml:"Location,attr"`
+	}
+)
+
+func NewSAMLIdP(vault repository.IAuthVault, validity time.Duration) *SAMLIdP {
+	return &SAMLIdP{
+		vault:    vault,
+		validity: validity,
+	}
+}
+
+// Load reads the signing key from Vault and creates it on the first start.
+func (s *SAMLIdP) Load(ctx context.Context) error {
+	stored, err := s.vault.GetSAMLKey(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot read saml key: %v", err)
+	}
+
+	if stored == nil {
+		if stored, err = newSAMLKey(); err != nil {
+			return err
+		}
+
+		if putErr := s.vault.PutSAMLKey(ctx, stored); putErr != nil {
+			// another instance may have created the key concurrently
+			if stored, err = s.vault.GetSAMLKey(ctx); err != nil || stored == nil {
+				return fmt.Errorf("cannot store saml key: %v", putErr)
+			}
+		}
+	}
+
+	der, err := base64.StdEncoding.DecodeString(stored.Certificate)
+	if err != nil {
+		return fmt.Errorf("cannot decode saml certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return fmt.Errorf("cannot parse saml certificate: %v", err)
+	}
+
+	der, err = base64.StdEncoding.DecodeString(stored.Private)
+	if err != nil {
+		return fmt.Errorf("cannot decode saml key: %v", err)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return fmt.Errorf("cannot parse saml key: %v", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok || !key.PublicKey.Equal(cert.PublicKey) {
+		return errors.New("saml key does not match its certificate")
+	}
+
+	s.mu.Lock()
+	s.cert, s.key = cert, key
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Metadata is the IdP entity descriptor of a tenant.
+func (s *SAMLIdP) Metadata(entityID, ssoURL string) ([]byte, error) {
+	cert, _, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := xml.MarshalIndent(samlEntityDescriptor{
+		EntityID: entityID,
+		IDPSSODescriptor: samlIDPSSODescriptor{
+			ProtocolSupportEnumeration: samlProtocolNS,
+			KeyDescriptor: samlKeyDescriptor{
+				Use: "signing",
+				KeyInfo: samlKeyInfo{
+					X509Certificate: base64.StdEncoding.EncodeToString(cert.Raw),
+				},
+			},
+			NameIDFormat: samlNameIDEmail,
+			SingleSignOnServices: []samlEndpoint{
+				{Binding: samlBindingRedirect, Location: ssoURL},
+				{Binding: samlBindingPOST, Location: ssoURL},
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal saml metadata: %v", err)
+	}
+
+	return append([]byte(xml.Header), metadata...), nil
+}
+
+// ParseAuthnRequest decodes the SAMLRequest parameter. Requests sent with
+// the HTTP-Redirect binding are deflated, the ones sent with HTTP-POST are
+// not.
+func ParseAuthnRequest(encoded string, deflated bool) (*SAMLAuthnRequest, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", vars.ErrInvalidSAMLRequest, err)
+	}
+
+	if deflated {
+		if raw, err = io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(raw)), samlMaxRequest+1)); err != nil {
+			return nil, fmt.Errorf("%w: %v", vars.ErrInvalidSAMLRequest, err)
+		}
+	}
+
+	if len(raw) > samlMaxRequest {
+		return nil, fmt.Errorf("%w: request is too large", vars.ErrInvalidSAMLRequest)
+	}
+
+	if bytes.Contains(raw, []byte("<!DOCTYPE")) {
+		return nil, fmt.Errorf("%w: doctype is not allowed", vars.ErrInvalidSAMLRequest)
+	}
+
+	var request samlAuthnRequest
+	if err := xml.Unmarshal(raw, &request); err != nil {
+		return nil, fmt.Errorf("%w: %v", vars.ErrInvalidSAMLRequest, err)
+	}
+
+	switch {
+	case request.Version != "2.0":
+		return nil, fmt.Errorf("%w: unsupported version %s", vars.ErrInvalidSAMLRequest, request.Version)
+	case request.ID == "" || request.Issuer == "":
+		return nil, fmt.Errorf("%w: id and issuer are required", vars.ErrInvalidSAMLRequest)
+	case request.ProtocolBinding != "" && request.ProtocolBinding != samlBindingPOST:
+		return nil, fmt.Errorf("%w: unsupported protocol binding %s", vars.ErrInvalidSAMLRequest, request.ProtocolBinding)
+	}
+
+	return &SAMLAuthnRequest{
+		ID:           request.ID,
+		Issuer:       strings.TrimSpace(request.Issuer),
+		ACSURL:       request.AssertionConsumerServiceURL,
+		Destination:  request.Destination,
+		IssueInstant: request.IssueInstant,
+	}, nil
+}
+
+// Response returns the base64 encoded SAMLResponse for the HTTP-POST
+// binding. Only the assertion is signed, which is what service providers
+// verify.
+func (s *SAMLIdP) Response(a *SAMLAssertion) (string, error) {
+	cert, key, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	assertionID, responseID := samlID(), samlID()
+	notBefore := now.Add(-certClockSkew).Format(samlTimeFormat)
+	notOnOrAfter := now.Add(s.validity).Format(samlTimeFormat)
+
+	issuer := c14nElement("saml:Issuer", nil, c14nText(a.Issuer))
+
+	subject := c14nElement("saml:Subject", nil,
+		c14nElement("saml:NameID", map[string]string{"Format": samlNameIDEmail}, c14nText(a.NameID)),
+		c14nElement("saml:SubjectConfirmation", map[string]string{"Method": samlBearer},
+			c14nElement("saml:SubjectConfirmationData", map[string]string{
+				"InResponseTo": a.InResponseTo,
+				"NotOnOrAfter": notOnOrAfter,
+				"Recipient":    a.Recipient,
+			}),
+		),
+	)
+
+	conditions := c14nElement("saml:Conditions", map[string]string{
+		"NotBefore":    notBefore,
+		"NotOnOrAfter": notOnOrAfter,
+	}, c14nElement("saml:AudienceRestriction", nil, c14nElement("saml:Audience", nil, c14nText(a.Audience))))
+
+	authnContext := a.AuthnContext
+	if authnContext == "" {
+		authnContext = samlMFA
+	}
+
+	authn := c14nElement("saml:AuthnStatement", map[string]string{
+		"AuthnInstant": a.AuthnInstant.UTC().Format(samlTimeFormat),
+		"SessionIndex": a.SessionIndex,
+	}, c14nElement("saml:AuthnContext", nil,
+		c14nElement("saml:AuthnContextClassRef", nil, c14nText(authnContext)),
+	))
+
+	attributes := make([]string, 0, len(a.Attributes))
+	for _, attr := range a.Attributes {
+		values := make([]string, 0, len(attr.Values))
+		for _, v := range attr.Values {
+			values = append(values, c14nElement("saml:AttributeValue", nil, c14nText(v)))
+		}
+
+		attributes = append(attributes, c14nElement("saml:Attribute", map[string]string{
+			"Name":       attr.Name,
+			"NameFormat": samlAttrNameBasic,
+		}, values...))
+	}
+
+	assertionAttrs := map[string]string{
+		"xmlns:saml":   samlAssertionNS,
+		"ID":           assertionID,
+		"IssueInstant": now.Format(samlTimeFormat),
+		"Version":      "2.0",
+	}
+	statements := []string{subject, conditions, authn}
+	if len(attributes) > 0 {
+		statements = append(statements, c14nElement("saml:AttributeStatement", nil, attributes...))
+	}
+
+	// the enveloped signature transform digests the assertion without its
+	// signature, which is placed right after the issuer
+	unsigned := c14nElement("saml:Assertion", assertionAttrs, append([]string{issuer}, statements...)...)
+	digest := sha256.Sum256([]byte(unsigned))
+
+	signedInfo := c14nElement("ds:SignedInfo", map[string]string{"xmlns:ds": xmldsigNS},
+		c14nElement("ds:CanonicalizationMethod", map[string]string{"Algorithm": xmlExcC14N}),
+		c14nElement("ds:SignatureMethod", map[string]string{"Algorithm": xmlRSASHA256}),
+		c14nElement("ds:Reference", map[string]string{"URI": "#" + assertionID},
+			c14nElement("ds:Transforms", nil,
+				c14nElement("ds:Transform", map[string]string{"Algorithm": xmlEnveloped}),
+				c14nElement("ds:Transform", map[string]string{"Algorithm": xmlExcC14N}),
+			),
+			c14nElement("ds:DigestMethod", map[string]string{"Algorithm": xmlDigestSHA256}),
+			c14nElement("ds:DigestValue", nil, base64.StdEncoding.EncodeToString(digest[:])),
+		),
+	)
+
+	hashed := sha256.Sum256([]byte(signedInfo))
+	signatureValue, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", fmt.Errorf("cannot sign saml assertion: %v", err)
+	}
+
+	signature := c14nElement("ds:Signature", map[string]string{"xmlns:ds": xmldsigNS},
+		signedInfo,
+		c14nElement("ds:SignatureValue", nil, base64.StdEncoding.EncodeToString(signatureValue)),
+		c14nElement("ds:KeyInfo", nil,
+			c14nElement("ds:X509Data", nil,
+				c14nElement("ds:X509Certificate", nil, base64.StdEncoding.EncodeToString(cert.Raw)),
+			),
+		),
+	)
+
+	assertion := c14nElement("saml:Assertion", assertionAttrs, append([]string{issuer, signature}, statements...)...)
+
+	response := c14nElement("samlp:Response", map[string]string{
+		"xmlns:samlp":  samlProtocolNS,
+		"xmlns:saml":   samlAssertionNS,
+		"Destination":  a.Recipient,
+		"ID":           responseID,
+		"InResponseTo": a.InResponseTo,
+		"IssueInstant": now.Format(samlTimeFormat),
+		"Version":      "2.0",
+	},
+		issuer,
+		c14nElement("samlp:Status", nil, c14nElement("samlp:StatusCode", map[string]string{"Value": samlStatusSuccess})),
+		assertion,
+	)
+
+	return base64.StdEncoding.EncodeToString([]byte(xml.Header + response)), nil
+}
+
+func (s *SAMLIdP) current() (*x509.Certificate, *rsa.PrivateKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.key == nil {
+		return nil, nil, errors.New("saml idp is not loaded")
+	}
+
+	return s.cert, s.key, nil
+}
+
+func newSAMLKey() (*model.SAMLKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate saml key: %v", err)
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "polonium saml idp"},
+		NotBefore:    now.Add(-certClockSkew),
+		NotAfter:     now.Add(caValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create saml certificate: %v", err)
+	}
+
+	private, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal saml key: %v", err)
+	}
+
+	return &model.SAMLKey{
+		Certificate: base64.StdEncoding.EncodeToString(der),
+		Private:     base64.StdEncoding.EncodeToString(private),
+		CreatedAt:   now,
+	}, nil
+}
+
+// samlID starts with a letter, as xml ids must.
+func samlID() string {
+	raw := make([]byte, 20)
+	rand.Read(raw)
+	return "_" + hex.EncodeToString(raw)
+}
+
+// c14nElement renders an element the way exclusive canonicalization does:
+// no empty element tags, namespace declarations first and the attributes
+// sorted. The assertion is built in this form, so that the bytes digested
+// are the bytes the service provider canonicalizes. Content must already be
+// escaped.
+func c14nElement(name string, attrs map[string]string, content ...string) string {
+	names := make([]string, 0, len(attrs))
+	for attr := range attrs {
+		names = append(names, attr)
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		aNS, bNS := strings.HasPrefix(a, "xmlns"), strings.HasPrefix(b, "xmlns")
+		if aNS != bNS {
+			if aNS {
+				return -1
+			}
+			return 1
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	var b strings.Builder
+	b.WriteString("<" + name)
+	for _, attr := range names {
+		b.WriteString(" " + attr + `="` + c14nAttr(attrs[attr]) + `"`)
+	}
+	b.WriteString(">")
+	for _, c := range content {
+		b.WriteString(c)
+	}
+	b.WriteString("</" + name + ">")
+
+	return b.String()
+}
+
+var (
+	c14nTextReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	c14nAttrReplacer = strings.NewReplacer(
+		"&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;",
+	)
+)
+
+func c14nText(s string) string {
+	return c14nTextReplacer.Replace(s)
+}
+
+func c14nAttr(s string) string {
+	return c14nAttrReplacer.Replace(s)
+}
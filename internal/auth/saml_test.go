@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+const testAuthnRequest = `<samlp:AuthnRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion"` +
+	` ID="_request" Version="%s" IssueInstant="2026-01-01T00:00:00Z" Destination="https://polonium.ws/saml/acme/sso"` +
+	` AssertionConsumerServiceURL="https://sp.example/acs" ProtocolBinding="%s">%s</samlp:AuthnRequest>`
+
+func authnRequest(version, binding, issuer string) string {
+	if issuer != "" {
+		issuer = "<saml:Issuer>" + issuer + "</saml:Issuer>"
+	}
+
+	return fmt.Sprintf(testAuthnRequest, version, binding, issuer)
+}
+
+func deflate(t *testing.T, raw string) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	w, _ := flate.NewWriter(&b, flate.DefaultCompression)
+	if _, err := w.Write([]byte(raw)); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+
+	return base64.StdEncoding.EncodeToString(b.Bytes())
+}
+
+func TestParseAuthnRequest(t *testing.T) {
+	valid := authnRequest("2.0", samlBindingPOST, " https://sp.example ")
+	encoded := base64.StdEncoding.EncodeToString([]byte(valid))
+
+	tests := []struct {
+		name     string
+		encoded  string
+		deflated bool
+		wantErr  bool
+	}{
+		{name: "http-post", encoded: encoded},
+		{name: "http-redirect", encoded: deflate(t, valid), deflated: true},
+		{name: "wrapped base64", encoded: encoded[:40] + "\r\n" + encoded[40:]},
+		{name: "no protocol binding", encoded: base64.StdEncoding.EncodeToString([]byte(authnRequest("2.0", "", "https://sp.example")))},
+		{name: "not base64", encoded: "%%%", wantErr: true},
+		{name: "not deflated", encoded: encoded, deflated: true, wantErr: true},
+		{
+			name:    "doctype",
+			encoded: base64.StdEncoding.EncodeToString([]byte(`<!DOCTYPE r [<!ENTITY x "x">]>` + valid)),
+			wantErr: true,
+		},
+		{
+			name:    "saml 1.1",
+			encoded: base64.StdEncoding.EncodeToString([]byte(authnRequest("1.1", samlBindingPOST, "https://sp.example"))),
+			wantErr: true,
+		},
+		{
+			name:    "no issuer",
+			encoded: base64.StdEncoding.EncodeToString([]byte(authnRequest("2.0", samlBindingPOST, ""))),
+			wantErr: true,
+		},
+		{
+			name:    "artifact binding",
+			encoded: base64.StdEncoding.EncodeToString([]byte(authnRequest("2.0", "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Artifact", "https://sp.example"))),
+			wantErr: true,
+		},
+		{
+			name:    "other element",
+			encoded: base64.StdEncoding.EncodeToString([]byte(strings.ReplaceAll(valid, "AuthnRequest", "LogoutRequest"))),
+			wantErr: true,
+		},
+		{
+			name:     "too large",
+			encoded:  deflate(t, strings.Replace(valid, "<saml:Issuer>", "<saml:Issuer>"+strings.Repeat(" ", samlMaxRequest), 1)),
+			deflated: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseAuthnRequest(tt.encoded, tt.deflated)
+			if tt.wantErr {
+				if !errors.Is(err, vars.ErrInvalidSAMLRequest) {
+					t.Fatalf("ParseAuthnRequest() error = %v, want %v", err, vars.ErrInvalidSAMLRequest)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAuthnRequest() error = %v", err)
+			}
+
+			if r.ID != "_request" || r.Issuer != "https://sp.example" || r.ACSURL != "https://sp.example/acs" {
+				t.Errorf("ParseAuthnRequest() = %+v", r)
+			}
+		})
+	}
+}
+
+func TestSAMLResponseSignature(t *testing.T) {
+	idp := NewSAMLIdP(newMemVault(), 5*time.Minute)
+	if err := idp.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	cert, _, err := idp.current()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		assertion    SAMLAssertion
+		authnContext string
+	}{
+		{
+			name: "plain",
+			assertion: SAMLAssertion{
+				NameID:     "user@polonium.ws",
+				Attributes: []SAMLAttribute{{Name: "email", Values: []string{"user@polonium.ws"}}},
+			},
+			authnContext: samlMFA,
+		},
+		{
+			name: "special characters",
+			assertion: SAMLAssertion{
+				Recipient:    `https://sp.example/acs?a=1&b="2"<3>`,
+				InResponseTo: "_id&\t\n'",
+				Audience:     "https://sp.example/?x=<y>&z",
+				NameID:       `o'brien+<tag>&"q"@polonium.ws`,
+				AuthnContext: "urn:oasis:names:tc:SAML:2.0:ac:classes:TimeSyncToken",
+				Attributes: []SAMLAttribute{
+					{Name: `display "name"`, Values: []string{"Zoë & <Jo>", "line\r\nbreak\ttab", "]]>"}},
+					{Name: "uid", Values: []string{"user-id"}},
+				},
+			},
+			authnContext: "urn:oasis:names:tc:SAML:2.0:ac:classes:TimeSyncToken",
+		},
+		{name: "no attributes", assertion: SAMLAssertion{NameID: "user@polonium.ws"}, authnContext: samlMFA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertion.Issuer = "https://polonium.ws/saml/acme"
+			tt.assertion.SessionIndex = "session"
+			tt.assertion.AuthnInstant = time.Now()
+
+			encoded, err := idp.Response(&tt.assertion)
+			if err != nil {
+				t.Fatalf("Response() error = %v", err)
+			}
+
+			raw, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc := etree.NewDocument()
+			if err := doc.ReadFromBytes(raw); err != nil {
+				t.Fatalf("response is not xml: %v", err)
+			}
+
+			assertion := doc.FindElement("/Response/Assertion")
+			if assertion == nil {
+				t.Fatalf("response has no assertion: %s", raw)
+			}
+
+			validator := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: []*x509.Certificate{cert}})
+			verified, err := validator.Validate(assertion.Copy())
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			if got := verified.FindElement("./Subject/NameID").Text(); got != tt.assertion.NameID {
+				t.Errorf("NameID = %q, want %q", got, tt.assertion.NameID)
+			}
+
+			if got := verified.FindElement("./AuthnStatement/AuthnContext/AuthnContextClassRef").Text(); got != tt.authnContext {
+				t.Errorf("AuthnContextClassRef = %q, want %q", got, tt.authnContext)
+			}
+
+			confirmation := verified.FindElement("./Subject/SubjectConfirmation/SubjectConfirmationData")
+			if confirmation.SelectAttrValue("Recipient", "") != tt.assertion.Recipient ||
+				confirmation.SelectAttrValue("InResponseTo", "") != tt.assertion.InResponseTo {
+				t.Errorf("SubjectConfirmationData = %v", confirmation.Attr)
+			}
+
+			if got := verified.FindElement("./Conditions/AudienceRestriction/Audience").Text(); got != tt.assertion.Audience {
+				t.Errorf("Audience = %q, want %q", got, tt.assertion.Audience)
+			}
+
+			attributes := verified.FindElements("./AttributeStatement/Attribute")
+			if len(attributes) != len(tt.assertion.Attributes) {
+				t.Fatalf("%d attributes, want %d", len(attributes), len(tt.assertion.Attributes))
+			}
+			for i, attr := range attributes {
+				want := tt.assertion.Attributes[i]
+				if attr.SelectAttrValue("Name", "") != want.Name {
+					t.Errorf("attribute name = %q, want %q", attr.SelectAttrValue("Name", ""), want.Name)
+				}
+
+				values := attr.SelectElements("AttributeValue")
+				for j, value := range values {
+					if value.Text() != want.Values[j] {
+						t.Errorf("attribute %s value = %q, want %q", want.Name, value.Text(), want.Values[j])
+					}
+				}
+			}
+
+			// a changed assertion no longer verifies
+			tampered := assertion.Copy()
+			tampered.FindElement("./Subject/NameID").SetText("admin@polonium.ws")
+			if _, err := validator.Validate(tampered); err == nil {
+				t.Error("Validate() accepted a tampered assertion")
+			}
+		})
+	}
+}
@@ -1,110 +1,275 @@
 package auth
 
 import (
-	"crypto/rsa"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/go-jose/go-jose/v4"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
 )
 
 type (
+	Denylist interface {
+		IsTokenRevoked(jti string) (bool, error)
+	}
+
+	// JWTProcessor issues and checks tokens. Despite the name it is not
+	// tied to JWT, the wire format is chosen by the TokenFormat.
 	JWTProcessor struct {
-		publicKey       *rsa.PublicKey
-		privateKey      *rsa.PrivateKey
-		signingMethod   jwt.SigningMethod
+		keys            KeyProvider
+		format          TokenFormat
+		denylist        Denylist
 		access, refresh time.Duration
 		issuer          string
 	}
 
 	CustomClaims struct {
 		UserID   string `json:"user_id"`
+		Email    string `json:"email"`
 		Deployer string `json:"deployer"`
 		Session  string `json:"session"`
+		ClientID string `json:"client_id,omitempty"`
+		Scope    string `json:"scope,omitempty"`
+		// Confirmation is set on access tokens bound to a DPoP key.
+		Confirmation *model.Confirmation `json:"cnf,omitempty"`
+		// Actor is set on tokens issued by a token exchange.
+		Actor *model.Actor `json:"act,omitempty"`
 		jwt.RegisteredClaims
 	}
 )
 
-func NewRSAJWTProcessor(
-	privateKey *rsa.PrivateKey,
-	publicKey *rsa.PublicKey,
+func NewJWTProcessor(
+	keys KeyProvider,
+	format TokenFormat,
+	denylist Denylist,
 	access, refresh time.Duration,
 	issuer string,
 ) *JWTProcessor {
 	return &JWTProcessor{
-		privateKey:    privateKey,
-		publicKey:     publicKey,
-		signingMethod: jwt.SigningMethodRS256,
-		access:        access,
-		refresh:       refresh,
-		issuer:        issuer,
+		keys:     keys,
+		format:   format,
+		denylist: denylist,
+		access:   access,
+		refresh:  refresh,
+		issuer:   issuer,
 	}
 }
 
-func (j *JWTProcessor) GenerateAccessToken(user, session string) (string, error) {
+func (j *JWTProcessor) GenerateAccessToken(user *model.User, session *model.Session) (string, error) {
 	now := time.Now()
 	claims := CustomClaims{
-		UserID:  user,
-		Session: session,
+		UserID:   user.Id,
+		Email:    user.Email,
+		Deployer: user.Deployer,
+		Session:  session.ID,
+		ClientID: session.Client,
+		Scope:    session.Scope,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Audience:  session.Audience,
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.access)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    j.issuer,
 			Subject:   "access",
 		},
 	}
 
-	token := jwt.NewWithClaims(j.signingMethod, claims)
-	return token.SignedString(j.privateKey)
+	if session.DPoPJKT != "" {
+		claims.Confirmation = &model.Confirmation{JKT: session.DPoPJKT}
+	}
+
+	return j.format.Encode(&claims)
 }
 
-func (j *JWTProcessor) GenerateRefreshToken(session string, userID string) (string, error) {
+func (j *JWTProcessor) GenerateRefreshToken(user *model.User, session *model.Session, jti string) (string, error) {
 	now := time.Now()
 	claims := CustomClaims{
-		UserID:  userID,
-		Session: session,
+		UserID:   user.Id,
+		Email:    user.Email,
+		Deployer: user.Deployer,
+		Session:  session.ID,
+		ClientID: session.Client,
+		Scope:    session.Scope,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Audience:  jwt.ClaimStrings{j.issuer},
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.refresh)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    j.issuer,
 			Subject:   "refresh",
 		},
 	}
 
-	token := jwt.NewWithClaims(j.signingMethod, claims)
-	return token.SignedString(j.privateKey)
+	return j.format.Encode(&claims)
 }
 
-func (j *JWTProcessor) TokenVerify(tokenString string) (*CustomClaims, error) {
-	if tokenString == "" {
-		return nil, errors.New("token is empty")
+// GenerateExchangedToken issues an access token for the subject of an
+// exchanged token with the actor as its client. It never outlives the
+// subject token and keeps its DPoP binding.
+func (j *JWTProcessor) GenerateExchangedToken(
+	subject *CustomClaims,
+	actor, scope string,
+	audience []string,
+) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(j.access)
+	if subject.ExpiresAt != nil && subject.ExpiresAt.Before(expiresAt) {
+		expiresAt = subject.ExpiresAt.Time
 	}
 
-	keyFunc := func(token *jwt.Token) (interface{}, error) {
-		if token.Method.Alg() != j.signingMethod.Alg() {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
-		}
-		return j.publicKey, nil
+	claims := CustomClaims{
+		UserID:   subject.UserID,
+		Email:    subject.Email,
+		Deployer: subject.Deployer,
+		Session:  subject.Session,
+		ClientID: actor,
+		Scope:    scope,
+		Actor:    &model.Actor{Sub: actor, Act: subject.Actor},
+		// an exchange must not turn a sender-constrained token into a bearer one
+		Confirmation: subject.Confirmation,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Audience:  audience,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    j.issuer,
+			Subject:   "access",
+		},
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, keyFunc)
+	token, err := j.format.Encode(&claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// GenerateClientToken issues an access token to a client acting on its own
+// behalf. Its subject is the client and it carries no user or session.
+func (j *JWTProcessor) GenerateClientToken(
+	clientID, scope string,
+	audience []string,
+	dpopJKT string,
+) (string, error) {
+	now := time.Now()
+	claims := CustomClaims{
+		ClientID: clientID,
+		Scope:    scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Audience:  audience,
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.access)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    j.issuer,
+			Subject:   clientID,
+		},
+	}
+
+	if dpopJKT != "" {
+		claims.Confirmation = &model.Confirmation{JKT: dpopJKT}
+	}
+
+	return j.format.Encode(&claims)
+}
+
+// TokenVerify checks the token and, when audience is set, that it was issued
+// for that audience and carries every required scope.
+func (j *JWTProcessor) TokenVerify(tokenString, audience string, scopes ...string) (*CustomClaims, error) {
+	if tokenString == "" {
+		return nil, errors.New("token is empty")
+	}
+
+	claims, err := j.format.Decode(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
-	if !token.Valid {
-		return nil, errors.New("token is invalid")
+	options := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if audience != "" {
+		options = append(options, jwt.WithAudience(audience))
 	}
 
-	claims, ok := token.Claims.(*CustomClaims)
-	if !ok {
-		return nil, errors.New("invalid token claims")
+	if err := jwt.NewValidator(options...).Validate(claims); err != nil {
+		return nil, err
 	}
 
 	if j.issuer != "" && claims.Issuer != j.issuer {
 		return nil, fmt.Errorf("invalid token issuer: %s", claims.Issuer)
 	}
 
+	for _, scope := range scopes {
+		if !claims.HasScope(scope) {
+			return nil, fmt.Errorf("%w: %s", vars.ErrInsufficientScope, scope)
+		}
+	}
+
+	if claims.ID != "" {
+		revoked, err := j.denylist.IsTokenRevoked(claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", vars.ErrRevocationCheckFailed, err)
+		}
+
+		if revoked {
+			return nil, vars.ErrTokenRevoked
+		}
+	}
+
 	return claims, nil
 }
+
+// IsClientToken tells a client credentials token from a user token.
+func (c *CustomClaims) IsClientToken() bool {
+	return c.UserID == "" && c.ClientID != "" && c.Subject == c.ClientID
+}
+
+// TokenType is "access" or "refresh", client tokens are access tokens.
+func (c *CustomClaims) TokenType() string {
+	if c.IsClientToken() {
+		return "access"
+	}
+
+	return c.Subject
+}
+
+func (c *CustomClaims) HasScope(scope string) bool {
+	return slices.Contains(strings.Fields(c.Scope), scope)
+}
+
+func (j *JWTProcessor) AccessTTL() time.Duration {
+	return j.access
+}
+
+func (j *JWTProcessor) RefreshTTL() time.Duration {
+	return j.refresh
+}
+
+func (j *JWTProcessor) Issuer() string {
+	return j.issuer
+}
+
+func (j *JWTProcessor) JWKS() jose.JSONWebKeySet {
+	return jose.JSONWebKeySet{Keys: j.keys.PublicKeys()}
+}
+
+func (j *JWTProcessor) JWKSCacheTTL() time.Duration {
+	return j.keys.CacheTTL()
+}
+
+func (j *JWTProcessor) Algorithms() []string {
+	return []string{j.keys.Algorithm()}
+}
+
+func (j *JWTProcessor) Format() string {
+	return j.format.Name()
+}
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+func newTestJWTProcessor(t *testing.T, alg string) (*JWTProcessor, *KeyRing) {
+	t.Helper()
+
+	ring := newTestKeyRing(t, newMemVault(), alg)
+	if err := ring.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewJWTProcessor(ring, &jwtFormat{keys: ring}, newDenylist(), time.Minute, time.Hour, "issuer"), ring
+}
+
+func TestJWTProcessorAlgorithms(t *testing.T) {
+	for _, alg := range []string{"EdDSA", "ES256", "RS256", "HS256"} {
+		t.Run(alg, func(t *testing.T) {
+			jp, ring := newTestJWTProcessor(t, alg)
+
+			token, err := jp.GenerateAccessToken(&model.User{Id: "user-id"}, &model.Session{ID: "session"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &CustomClaims{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if parsed.Method.Alg() != alg {
+				t.Errorf("token is signed with %s, want %s", parsed.Method.Alg(), alg)
+			}
+
+			claims, err := jp.TokenVerify(token, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if claims.UserID != "user-id" || claims.Session != "session" || claims.Subject != "access" {
+				t.Errorf("unexpected claims: %+v", claims)
+			}
+
+			// HMAC secrets must never be published
+			if published := len(ring.PublicKeys()) > 0; published != (alg != "HS256") {
+				t.Errorf("published keys = %v for %s", published, alg)
+			}
+		})
+	}
+}
+
+func TestJWTProcessorRejectsOtherAlgorithms(t *testing.T) {
+	jp, ring := newTestJWTProcessor(t, "RS256")
+
+	key, err := ring.SigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := CustomClaims{
+		UserID: "user-id",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "issuer",
+			Subject:   "access",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+
+	// the public key used as an HMAC secret under the same kid
+	public, err := x509.MarshalPKIXPublicKey(key.Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	confused.Header["kid"] = key.Kid
+	hs, err := confused.SignedString(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	unsigned.Header["kid"] = key.Kid
+	none, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noKid := jwt.NewWithClaims(key.Method, claims)
+	missing, err := noKid.SignedString(key.Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, token := range map[string]string{"HS256 with public key": hs, "none": none, "no kid": missing} {
+		if _, err := jp.TokenVerify(token, ""); err == nil {
+			t.Errorf("%s token was accepted", name)
+		}
+	}
+}
+
+func TestJWTProcessorDenylist(t *testing.T) {
+	_, ring := newTestJWTProcessor(t, "EdDSA")
+	deny := newDenylist()
+	jp := NewJWTProcessor(ring, &jwtFormat{keys: ring}, deny, time.Minute, time.Hour, "issuer")
+
+	token, err := jp.GenerateAccessToken(&model.User{Id: "user-id"}, &model.Session{ID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := jp.TokenVerify(token, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.ID == "" {
+		t.Fatal("access token has no jti")
+	}
+
+	deny.revoked[claims.ID] = true
+	if _, err := jp.TokenVerify(token, ""); !errors.Is(err, vars.ErrTokenRevoked) {
+		t.Errorf("revoked token: got %v, want %v", err, vars.ErrTokenRevoked)
+	}
+
+	deny.err = errors.New("redis is down")
+	if _, err := jp.TokenVerify(token, ""); !errors.Is(err, vars.ErrRevocationCheckFailed) {
+		t.Errorf("failed denylist: got %v, want %v", err, vars.ErrRevocationCheckFailed)
+	}
+}
+
+func TestJWTProcessorPASETO(t *testing.T) {
+	keys := newStaticKeys(t)
+	jp := NewJWTProcessor(keys, &pasetoFormat{keys: keys}, newDenylist(), time.Minute, time.Hour, "issuer")
+
+	token, err := jp.GenerateAccessToken(&model.User{Id: "user-id"}, &model.Session{ID: "session", Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(token, pasetoV4PublicHeader) {
+		t.Fatalf("token %s is not v4.public", token)
+	}
+
+	claims, err := jp.TokenVerify(token, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.UserID != "user-id" || claims.Session != "session" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	if _, err := jp.TokenVerify(token, "billing"); err == nil {
+		t.Error("token accepted for an audience it was not issued for")
+	}
+
+	expired := NewJWTProcessor(keys, &pasetoFormat{keys: keys}, newDenylist(), -time.Minute, time.Hour, "issuer")
+	stale, err := expired.GenerateAccessToken(&model.User{Id: "user-id"}, &model.Session{ID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := jp.TokenVerify(stale, ""); !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Errorf("expired token: got %v, want %v", err, jwt.ErrTokenExpired)
+	}
+}
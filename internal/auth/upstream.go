@@ -0,0 +1,309 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	upstreamTimeout         = 10 * time.Second
+	upstreamLeeway          = time.Minute
+	upstreamRefreshInterval = 10 * time.Second
+)
+
+var upstreamAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "EdDSA"}
+
+type (
+	// Upstream is an OpenID Connect provider that users can log in with.
+	// Polonium is a confidential relying party of it and uses the code flow
+	// with PKCE. The discovery document and the keys are fetched lazily, so
+	// that a provider being down does not stop polonium from starting.
+	Upstream struct {
+		issuer       string
+		clientID     string
+		clientSecret string
+		redirectURI  string
+		scopes       []string
+		client       *http.Client
+
+		mu        sync.Mutex
+		discovery *upstreamDiscovery
+		keys      map[string]jose.JSONWebKey
+		fetchedAt time.Time
+	}
+
+	// UpstreamIdentity is the user as the upstream provider asserts it.
+	UpstreamIdentity struct {
+		Subject       string
+		Email         string
+		EmailVerified bool
+		AMR           []string
+	}
+
+	upstreamDiscovery struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JwksURI               string `json:"jwks_uri"`
+	}
+
+	upstreamTokenResponse struct {
+		IDToken string `json:"id_token"`
+	}
+
+	upstreamClaims struct {
+		Email         string   `json:"email"`
+		EmailVerified any      `json:"email_verified"`
+		Nonce         string   `json:"nonce"`
+		AZP           string   `json:"azp"`
+		AMR           []string `json:"amr"`
+		jwt.RegisteredClaims
+	}
+)
+
+func NewUpstream(issuer, clientID, clientSecret, redirectURI string, scopes []string) *Upstream {
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Upstream{
+		issuer:       strings.TrimRight(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURI:  redirectURI,
+		scopes:       scopes,
+		client:       &http.Client{Timeout: upstreamTimeout},
+		keys:         map[string]jose.JSONWebKey{},
+	}
+}
+
+// AuthCodeURL is where the browser is sent to log in upstream.
+func (u *Upstream) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	discovery, err := u.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid upstream authorization endpoint: %v", err)
+	}
+
+	query := target.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", u.clientID)
+	query.Set("redirect_uri", u.redirectURI)
+	query.Set("scope", strings.Join(u.scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	target.RawQuery = query.Encode()
+
+	return target.String(), nil
+}
+
+// Exchange redeems the upstream code and verifies the ID token it returns.
+func (u *Upstream) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*UpstreamIdentity, error) {
+	discovery, err := u.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {u.redirectURI},
+		"code_verifier": {codeVerifier},
+	}
+	if u.clientSecret == "" {
+		form.Set("client_id", u.clientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if u.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(u.clientID), url.QueryEscape(u.clientSecret))
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot redeem upstream code: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read upstream token response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot redeem upstream code: status %d: %s", resp.StatusCode, body)
+	}
+
+	var tokens upstreamTokenResponse
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, fmt.Errorf("cannot decode upstream token response: %v", err)
+	}
+
+	if tokens.IDToken == "" {
+		return nil, errors.New("upstream returned no id token")
+	}
+
+	return u.verifyIDToken(ctx, tokens.IDToken, nonce)
+}
+
+func (u *Upstream) verifyIDToken(ctx context.Context, raw, nonce string) (*UpstreamIdentity, error) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := u.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		if key.Algorithm != "" && key.Algorithm != t.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Method.Alg())
+		}
+
+		return key.Key, nil
+	}
+
+	claims := new(upstreamClaims)
+	if _, err := jwt.ParseWithClaims(
+		raw, claims, keyFunc,
+		jwt.WithValidMethods(upstreamAlgorithms),
+		jwt.WithIssuer(u.issuer),
+		jwt.WithAudience(u.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(upstreamLeeway),
+	); err != nil {
+		return nil, fmt.Errorf("invalid upstream id token: %v", err)
+	}
+
+	switch {
+	case claims.Subject == "":
+		return nil, errors.New("upstream id token has no subject")
+	case claims.Nonce != nonce:
+		return nil, errors.New("upstream id token nonce does not match")
+	case len(claims.Audience) > 1 && claims.AZP != u.clientID:
+		return nil, errors.New("upstream id token was issued to another party")
+	}
+
+	// some providers send email_verified as a string
+	verified := claims.EmailVerified == true || claims.EmailVerified == "true"
+
+	return &UpstreamIdentity{
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: verified,
+		AMR:           claims.AMR,
+	}, nil
+}
+
+func (u *Upstream) discover(ctx context.Context) (*upstreamDiscovery, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.discovery != nil {
+		return u.discovery, nil
+	}
+
+	discovery := new(upstreamDiscovery)
+	if err := u.getJSON(ctx, u.issuer+"/.well-known/openid-configuration", discovery); err != nil {
+		return nil, fmt.Errorf("cannot discover upstream provider: %v", err)
+	}
+
+	if strings.TrimRight(discovery.Issuer, "/") != u.issuer {
+		return nil, fmt.Errorf("upstream issuer mismatch: %s", discovery.Issuer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksURI == "" {
+		return nil, errors.New("upstream discovery document is incomplete")
+	}
+
+	u.discovery = discovery
+	return discovery, nil
+}
+
+// key refetches the upstream keys when the token names an unknown one, at
+// most every upstreamRefreshInterval.
+func (u *Upstream) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	discovery, err := u.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	key, ok := u.lookup(kid)
+	if !ok && time.Since(u.fetchedAt) > upstreamRefreshInterval {
+		var set jose.JSONWebKeySet
+		if err := u.getJSON(ctx, discovery.JwksURI, &set); err != nil {
+			return nil, fmt.Errorf("cannot fetch upstream jwks: %v", err)
+		}
+
+		keys := make(map[string]jose.JSONWebKey, len(set.Keys))
+		for _, k := range set.Keys {
+			if k.Use == "" || k.Use == "sig" {
+				keys[k.KeyID] = k
+			}
+		}
+
+		u.keys, u.fetchedAt = keys, time.Now()
+		key, ok = u.lookup(kid)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown upstream signing key: %s", kid)
+	}
+
+	return &key, nil
+}
+
+// lookup also accepts tokens without a key id when there is a single key.
+func (u *Upstream) lookup(kid string) (jose.JSONWebKey, bool) {
+	if kid == "" && len(u.keys) == 1 {
+		for _, key := range u.keys {
+			return key, true
+		}
+	}
+
+	key, ok := u.keys[kid]
+	return key, ok
+}
+
+func (u *Upstream) getJSON(ctx context.Context, target string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
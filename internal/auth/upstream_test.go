@@ -0,0 +1,292 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	stubClientID     = "polonium"
+	stubClientSecret = "secret"
+	stubRedirectURI  = "https://polonium.ws/federation/stub/callback"
+	stubCode         = "upstream-code"
+	stubNonce        = "nonce"
+)
+
+// stubProvider is a minimal OpenID Connect provider. It answers the code
+// exchange with the ID token its sign function builds.
+type stubProvider struct {
+	*httptest.Server
+
+	key    *ecdsa.PrivateKey
+	issuer string
+	sign   func(claims jwt.MapClaims) string
+
+	verifier string
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &stubProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.issuer,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+			Key: &key.PublicKey, KeyID: "stub-kid", Algorithm: "ES256", Use: "sig",
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if id != stubClientID || secret != stubClientSecret ||
+			r.PostFormValue("grant_type") != "authorization_code" ||
+			r.PostFormValue("code") != stubCode ||
+			r.PostFormValue("redirect_uri") != stubRedirectURI {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		p.verifier = r.PostFormValue("code_verifier")
+
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "upstream-access-token",
+			"token_type":   "Bearer",
+			"id_token":     p.sign(p.claims()),
+		})
+	})
+
+	p.Server = httptest.NewServer(mux)
+	p.issuer = p.URL
+	p.sign = p.signES256("stub-kid")
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func (p *stubProvider) claims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            "upstream-subject",
+		"aud":            stubClientID,
+		"exp":            now.Add(time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          stubNonce,
+		"email":          "User@Example.com",
+		"email_verified": true,
+	}
+}
+
+func (p *stubProvider) signES256(kid string) func(jwt.MapClaims) string {
+	return func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = kid
+		signed, _ := token.SignedString(p.key)
+		return signed
+	}
+}
+
+func (p *stubProvider) upstream() *Upstream {
+	return NewUpstream(p.URL, stubClientID, stubClientSecret, stubRedirectURI, nil)
+}
+
+func TestUpstreamAuthCodeURL(t *testing.T) {
+	p := newStubProvider(t)
+
+	raw, err := p.upstream().AuthCodeURL(context.Background(), "state", stubNonce, "verifier")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+
+	target, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {stubClientID},
+		"redirect_uri":          {stubRedirectURI},
+		"scope":                 {"openid email profile"},
+		"state":                 {"state"},
+		"nonce":                 {stubNonce},
+		"code_challenge":        {CodeChallenge("verifier")},
+		"code_challenge_method": {"S256"},
+	}
+	if target.Path != "/authorize" || target.Query().Encode() != want.Encode() {
+		t.Errorf("AuthCodeURL() = %s", raw)
+	}
+}
+
+func TestUpstreamExchange(t *testing.T) {
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		change  func(p *stubProvider, claims jwt.MapClaims)
+		sign    func(p *stubProvider) func(jwt.MapClaims) string
+		code    string
+		nonce   string
+		wantErr bool
+	}{
+		{name: "valid"},
+		{
+			name: "several audiences with azp",
+			change: func(_ *stubProvider, c jwt.MapClaims) {
+				c["aud"], c["azp"] = []string{stubClientID, "other"}, stubClientID
+			},
+		},
+		{name: "expired within leeway", change: func(_ *stubProvider, c jwt.MapClaims) { c["exp"] = time.Now().Add(-30 * time.Second).Unix() }},
+		{name: "other issuer", change: func(_ *stubProvider, c jwt.MapClaims) { c["iss"] = "https://evil.example" }, wantErr: true},
+		{name: "other audience", change: func(_ *stubProvider, c jwt.MapClaims) { c["aud"] = "other" }, wantErr: true},
+		{
+			name:    "several audiences without azp",
+			change:  func(_ *stubProvider, c jwt.MapClaims) { c["aud"] = []string{stubClientID, "other"} },
+			wantErr: true,
+		},
+		{
+			name: "several audiences with another azp",
+			change: func(_ *stubProvider, c jwt.MapClaims) {
+				c["aud"], c["azp"] = []string{stubClientID, "other"}, "other"
+			},
+			wantErr: true,
+		},
+		{name: "other nonce", nonce: "other", wantErr: true},
+		{name: "no nonce", change: func(_ *stubProvider, c jwt.MapClaims) { delete(c, "nonce") }, wantErr: true},
+		{name: "expired", change: func(_ *stubProvider, c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * time.Minute).Unix() }, wantErr: true},
+		{name: "no expiration", change: func(_ *stubProvider, c jwt.MapClaims) { delete(c, "exp") }, wantErr: true},
+		{name: "no subject", change: func(_ *stubProvider, c jwt.MapClaims) { delete(c, "sub") }, wantErr: true},
+		{
+			name:    "unknown key",
+			sign:    func(p *stubProvider) func(jwt.MapClaims) string { return p.signES256("other-kid") },
+			wantErr: true,
+		},
+		{
+			name: "other key",
+			sign: func(p *stubProvider) func(jwt.MapClaims) string {
+				return func(claims jwt.MapClaims) string {
+					token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+					token.Header["kid"] = "stub-kid"
+					signed, _ := token.SignedString(other)
+					return signed
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "hs256",
+			sign: func(p *stubProvider) func(jwt.MapClaims) string {
+				return func(claims jwt.MapClaims) string {
+					token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+					token.Header["kid"] = "stub-kid"
+					signed, _ := token.SignedString([]byte(stubClientSecret))
+					return signed
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsigned",
+			sign: func(p *stubProvider) func(jwt.MapClaims) string {
+				return func(claims jwt.MapClaims) string {
+					signed, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+					return signed
+				}
+			},
+			wantErr: true,
+		},
+		{name: "rejected code", code: "other", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newStubProvider(t)
+			if tt.sign != nil {
+				p.sign = tt.sign(p)
+			}
+			if tt.change != nil {
+				sign := p.sign
+				p.sign = func(claims jwt.MapClaims) string {
+					tt.change(p, claims)
+					return sign(claims)
+				}
+			}
+
+			code, nonce := stubCode, stubNonce
+			if tt.code != "" {
+				code = tt.code
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			identity, err := p.upstream().Exchange(context.Background(), code, "verifier", nonce)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Exchange() = %+v, want an error", identity)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange() error = %v", err)
+			}
+
+			if identity.Subject != "upstream-subject" || identity.Email != "user@example.com" || !identity.EmailVerified {
+				t.Errorf("Exchange() = %+v", identity)
+			}
+
+			if p.verifier != "verifier" {
+				t.Errorf("code verifier sent upstream = %q", p.verifier)
+			}
+		})
+	}
+}
+
+func TestUpstreamDiscovery(t *testing.T) {
+	tests := []struct {
+		name   string
+		issuer func(p *stubProvider) string
+	}{
+		{name: "other issuer in the document", issuer: func(p *stubProvider) string { return "https://evil.example" }},
+		{name: "issuer with a path", issuer: func(p *stubProvider) string { return p.URL + "/tenant" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newStubProvider(t)
+			p.issuer = tt.issuer(p)
+
+			if _, err := p.upstream().AuthCodeURL(context.Background(), "state", stubNonce, "verifier"); err == nil ||
+				!strings.Contains(err.Error(), "issuer mismatch") {
+				t.Fatalf("AuthCodeURL() error = %v, want an issuer mismatch", err)
+			}
+		})
+	}
+}
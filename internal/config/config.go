@@ -1,9 +1,13 @@
 package config
 
 import (
+	"encoding/json"
+	"encoding/xml"
 	"log"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,17 +19,54 @@ type (
 		Redis                       Redis
 		Vault                       Vault
 		Auth                        Auth
+		Federation                  Federation
+		SAML                        SAML
 	}
 
 	Auth struct {
 		Issuer          string
+		TokenFormat     string
+		EncryptTokens   bool
+		Audience        string
+		DefaultClient   string
+		CLIClient       string
 		CertSecret      string
 		Access, Refresh time.Duration
 		CertExp         time.Duration
+		LoginClients    []string
+		AuthCodeTTL     time.Duration
+		DeviceCodeTTL   time.Duration
+		DeviceInterval  time.Duration
+		DeviceAttempts  int
+		ConsentTTL      time.Duration
+		TrustDomain     string
+		Admins          []string
+		Keys            Keys
+		DPoP            DPoP
+		Clients         []Client
+	}
+
+	DPoP struct {
+		RequireNonce          bool
+		ProofWindow, NonceTTL time.Duration
+	}
+
+	Client struct {
+		ID           string   `json:"id"`
+		SecretHash   string   `json:"secret_hash"`
+		Scopes       []string `json:"scopes"`
+		Audiences    []string `json:"audiences"`
+		GrantTypes   []string `json:"grant_types"`
+		RedirectURIs []string `json:"redirect_uris"`
+	}
+
+	Keys struct {
+		Algorithm                   string
+		Rotation, Lead, Grace, Sync time.Duration
 	}
 
 	Server struct {
-		Port string
+		Port, URL string
 	}
 
 	Psql struct {
@@ -45,16 +86,83 @@ type (
 	Vault struct {
 		Address, Token, MountPath string
 	}
+
+	Federation struct {
+		// ReturnURL is where the browser lands after a federated login.
+		ReturnURL string
+		StateTTL  time.Duration
+		Providers []FederationProvider
+	}
+
+	FederationProvider struct {
+		ID           string   `json:"id"`
+		Name         string   `json:"name"`
+		Issuer       string   `json:"issuer"`
+		ClientID     string   `json:"client_id"`
+		ClientSecret string   `json:"client_secret"`
+		Scopes       []string `json:"scopes"`
+		// Domains limits the accounts the provider may link or create to
+		// these email domains. Empty allows every domain, but then the
+		// provider only creates new accounts: it is not trusted to log in
+		// to an existing password and TOTP account.
+		Domains []string `json:"domains"`
+	}
+
+	SAML struct {
+		AssertionTTL time.Duration
+		Tenants      []SAMLTenant
+	}
+
+	SAMLTenant struct {
+		ID               string                `json:"id"`
+		ServiceProviders []SAMLServiceProvider `json:"service_providers"`
+	}
+
+	// SAMLServiceProvider is given either by its fields or by the SP
+	// metadata document, which fills in the entity id and the HTTP-POST
+	// assertion consumer services.
+	SAMLServiceProvider struct {
+		EntityID string   `json:"entity_id"`
+		ACSURLs  []string `json:"acs_urls"`
+		Metadata string   `json:"metadata"`
+		// AuthnContext is the authentication context class asserted to the
+		// service provider, the REFEDS MFA profile when empty.
+		AuthnContext string `json:"authn_context"`
+	}
+
+	samlEntityDescriptor struct {
+		EntityID                  string `xml:"entityID,attr"`
+		AssertionConsumerServices []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"SPSSODescriptor>AssertionConsumerService"`
+	}
 )
 
 func Init() *PAuth {
 	return &PAuth{
-		PublicServer: Server{Port: ":8080"},
-		Psql:         loadPsql(),
-		Smtp:         loadSmtp(),
-		Redis:        loadRedis(),
-		Vault:        loadVault(),
-		Auth:         loadAuth(),
+		PublicServer:  loadPublicServer(),
+		PrivateServer: loadPrivateServer(),
+		Psql:          loadPsql(),
+		Smtp:          loadSmtp(),
+		Redis:         loadRedis(),
+		Vault:         loadVault(),
+		Auth:          loadAuth(),
+		Federation:    loadFederation(),
+		SAML:          loadSAML(),
+	}
+}
+
+func loadPublicServer() Server {
+	return Server{
+		Port: ":8080",
+		URL:  envDefault[string]("APP_PUBLIC_URL", "http://localhost:8080"),
+	}
+}
+
+func loadPrivateServer() Server {
+	return Server{
+		Port: ":8081",
 	}
 }
 
@@ -92,16 +200,191 @@ func loadVault() Vault {
 }
 
 func loadAuth() Auth {
+	refresh := envDefault[time.Duration]("APP_REFRESH_TTL", time.Hour)
+	audience := envDefault[string]("APP_AUTH_AUDIENCE", "polonium-auth")
+	defaultClient := envDefault[string]("APP_AUTH_DEFAULT_CLIENT", "polonium-web")
+	cliClient := envDefault[string]("APP_AUTH_CLI_CLIENT", "polonium-cli")
+	clients := loadClients(defaultClient, cliClient, audience)
+
+	// the login endpoints do not authenticate the client, so they may only
+	// issue tokens to public first-party clients
+	loginClients := strings.Fields(envDefault[string]("APP_AUTH_LOGIN_CLIENTS", defaultClient))
+	for _, id := range loginClients {
+		i := slices.IndexFunc(clients, func(c Client) bool {
+			return c.ID == id
+		})
+
+		if i < 0 || clients[i].SecretHash != "" {
+			log.Fatalf("login client %s must be a configured public client", id)
+		}
+	}
+
 	return Auth{
-		Issuer:     envDefault[string]("APP_AUTH_ISSUER", "polonium-authorization"),
-		CertSecret: envRequired[string]("APP_AUTH_CERT_SECRET"),
-		Access:     envDefault[time.Duration]("APP_ACCESS_TTL", time.Minute),
-		Refresh:    envDefault[time.Duration]("APP_REFRESH_TTL", time.Hour),
-		CertExp:    envDefault[time.Duration]("APP_CERT_TTL", time.Hour),
+		Issuer:         envDefault[string]("APP_AUTH_ISSUER", "polonium-authorization"),
+		TokenFormat:    envDefault[string]("APP_TOKEN_FORMAT", "jwt"),
+		EncryptTokens:  envDefault[bool]("APP_TOKEN_ENCRYPTION", false),
+		Audience:       audience,
+		DefaultClient:  defaultClient,
+		CLIClient:      cliClient,
+		CertSecret:     envRequired[string]("APP_AUTH_CERT_SECRET"),
+		Access:         envDefault[time.Duration]("APP_ACCESS_TTL", time.Minute),
+		Refresh:        refresh,
+		CertExp:        envDefault[time.Duration]("APP_CERT_TTL", time.Hour),
+		AuthCodeTTL:    envDefault[time.Duration]("APP_OAUTH_CODE_TTL", time.Minute),
+		DeviceCodeTTL:  envDefault[time.Duration]("APP_OAUTH_DEVICE_CODE_TTL", 10*time.Minute),
+		DeviceInterval: envDefault[time.Duration]("APP_OAUTH_DEVICE_INTERVAL", 5*time.Second),
+		DeviceAttempts: envDefault[int]("APP_OAUTH_DEVICE_ATTEMPTS", 10),
+		ConsentTTL:     envDefault[time.Duration]("APP_OAUTH_CONSENT_TTL", 5*time.Minute),
+		TrustDomain:    envDefault[string]("APP_SPIFFE_TRUST_DOMAIN", "polonium.ws"),
+		Admins:         strings.Fields(envDefault[string]("APP_AUTH_ADMINS", "")),
+		Keys:           loadKeys(refresh),
+		DPoP:           loadDPoP(),
+		Clients:        clients,
+		LoginClients:   loginClients,
+	}
+}
+
+// loadClients reads the registered clients and makes sure the first-party
+// clients used by the ext-auth flows and by the CLI exist.
+func loadClients(defaultClient, cliClient, audience string) []Client {
+	var clients []Client
+
+	raw := envDefault[string]("APP_AUTH_CLIENTS", "[]")
+	if err := json.Unmarshal([]byte(raw), &clients); err != nil {
+		log.Fatalf("environment variable APP_AUTH_CLIENTS must be a valid json: %v", err)
+	}
+
+	registered := func(id string) bool {
+		return slices.ContainsFunc(clients, func(c Client) bool {
+			return c.ID == id
+		})
+	}
+
+	if !registered(defaultClient) {
+		clients = append(clients, Client{
+			ID:           defaultClient,
+			Scopes:       []string{"openid", "profile", "email", "offline_access", "ssh:sign"},
+			Audiences:    []string{audience},
+			GrantTypes:   []string{"authorization_code"},
+			RedirectURIs: strings.Fields(envDefault[string]("APP_AUTH_DEFAULT_REDIRECT_URIS", "")),
+		})
+	}
+
+	// the CLI is a public client logging in with the device grant
+	if !registered(cliClient) {
+		clients = append(clients, Client{
+			ID:         cliClient,
+			Scopes:     []string{"openid", "profile", "email", "offline_access", "ssh:sign"},
+			Audiences:  []string{audience},
+			GrantTypes: []string{"urn:ietf:params:oauth:grant-type:device_code"},
+		})
 	}
+
+	return clients
 }
 
-func envRequired[T interface{ time.Duration | string | int }](name string) T {
+func loadKeys(refresh time.Duration) Keys {
+	keys := Keys{
+		Algorithm: envDefault[string]("APP_JWT_ALG", "EdDSA"),
+		Rotation:  envDefault[time.Duration]("APP_JWT_KEY_ROTATION", 24*time.Hour),
+		Lead:      envDefault[time.Duration]("APP_JWT_KEY_LEAD", time.Hour),
+		Grace:     envDefault[time.Duration]("APP_JWT_KEY_GRACE", refresh),
+		Sync:      envDefault[time.Duration]("APP_JWT_KEY_SYNC", time.Minute),
+	}
+
+	// tokens signed right before a rotation must stay verifiable
+	if keys.Grace < refresh {
+		log.Fatalf("APP_JWT_KEY_GRACE must not be shorter than APP_REFRESH_TTL")
+	}
+
+	if keys.Lead >= keys.Rotation || keys.Sync >= keys.Lead {
+		log.Fatalf("jwt key timings must satisfy APP_JWT_KEY_SYNC < APP_JWT_KEY_LEAD < APP_JWT_KEY_ROTATION")
+	}
+
+	return keys
+}
+
+func loadDPoP() DPoP {
+	return DPoP{
+		RequireNonce: envDefault[bool]("APP_DPOP_REQUIRE_NONCE", false),
+		ProofWindow:  envDefault[time.Duration]("APP_DPOP_PROOF_WINDOW", time.Minute),
+		NonceTTL:     envDefault[time.Duration]("APP_DPOP_NONCE_TTL", 5*time.Minute),
+	}
+}
+
+func loadFederation() Federation {
+	var providers []FederationProvider
+
+	raw := envDefault[string]("APP_FEDERATION_PROVIDERS", "[]")
+	if err := json.Unmarshal([]byte(raw), &providers); err != nil {
+		log.Fatalf("environment variable APP_FEDERATION_PROVIDERS must be a valid json: %v", err)
+	}
+
+	for i, p := range providers {
+		if p.ID == "" || p.Issuer == "" || p.ClientID == "" {
+			log.Fatalf("federation providers need an id, issuer and client_id")
+		}
+
+		if p.Name == "" {
+			providers[i].Name = p.ID
+		}
+	}
+
+	return Federation{
+		ReturnURL: envDefault[string]("APP_FEDERATION_RETURN_URL", "/"),
+		StateTTL:  envDefault[time.Duration]("APP_FEDERATION_STATE_TTL", 10*time.Minute),
+		Providers: providers,
+	}
+}
+
+func loadSAML() SAML {
+	var tenants []SAMLTenant
+
+	raw := envDefault[string]("APP_SAML_TENANTS", "[]")
+	if err := json.Unmarshal([]byte(raw), &tenants); err != nil {
+		log.Fatalf("environment variable APP_SAML_TENANTS must be a valid json: %v", err)
+	}
+
+	for _, tenant := range tenants {
+		if tenant.ID == "" {
+			log.Fatalf("saml tenants need an id")
+		}
+
+		for i, sp := range tenant.ServiceProviders {
+			if sp.Metadata != "" {
+				var descriptor samlEntityDescriptor
+				if err := xml.Unmarshal([]byte(sp.Metadata), &descriptor); err != nil {
+					log.Fatalf("invalid saml sp metadata in tenant %s: %v", tenant.ID, err)
+				}
+
+				if sp.EntityID == "" {
+					sp.EntityID = descriptor.EntityID
+				}
+
+				for _, acs := range descriptor.AssertionConsumerServices {
+					if acs.Binding == "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" {
+						sp.ACSURLs = append(sp.ACSURLs, acs.Location)
+					}
+				}
+			}
+
+			if sp.EntityID == "" || len(sp.ACSURLs) == 0 {
+				log.Fatalf("saml service providers of tenant %s need an entity id and acs urls", tenant.ID)
+			}
+
+			tenant.ServiceProviders[i] = sp
+		}
+	}
+
+	return SAML{
+		AssertionTTL: envDefault[time.Duration]("APP_SAML_ASSERTION_TTL", 5*time.Minute),
+		Tenants:      tenants,
+	}
+}
+
+func envRequired[T interface {
+	time.Duration | string | int | bool
+}](name string) T {
 	v := os.Getenv(name)
 	if v == "" {
 		log.Fatalf("environment variable %s is required", name)
@@ -123,12 +406,20 @@ func envRequired[T interface{ time.Duration | string | int }](name string) T {
 			log.Fatalf("environment variable %s must be a valid integer: %v", name, err)
 		}
 		result = any(intVal).(T)
+	case bool:
+		boolVal, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Fatalf("environment variable %s must be a valid boolean: %v", name, err)
+		}
+		result = any(boolVal).(T)
 	}
 
 	return result
 }
 
-func envDefault[T interface{ time.Duration | string | int }](name string, def T) T {
+func envDefault[T interface {
+	time.Duration | string | int | bool
+}](name string, def T) T {
 	v := os.Getenv(name)
 	if v == "" {
 		return def
@@ -150,6 +441,12 @@ func envDefault[T interface{ time.Duration | string | int }](name string, def T)
 			log.Fatalf("environment variable %s must be a valid integer: %v", name, err)
 		}
 		result = any(intVal).(T)
+	case bool:
+		boolVal, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Fatalf("environment variable %s must be a valid boolean: %v", name, err)
+		}
+		result = any(boolVal).(T)
 	}
 
 	return result
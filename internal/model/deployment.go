@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type (
+	Deployment struct {
+		ID, Deployer, Name, State, IP string
+		LastConnection, CreateDt      time.Time
+	}
+
+	DeploymentCertificate struct {
+		Serial, Deployment, Deployer, SpiffeID string
+		NotBefore, NotAfter                    time.Time
+		// RevokedAt is also set for certificates of revoked or deleted
+		// deployments.
+		RevokedAt        *time.Time
+		RevocationReason int
+	}
+)
@@ -0,0 +1,25 @@
+package model
+
+type (
+	OpenIDConfiguration struct {
+		Issuer                            string   `json:"issuer"`
+		JwksURI                           string   `json:"jwks_uri"`
+		AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+		UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+		ScopesSupported                   []string `json:"scopes_supported"`
+		AcrValuesSupported                []string `json:"acr_values_supported"`
+		ResponseTypesSupported            []string `json:"response_types_supported"`
+		SubjectTypesSupported             []string `json:"subject_types_supported"`
+		IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+		ClaimsSupported                   []string `json:"claims_supported"`
+		RegistrationEndpoint              string   `json:"registration_endpoint"`
+		DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint"`
+		TokenEndpoint                     string   `json:"token_endpoint"`
+		GrantTypesSupported               []string `json:"grant_types_supported"`
+		TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+		CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+		RevocationEndpoint                string   `json:"revocation_endpoint"`
+		RevocationAuthMethodsSupported    []string `json:"revocation_endpoint_auth_methods_supported"`
+		DPoPSigningAlgValuesSupported     []string `json:"dpop_signing_alg_values_supported"`
+	}
+)
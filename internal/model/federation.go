@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+type (
+	// FederatedIdentity links an account of an upstream provider to a user.
+	FederatedIdentity struct {
+		Provider    string    `json:"provider"`
+		Subject     string    `json:"subject"`
+		UserEmail   string    `json:"user_email"`
+		Email       string    `json:"email"`
+		CreateDt    time.Time `json:"create_dt"`
+		LastLoginDt time.Time `json:"last_login_dt"`
+	}
+
+	// FederationState is kept between sending the browser upstream and its
+	// return to the callback.
+	FederationState struct {
+		Provider     string `json:"provider"`
+		Nonce        string `json:"nonce"`
+		CodeVerifier string `json:"code_verifier"`
+	}
+
+	FederationProvider struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		LoginURL string `json:"login_url"`
+	}
+)
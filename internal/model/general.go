@@ -8,4 +8,17 @@ type (
 		Verified, Banned             bool
 		CreateDt                     time.Time
 	}
+
+	Client struct {
+		ID           string
+		Name         string
+		SecretHash   string
+		Scopes       []string
+		Audiences    []string
+		GrantTypes   []string
+		RedirectURIs []string
+		// ThirdParty clients were registered by users and need the consent
+		// of every user they act for.
+		ThirdParty bool
+	}
 )
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+type (
+	SigningKeySet struct {
+		Keys []SigningKey `json:"keys"`
+	}
+
+	SigningKey struct {
+		Kid        string    `json:"kid"`
+		Alg        string    `json:"alg"`
+		Private    string    `json:"private"`
+		CreatedAt  time.Time `json:"created_at"`
+		ActiveFrom time.Time `json:"active_from"`
+	}
+
+	EncryptionKey struct {
+		Kid       string    `json:"kid"`
+		Audience  string    `json:"audience"`
+		Private   string    `json:"private"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// X509CA holds the DER certificate and the private key sealed with
+	// the cert secret, both base64 encoded.
+	X509CA struct {
+		Certificate string    `json:"certificate"`
+		Private     string    `json:"private"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// SAMLKey holds the DER certificate and the PKCS #8 private key the SAML
+	// identity provider signs assertions with, both base64 encoded.
+	SAMLKey struct {
+		Certificate string    `json:"certificate"`
+		Private     string    `json:"private"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+)
@@ -7,6 +7,7 @@ import (
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
 )
 
 // suppress unused package warning
@@ -17,7 +18,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel(in *jlexer.Lexer, out *User) {
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel(in *jlexer.Lexer, out *X509CA) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -31,48 +32,24 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel(in *jlexer
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "Email":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.Email = string(in.String())
-			}
-		case "Id":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.Id = string(in.String())
-			}
-		case "SshSign":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.SshSign = string(in.String())
-			}
-		case "Deployer":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.Deployer = string(in.String())
-			}
-		case "Verified":
+		case "certificate":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Verified = bool(in.Bool())
+				out.Certificate = string(in.String())
 			}
-		case "Banned":
+		case "private":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Banned = bool(in.Bool())
+				out.Private = string(in.String())
 			}
-		case "CreateDt":
+		case "created_at":
 			if in.IsNull() {
 				in.Skip()
 			} else {
 				if data := in.Raw(); in.Ok() {
-					in.AddError((out.CreateDt).UnmarshalJSON(data))
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
 				}
 			}
 		default:
@@ -85,72 +62,52 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel(in *jlexer
 		in.Consumed()
 	}
 }
-func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel(out *jwriter.Writer, in User) {
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel(out *jwriter.Writer, in X509CA) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Email\":"
+		const prefix string = ",\"certificate\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Email))
+		out.String(string(in.Certificate))
 	}
 	{
-		const prefix string = ",\"Id\":"
-		out.RawString(prefix)
-		out.String(string(in.Id))
-	}
-	{
-		const prefix string = ",\"SshSign\":"
-		out.RawString(prefix)
-		out.String(string(in.SshSign))
-	}
-	{
-		const prefix string = ",\"Deployer\":"
-		out.RawString(prefix)
-		out.String(string(in.Deployer))
-	}
-	{
-		const prefix string = ",\"Verified\":"
+		const prefix string = ",\"private\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Verified))
-	}
-	{
-		const prefix string = ",\"Banned\":"
-		out.RawString(prefix)
-		out.Bool(bool(in.Banned))
+		out.String(string(in.Private))
 	}
 	{
-		const prefix string = ",\"CreateDt\":"
+		const prefix string = ",\"created_at\":"
 		out.RawString(prefix)
-		out.Raw((in.CreateDt).MarshalJSON())
+		out.Raw((in.CreatedAt).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v User) MarshalJSON() ([]byte, error) {
+func (v X509CA) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v User) MarshalEasyJSON(w *jwriter.Writer) {
+func (v X509CA) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *User) UnmarshalJSON(data []byte) error {
+func (v *X509CA) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *X509CA) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel(l, v)
 }
-func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel1(in *jlexer.Lexer, out *SignupConfirmCodeRequest) {
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel1(in *jlexer.Lexer, out *UserInfo) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -164,23 +121,37 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel1(in *jlexe
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
+		case "sub":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Sub = string(in.String())
+			}
 		case "email":
 			if in.IsNull() {
 				in.Skip()
 			} else {
 				out.Email = string(in.String())
 			}
-		case "pwd":
+		case "email_verified":
 			if in.IsNull() {
 				in.Skip()
+				out.EmailVerified = nil
 			} else {
-				out.Pwd = string(in.String())
+				if out.EmailVerified == nil {
+					out.EmailVerified = new(bool)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					*out.EmailVerified = bool(in.Bool())
+				}
 			}
-		case "code":
+		case "deployer":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Code = string(in.String())
+				out.Deployer = string(in.String())
 			}
 		default:
 			in.SkipRecursive()
@@ -192,52 +163,57 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel1(in *jlexe
 		in.Consumed()
 	}
 }
-func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel1(out *jwriter.Writer, in SignupConfirmCodeRequest) {
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel1(out *jwriter.Writer, in UserInfo) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"sub\":"
 		out.RawString(prefix[1:])
+		out.String(string(in.Sub))
+	}
+	if in.Email != "" {
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
 		out.String(string(in.Email))
 	}
-	{
-		const prefix string = ",\"pwd\":"
+	if in.EmailVerified != nil {
+		const prefix string = ",\"email_verified\":"
 		out.RawString(prefix)
-		out.String(string(in.Pwd))
+		out.Bool(bool(*in.EmailVerified))
 	}
-	{
-		const prefix string = ",\"code\":"
+	if in.Deployer != "" {
+		const prefix string = ",\"deployer\":"
 		out.RawString(prefix)
-		out.String(string(in.Code))
+		out.String(string(in.Deployer))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v SignupConfirmCodeRequest) MarshalJSON() ([]byte, error) {
+func (v UserInfo) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v SignupConfirmCodeRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserInfo) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *SignupConfirmCodeRequest) UnmarshalJSON(data []byte) error {
+func (v *UserInfo) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *SignupConfirmCodeRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel1(l, v)
 }
-func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel2(in *jlexer.Lexer, out *SignupCheckRequest) {
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel2(in *jlexer.Lexer, out *User) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -251,12 +227,50 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel2(in *jlexe
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "email":
+		case "Email":
 			if in.IsNull() {
 				in.Skip()
 			} else {
 				out.Email = string(in.String())
 			}
+		case "Id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Id = string(in.String())
+			}
+		case "SshSign":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SshSign = string(in.String())
+			}
+		case "Deployer":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Deployer = string(in.String())
+			}
+		case "Verified":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Verified = bool(in.Bool())
+			}
+		case "Banned":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Banned = bool(in.Bool())
+			}
+		case "CreateDt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreateDt).UnmarshalJSON(data))
+				}
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -267,42 +281,72 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel2(in *jlexe
 		in.Consumed()
 	}
 }
-func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel2(out *jwriter.Writer, in SignupCheckRequest) {
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel2(out *jwriter.Writer, in User) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"Email\":"
 		out.RawString(prefix[1:])
 		out.String(string(in.Email))
 	}
+	{
+		const prefix string = ",\"Id\":"
+		out.RawString(prefix)
+		out.String(string(in.Id))
+	}
+	{
+		const prefix string = ",\"SshSign\":"
+		out.RawString(prefix)
+		out.String(string(in.SshSign))
+	}
+	{
+		const prefix string = ",\"Deployer\":"
+		out.RawString(prefix)
+		out.String(string(in.Deployer))
+	}
+	{
+		const prefix string = ",\"Verified\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Verified))
+	}
+	{
+		const prefix string = ",\"Banned\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Banned))
+	}
+	{
+		const prefix string = ",\"CreateDt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreateDt).MarshalJSON())
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v SignupCheckRequest) MarshalJSON() ([]byte, error) {
+func (v User) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v SignupCheckRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v User) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *SignupCheckRequest) UnmarshalJSON(data []byte) error {
+func (v *User) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *SignupCheckRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel2(l, v)
 }
-func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel3(in *jlexer.Lexer, out *Response) {
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel3(in *jlexer.Lexer, out *TokenResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -316,25 +360,47 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel3(in *jlexe
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "data":
-			if m, ok := out.Data.(easyjson.Unmarshaler); ok {
-				m.UnmarshalEasyJSON(in)
-			} else if m, ok := out.Data.(json.Unmarshaler); ok {
-				_ = m.UnmarshalJSON(in.Raw())
+		case "access_token":
+			if in.IsNull() {
+				in.Skip()
 			} else {
-				out.Data = in.Interface()
+				out.AccessToken = string(in.String())
 			}
-		case "message":
+		case "issued_token_type":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Message = string(in.String())
+				out.IssuedTokenType = string(in.String())
 			}
-		case "error":
+		case "token_type":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Error = string(in.String())
+				out.TokenType = string(in.String())
+			}
+		case "expires_in":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ExpiresIn = int64(in.Int64())
+			}
+		case "refresh_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RefreshToken = string(in.String())
+			}
+		case "id_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IDToken = string(in.String())
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
 			}
 		default:
 			in.SkipRecursive()
@@ -346,58 +412,72 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel3(in *jlexe
 		in.Consumed()
 	}
 }
-func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel3(out *jwriter.Writer, in Response) {
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel3(out *jwriter.Writer, in TokenResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"data\":"
+		const prefix string = ",\"access_token\":"
 		out.RawString(prefix[1:])
-		if m, ok := in.Data.(easyjson.Marshaler); ok {
-			m.MarshalEasyJSON(out)
-		} else if m, ok := in.Data.(json.Marshaler); ok {
-			out.Raw(m.MarshalJSON())
-		} else {
-			out.Raw(json.Marshal(in.Data))
-		}
+		out.String(string(in.AccessToken))
+	}
+	if in.IssuedTokenType != "" {
+		const prefix string = ",\"issued_token_type\":"
+		out.RawString(prefix)
+		out.String(string(in.IssuedTokenType))
 	}
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"token_type\":"
 		out.RawString(prefix)
-		out.String(string(in.Message))
+		out.String(string(in.TokenType))
 	}
 	{
-		const prefix string = ",\"error\":"
+		const prefix string = ",\"expires_in\":"
 		out.RawString(prefix)
-		out.String(string(in.Error))
+		out.Int64(int64(in.ExpiresIn))
+	}
+	if in.RefreshToken != "" {
+		const prefix string = ",\"refresh_token\":"
+		out.RawString(prefix)
+		out.String(string(in.RefreshToken))
+	}
+	if in.IDToken != "" {
+		const prefix string = ",\"id_token\":"
+		out.RawString(prefix)
+		out.String(string(in.IDToken))
+	}
+	if in.Scope != "" {
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Response) MarshalJSON() ([]byte, error) {
+func (v TokenResponse) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Response) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TokenResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Response) UnmarshalJSON(data []byte) error {
+func (v *TokenResponse) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Response) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TokenResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel3(l, v)
 }
-func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel4(in *jlexer.Lexer, out *GetQRCodeRequest) {
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel4(in *jlexer.Lexer, out *TokenExchangeRequest) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -411,17 +491,56 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel4(in *jlexe
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
-		case "email":
+		case "SubjectToken":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Email = string(in.String())
+				out.SubjectToken = string(in.String())
 			}
-		case "pwd":
+		case "SubjectTokenType":
 			if in.IsNull() {
 				in.Skip()
 			} else {
-				out.Pwd = string(in.String())
+				out.SubjectTokenType = string(in.String())
+			}
+		case "RequestedTokenType":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RequestedTokenType = string(in.String())
+			}
+		case "Audience":
+			if in.IsNull() {
+				in.Skip()
+				out.Audience = nil
+			} else {
+				in.Delim('[')
+				if out.Audience == nil {
+					if !in.IsDelim(']') {
+						out.Audience = make([]string, 0, 4)
+					} else {
+						out.Audience = []string{}
+					}
+				} else {
+					out.Audience = (out.Audience)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v1 = string(in.String())
+					}
+					out.Audience = append(out.Audience, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
 			}
 		default:
 			in.SkipRecursive()
@@ -433,43 +552,5947 @@ func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel4(in *jlexe
 		in.Consumed()
 	}
 }
-func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel4(out *jwriter.Writer, in GetQRCodeRequest) {
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel4(out *jwriter.Writer, in TokenExchangeRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"SubjectToken\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Email))
+		out.String(string(in.SubjectToken))
 	}
 	{
-		const prefix string = ",\"pwd\":"
+		const prefix string = ",\"SubjectTokenType\":"
 		out.RawString(prefix)
-		out.String(string(in.Pwd))
+		out.String(string(in.SubjectTokenType))
+	}
+	{
+		const prefix string = ",\"RequestedTokenType\":"
+		out.RawString(prefix)
+		out.String(string(in.RequestedTokenType))
+	}
+	{
+		const prefix string = ",\"Audience\":"
+		out.RawString(prefix)
+		if in.Audience == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Audience {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"Scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v GetQRCodeRequest) MarshalJSON() ([]byte, error) {
+func (v TokenExchangeRequest) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v GetQRCodeRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TokenExchangeRequest) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *GetQRCodeRequest) UnmarshalJSON(data []byte) error {
+func (v *TokenExchangeRequest) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *GetQRCodeRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TokenExchangeRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel4(l, v)
 }
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel5(in *jlexer.Lexer, out *SignupConfirmCodeRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "pwd":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Pwd = string(in.String())
+			}
+		case "code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Code = string(in.String())
+			}
+		case "client_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		case "audience":
+			if in.IsNull() {
+				in.Skip()
+				out.Audience = nil
+			} else {
+				in.Delim('[')
+				if out.Audience == nil {
+					if !in.IsDelim(']') {
+						out.Audience = make([]string, 0, 4)
+					} else {
+						out.Audience = []string{}
+					}
+				} else {
+					out.Audience = (out.Audience)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v4 = string(in.String())
+					}
+					out.Audience = append(out.Audience, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel5(out *jwriter.Writer, in SignupConfirmCodeRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"pwd\":"
+		out.RawString(prefix)
+		out.String(string(in.Pwd))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"client_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientID))
+	}
+	{
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	{
+		const prefix string = ",\"audience\":"
+		out.RawString(prefix)
+		if in.Audience == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Audience {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SignupConfirmCodeRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SignupConfirmCodeRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SignupConfirmCodeRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SignupConfirmCodeRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel5(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel6(in *jlexer.Lexer, out *SignupCheckRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel6(out *jwriter.Writer, in SignupCheckRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SignupCheckRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SignupCheckRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SignupCheckRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SignupCheckRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel6(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel7(in *jlexer.Lexer, out *SigningKeySet) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "keys":
+			if in.IsNull() {
+				in.Skip()
+				out.Keys = nil
+			} else {
+				in.Delim('[')
+				if out.Keys == nil {
+					if !in.IsDelim(']') {
+						out.Keys = make([]SigningKey, 0, 0)
+					} else {
+						out.Keys = []SigningKey{}
+					}
+				} else {
+					out.Keys = (out.Keys)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 SigningKey
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						(v7).UnmarshalEasyJSON(in)
+					}
+					out.Keys = append(out.Keys, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel7(out *jwriter.Writer, in SigningKeySet) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"keys\":"
+		out.RawString(prefix[1:])
+		if in.Keys == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Keys {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				(v9).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SigningKeySet) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SigningKeySet) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SigningKeySet) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SigningKeySet) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel7(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel8(in *jlexer.Lexer, out *SigningKey) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "kid":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Kid = string(in.String())
+			}
+		case "alg":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Alg = string(in.String())
+			}
+		case "private":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Private = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "active_from":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.ActiveFrom).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel8(out *jwriter.Writer, in SigningKey) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"kid\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Kid))
+	}
+	{
+		const prefix string = ",\"alg\":"
+		out.RawString(prefix)
+		out.String(string(in.Alg))
+	}
+	{
+		const prefix string = ",\"private\":"
+		out.RawString(prefix)
+		out.String(string(in.Private))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"active_from\":"
+		out.RawString(prefix)
+		out.Raw((in.ActiveFrom).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SigningKey) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SigningKey) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SigningKey) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SigningKey) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel8(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel9(in *jlexer.Lexer, out *Session) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "user":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.User = string(in.String())
+			}
+		case "user_agent":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserAgent = string(in.String())
+			}
+		case "ip":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IP = string(in.String())
+			}
+		case "mfa":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.MFA = string(in.String())
+			}
+		case "client":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Client = string(in.String())
+			}
+		case "audience":
+			if in.IsNull() {
+				in.Skip()
+				out.Audience = nil
+			} else {
+				in.Delim('[')
+				if out.Audience == nil {
+					if !in.IsDelim(']') {
+						out.Audience = make([]string, 0, 4)
+					} else {
+						out.Audience = []string{}
+					}
+				} else {
+					out.Audience = (out.Audience)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v10 = string(in.String())
+					}
+					out.Audience = append(out.Audience, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		case "dpop_jkt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DPoPJKT = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "last_used_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.LastUsedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel9(out *jwriter.Writer, in Session) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.String(string(in.User))
+	}
+	{
+		const prefix string = ",\"user_agent\":"
+		out.RawString(prefix)
+		out.String(string(in.UserAgent))
+	}
+	{
+		const prefix string = ",\"ip\":"
+		out.RawString(prefix)
+		out.String(string(in.IP))
+	}
+	{
+		const prefix string = ",\"mfa\":"
+		out.RawString(prefix)
+		out.String(string(in.MFA))
+	}
+	{
+		const prefix string = ",\"client\":"
+		out.RawString(prefix)
+		out.String(string(in.Client))
+	}
+	{
+		const prefix string = ",\"audience\":"
+		out.RawString(prefix)
+		if in.Audience == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Audience {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v12))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	if in.DPoPJKT != "" {
+		const prefix string = ",\"dpop_jkt\":"
+		out.RawString(prefix)
+		out.String(string(in.DPoPJKT))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"last_used_at\":"
+		out.RawString(prefix)
+		out.Raw((in.LastUsedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Session) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Session) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Session) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Session) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel9(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel10(in *jlexer.Lexer, out *SSHCertificateResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "certificate":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Certificate = string(in.String())
+			}
+		case "serial":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Serial = uint64(in.Uint64())
+			}
+		case "principals":
+			if in.IsNull() {
+				in.Skip()
+				out.Principals = nil
+			} else {
+				in.Delim('[')
+				if out.Principals == nil {
+					if !in.IsDelim(']') {
+						out.Principals = make([]string, 0, 4)
+					} else {
+						out.Principals = []string{}
+					}
+				} else {
+					out.Principals = (out.Principals)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v13 = string(in.String())
+					}
+					out.Principals = append(out.Principals, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "valid_before":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ValidBefore = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel10(out *jwriter.Writer, in SSHCertificateResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"certificate\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Certificate))
+	}
+	{
+		const prefix string = ",\"serial\":"
+		out.RawString(prefix)
+		out.Uint64(uint64(in.Serial))
+	}
+	{
+		const prefix string = ",\"principals\":"
+		out.RawString(prefix)
+		if in.Principals == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Principals {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v15))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"valid_before\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ValidBefore))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SSHCertificateResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SSHCertificateResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SSHCertificateResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SSHCertificateResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel10(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel11(in *jlexer.Lexer, out *SSHCertificateRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "public_key":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PublicKey = string(in.String())
+			}
+		case "source_address":
+			if in.IsNull() {
+				in.Skip()
+				out.SourceAddress = nil
+			} else {
+				in.Delim('[')
+				if out.SourceAddress == nil {
+					if !in.IsDelim(']') {
+						out.SourceAddress = make([]string, 0, 4)
+					} else {
+						out.SourceAddress = []string{}
+					}
+				} else {
+					out.SourceAddress = (out.SourceAddress)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v16 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v16 = string(in.String())
+					}
+					out.SourceAddress = append(out.SourceAddress, v16)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel11(out *jwriter.Writer, in SSHCertificateRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"public_key\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.PublicKey))
+	}
+	{
+		const prefix string = ",\"source_address\":"
+		out.RawString(prefix)
+		if in.SourceAddress == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.SourceAddress {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v18))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SSHCertificateRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SSHCertificateRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SSHCertificateRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SSHCertificateRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel11(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel12(in *jlexer.Lexer, out *SAMLKey) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "certificate":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Certificate = string(in.String())
+			}
+		case "private":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Private = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel12(out *jwriter.Writer, in SAMLKey) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"certificate\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Certificate))
+	}
+	{
+		const prefix string = ",\"private\":"
+		out.RawString(prefix)
+		out.String(string(in.Private))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SAMLKey) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SAMLKey) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SAMLKey) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SAMLKey) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel12(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel13(in *jlexer.Lexer, out *Response) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "data":
+			if m, ok := out.Data.(easyjson.Unmarshaler); ok {
+				m.UnmarshalEasyJSON(in)
+			} else if m, ok := out.Data.(json.Unmarshaler); ok {
+				_ = m.UnmarshalJSON(in.Raw())
+			} else {
+				out.Data = in.Interface()
+			}
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Message = string(in.String())
+			}
+		case "error":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Error = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel13(out *jwriter.Writer, in Response) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"data\":"
+		out.RawString(prefix[1:])
+		if m, ok := in.Data.(easyjson.Marshaler); ok {
+			m.MarshalEasyJSON(out)
+		} else if m, ok := in.Data.(json.Marshaler); ok {
+			out.Raw(m.MarshalJSON())
+		} else {
+			out.Raw(json.Marshal(in.Data))
+		}
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	{
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Response) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Response) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Response) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Response) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel13(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel14(in *jlexer.Lexer, out *RegisteredClient) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "Name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "Owner":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Owner = string(in.String())
+			}
+		case "Status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "TokenEndpointAuthMethod":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TokenEndpointAuthMethod = string(in.String())
+			}
+		case "RegistrationTokenHash":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RegistrationTokenHash = string(in.String())
+			}
+		case "CreateDt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreateDt).UnmarshalJSON(data))
+				}
+			}
+		case "ID":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "SecretHash":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SecretHash = string(in.String())
+			}
+		case "Scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v19 = string(in.String())
+					}
+					out.Scopes = append(out.Scopes, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Audiences":
+			if in.IsNull() {
+				in.Skip()
+				out.Audiences = nil
+			} else {
+				in.Delim('[')
+				if out.Audiences == nil {
+					if !in.IsDelim(']') {
+						out.Audiences = make([]string, 0, 4)
+					} else {
+						out.Audiences = []string{}
+					}
+				} else {
+					out.Audiences = (out.Audiences)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v20 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v20 = string(in.String())
+					}
+					out.Audiences = append(out.Audiences, v20)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "GrantTypes":
+			if in.IsNull() {
+				in.Skip()
+				out.GrantTypes = nil
+			} else {
+				in.Delim('[')
+				if out.GrantTypes == nil {
+					if !in.IsDelim(']') {
+						out.GrantTypes = make([]string, 0, 4)
+					} else {
+						out.GrantTypes = []string{}
+					}
+				} else {
+					out.GrantTypes = (out.GrantTypes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v21 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v21 = string(in.String())
+					}
+					out.GrantTypes = append(out.GrantTypes, v21)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "RedirectURIs":
+			if in.IsNull() {
+				in.Skip()
+				out.RedirectURIs = nil
+			} else {
+				in.Delim('[')
+				if out.RedirectURIs == nil {
+					if !in.IsDelim(']') {
+						out.RedirectURIs = make([]string, 0, 4)
+					} else {
+						out.RedirectURIs = []string{}
+					}
+				} else {
+					out.RedirectURIs = (out.RedirectURIs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v22 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v22 = string(in.String())
+					}
+					out.RedirectURIs = append(out.RedirectURIs, v22)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "ThirdParty":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ThirdParty = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel14(out *jwriter.Writer, in RegisteredClient) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"Owner\":"
+		out.RawString(prefix)
+		out.String(string(in.Owner))
+	}
+	{
+		const prefix string = ",\"Status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"TokenEndpointAuthMethod\":"
+		out.RawString(prefix)
+		out.String(string(in.TokenEndpointAuthMethod))
+	}
+	{
+		const prefix string = ",\"RegistrationTokenHash\":"
+		out.RawString(prefix)
+		out.String(string(in.RegistrationTokenHash))
+	}
+	{
+		const prefix string = ",\"CreateDt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreateDt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"ID\":"
+		out.RawString(prefix)
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"SecretHash\":"
+		out.RawString(prefix)
+		out.String(string(in.SecretHash))
+	}
+	{
+		const prefix string = ",\"Scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v23, v24 := range in.Scopes {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v24))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"Audiences\":"
+		out.RawString(prefix)
+		if in.Audiences == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v25, v26 := range in.Audiences {
+				if v25 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v26))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"GrantTypes\":"
+		out.RawString(prefix)
+		if in.GrantTypes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v27, v28 := range in.GrantTypes {
+				if v27 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v28))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"RedirectURIs\":"
+		out.RawString(prefix)
+		if in.RedirectURIs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v29, v30 := range in.RedirectURIs {
+				if v29 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v30))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"ThirdParty\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.ThirdParty))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RegisteredClient) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RegisteredClient) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RegisteredClient) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RegisteredClient) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel14(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel15(in *jlexer.Lexer, out *RefreshTokenRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "refresh_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RefreshToken = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel15(out *jwriter.Writer, in RefreshTokenRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"refresh_token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.RefreshToken))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RefreshTokenRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel15(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RefreshTokenRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel15(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RefreshTokenRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel15(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RefreshTokenRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel15(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel16(in *jlexer.Lexer, out *RefreshFamily) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "user":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.User = string(in.String())
+			}
+		case "current":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Current = string(in.String())
+			}
+		case "revoked":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Revoked = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel16(out *jwriter.Writer, in RefreshFamily) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.User))
+	}
+	{
+		const prefix string = ",\"current\":"
+		out.RawString(prefix)
+		out.String(string(in.Current))
+	}
+	{
+		const prefix string = ",\"revoked\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Revoked))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RefreshFamily) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RefreshFamily) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RefreshFamily) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RefreshFamily) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel16(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel17(in *jlexer.Lexer, out *OpenIDConfiguration) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "issuer":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Issuer = string(in.String())
+			}
+		case "jwks_uri":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.JwksURI = string(in.String())
+			}
+		case "authorization_endpoint":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AuthorizationEndpoint = string(in.String())
+			}
+		case "userinfo_endpoint":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserinfoEndpoint = string(in.String())
+			}
+		case "scopes_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.ScopesSupported = nil
+			} else {
+				in.Delim('[')
+				if out.ScopesSupported == nil {
+					if !in.IsDelim(']') {
+						out.ScopesSupported = make([]string, 0, 4)
+					} else {
+						out.ScopesSupported = []string{}
+					}
+				} else {
+					out.ScopesSupported = (out.ScopesSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v31 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v31 = string(in.String())
+					}
+					out.ScopesSupported = append(out.ScopesSupported, v31)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "acr_values_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.AcrValuesSupported = nil
+			} else {
+				in.Delim('[')
+				if out.AcrValuesSupported == nil {
+					if !in.IsDelim(']') {
+						out.AcrValuesSupported = make([]string, 0, 4)
+					} else {
+						out.AcrValuesSupported = []string{}
+					}
+				} else {
+					out.AcrValuesSupported = (out.AcrValuesSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v32 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v32 = string(in.String())
+					}
+					out.AcrValuesSupported = append(out.AcrValuesSupported, v32)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "response_types_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.ResponseTypesSupported = nil
+			} else {
+				in.Delim('[')
+				if out.ResponseTypesSupported == nil {
+					if !in.IsDelim(']') {
+						out.ResponseTypesSupported = make([]string, 0, 4)
+					} else {
+						out.ResponseTypesSupported = []string{}
+					}
+				} else {
+					out.ResponseTypesSupported = (out.ResponseTypesSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v33 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v33 = string(in.String())
+					}
+					out.ResponseTypesSupported = append(out.ResponseTypesSupported, v33)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "subject_types_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.SubjectTypesSupported = nil
+			} else {
+				in.Delim('[')
+				if out.SubjectTypesSupported == nil {
+					if !in.IsDelim(']') {
+						out.SubjectTypesSupported = make([]string, 0, 4)
+					} else {
+						out.SubjectTypesSupported = []string{}
+					}
+				} else {
+					out.SubjectTypesSupported = (out.SubjectTypesSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v34 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v34 = string(in.String())
+					}
+					out.SubjectTypesSupported = append(out.SubjectTypesSupported, v34)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "id_token_signing_alg_values_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.IDTokenSigningAlgValuesSupported = nil
+			} else {
+				in.Delim('[')
+				if out.IDTokenSigningAlgValuesSupported == nil {
+					if !in.IsDelim(']') {
+						out.IDTokenSigningAlgValuesSupported = make([]string, 0, 4)
+					} else {
+						out.IDTokenSigningAlgValuesSupported = []string{}
+					}
+				} else {
+					out.IDTokenSigningAlgValuesSupported = (out.IDTokenSigningAlgValuesSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v35 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v35 = string(in.String())
+					}
+					out.IDTokenSigningAlgValuesSupported = append(out.IDTokenSigningAlgValuesSupported, v35)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "claims_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.ClaimsSupported = nil
+			} else {
+				in.Delim('[')
+				if out.ClaimsSupported == nil {
+					if !in.IsDelim(']') {
+						out.ClaimsSupported = make([]string, 0, 4)
+					} else {
+						out.ClaimsSupported = []string{}
+					}
+				} else {
+					out.ClaimsSupported = (out.ClaimsSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v36 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v36 = string(in.String())
+					}
+					out.ClaimsSupported = append(out.ClaimsSupported, v36)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "registration_endpoint":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RegistrationEndpoint = string(in.String())
+			}
+		case "device_authorization_endpoint":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DeviceAuthorizationEndpoint = string(in.String())
+			}
+		case "token_endpoint":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TokenEndpoint = string(in.String())
+			}
+		case "grant_types_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.GrantTypesSupported = nil
+			} else {
+				in.Delim('[')
+				if out.GrantTypesSupported == nil {
+					if !in.IsDelim(']') {
+						out.GrantTypesSupported = make([]string, 0, 4)
+					} else {
+						out.GrantTypesSupported = []string{}
+					}
+				} else {
+					out.GrantTypesSupported = (out.GrantTypesSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v37 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v37 = string(in.String())
+					}
+					out.GrantTypesSupported = append(out.GrantTypesSupported, v37)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "token_endpoint_auth_methods_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.TokenEndpointAuthMethodsSupported = nil
+			} else {
+				in.Delim('[')
+				if out.TokenEndpointAuthMethodsSupported == nil {
+					if !in.IsDelim(']') {
+						out.TokenEndpointAuthMethodsSupported = make([]string, 0, 4)
+					} else {
+						out.TokenEndpointAuthMethodsSupported = []string{}
+					}
+				} else {
+					out.TokenEndpointAuthMethodsSupported = (out.TokenEndpointAuthMethodsSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v38 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v38 = string(in.String())
+					}
+					out.TokenEndpointAuthMethodsSupported = append(out.TokenEndpointAuthMethodsSupported, v38)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "code_challenge_methods_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.CodeChallengeMethodsSupported = nil
+			} else {
+				in.Delim('[')
+				if out.CodeChallengeMethodsSupported == nil {
+					if !in.IsDelim(']') {
+						out.CodeChallengeMethodsSupported = make([]string, 0, 4)
+					} else {
+						out.CodeChallengeMethodsSupported = []string{}
+					}
+				} else {
+					out.CodeChallengeMethodsSupported = (out.CodeChallengeMethodsSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v39 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v39 = string(in.String())
+					}
+					out.CodeChallengeMethodsSupported = append(out.CodeChallengeMethodsSupported, v39)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "revocation_endpoint":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RevocationEndpoint = string(in.String())
+			}
+		case "revocation_endpoint_auth_methods_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.RevocationAuthMethodsSupported = nil
+			} else {
+				in.Delim('[')
+				if out.RevocationAuthMethodsSupported == nil {
+					if !in.IsDelim(']') {
+						out.RevocationAuthMethodsSupported = make([]string, 0, 4)
+					} else {
+						out.RevocationAuthMethodsSupported = []string{}
+					}
+				} else {
+					out.RevocationAuthMethodsSupported = (out.RevocationAuthMethodsSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v40 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v40 = string(in.String())
+					}
+					out.RevocationAuthMethodsSupported = append(out.RevocationAuthMethodsSupported, v40)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "dpop_signing_alg_values_supported":
+			if in.IsNull() {
+				in.Skip()
+				out.DPoPSigningAlgValuesSupported = nil
+			} else {
+				in.Delim('[')
+				if out.DPoPSigningAlgValuesSupported == nil {
+					if !in.IsDelim(']') {
+						out.DPoPSigningAlgValuesSupported = make([]string, 0, 4)
+					} else {
+						out.DPoPSigningAlgValuesSupported = []string{}
+					}
+				} else {
+					out.DPoPSigningAlgValuesSupported = (out.DPoPSigningAlgValuesSupported)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v41 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v41 = string(in.String())
+					}
+					out.DPoPSigningAlgValuesSupported = append(out.DPoPSigningAlgValuesSupported, v41)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel17(out *jwriter.Writer, in OpenIDConfiguration) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"issuer\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Issuer))
+	}
+	{
+		const prefix string = ",\"jwks_uri\":"
+		out.RawString(prefix)
+		out.String(string(in.JwksURI))
+	}
+	{
+		const prefix string = ",\"authorization_endpoint\":"
+		out.RawString(prefix)
+		out.String(string(in.AuthorizationEndpoint))
+	}
+	{
+		const prefix string = ",\"userinfo_endpoint\":"
+		out.RawString(prefix)
+		out.String(string(in.UserinfoEndpoint))
+	}
+	{
+		const prefix string = ",\"scopes_supported\":"
+		out.RawString(prefix)
+		if in.ScopesSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v42, v43 := range in.ScopesSupported {
+				if v42 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v43))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"acr_values_supported\":"
+		out.RawString(prefix)
+		if in.AcrValuesSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v44, v45 := range in.AcrValuesSupported {
+				if v44 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v45))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"response_types_supported\":"
+		out.RawString(prefix)
+		if in.ResponseTypesSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v46, v47 := range in.ResponseTypesSupported {
+				if v46 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v47))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"subject_types_supported\":"
+		out.RawString(prefix)
+		if in.SubjectTypesSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v48, v49 := range in.SubjectTypesSupported {
+				if v48 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v49))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"id_token_signing_alg_values_supported\":"
+		out.RawString(prefix)
+		if in.IDTokenSigningAlgValuesSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v50, v51 := range in.IDTokenSigningAlgValuesSupported {
+				if v50 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v51))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"claims_supported\":"
+		out.RawString(prefix)
+		if in.ClaimsSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v52, v53 := range in.ClaimsSupported {
+				if v52 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v53))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"registration_endpoint\":"
+		out.RawString(prefix)
+		out.String(string(in.RegistrationEndpoint))
+	}
+	{
+		const prefix string = ",\"device_authorization_endpoint\":"
+		out.RawString(prefix)
+		out.String(string(in.DeviceAuthorizationEndpoint))
+	}
+	{
+		const prefix string = ",\"token_endpoint\":"
+		out.RawString(prefix)
+		out.String(string(in.TokenEndpoint))
+	}
+	{
+		const prefix string = ",\"grant_types_supported\":"
+		out.RawString(prefix)
+		if in.GrantTypesSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v54, v55 := range in.GrantTypesSupported {
+				if v54 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v55))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"token_endpoint_auth_methods_supported\":"
+		out.RawString(prefix)
+		if in.TokenEndpointAuthMethodsSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v56, v57 := range in.TokenEndpointAuthMethodsSupported {
+				if v56 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v57))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"code_challenge_methods_supported\":"
+		out.RawString(prefix)
+		if in.CodeChallengeMethodsSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v58, v59 := range in.CodeChallengeMethodsSupported {
+				if v58 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v59))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"revocation_endpoint\":"
+		out.RawString(prefix)
+		out.String(string(in.RevocationEndpoint))
+	}
+	{
+		const prefix string = ",\"revocation_endpoint_auth_methods_supported\":"
+		out.RawString(prefix)
+		if in.RevocationAuthMethodsSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v60, v61 := range in.RevocationAuthMethodsSupported {
+				if v60 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v61))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"dpop_signing_alg_values_supported\":"
+		out.RawString(prefix)
+		if in.DPoPSigningAlgValuesSupported == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v62, v63 := range in.DPoPSigningAlgValuesSupported {
+				if v62 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v63))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OpenIDConfiguration) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OpenIDConfiguration) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OpenIDConfiguration) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OpenIDConfiguration) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel17(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel18(in *jlexer.Lexer, out *OAuthError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "error":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Error = string(in.String())
+			}
+		case "error_description":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ErrorDescription = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel18(out *jwriter.Writer, in OAuthError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"error\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Error))
+	}
+	if in.ErrorDescription != "" {
+		const prefix string = ",\"error_description\":"
+		out.RawString(prefix)
+		out.String(string(in.ErrorDescription))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v OAuthError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OAuthError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *OAuthError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OAuthError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel18(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel19(in *jlexer.Lexer, out *IntrospectionResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "active":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Active = bool(in.Bool())
+			}
+		case "revoked":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Revoked = bool(in.Bool())
+			}
+		case "sub":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Sub = string(in.String())
+			}
+		case "session":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Session = string(in.String())
+			}
+		case "deployer":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Deployer = string(in.String())
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		case "client_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "aud":
+			if in.IsNull() {
+				in.Skip()
+				out.Aud = nil
+			} else {
+				in.Delim('[')
+				if out.Aud == nil {
+					if !in.IsDelim(']') {
+						out.Aud = make([]string, 0, 4)
+					} else {
+						out.Aud = []string{}
+					}
+				} else {
+					out.Aud = (out.Aud)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v64 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v64 = string(in.String())
+					}
+					out.Aud = append(out.Aud, v64)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "cnf":
+			if in.IsNull() {
+				in.Skip()
+				out.Cnf = nil
+			} else {
+				if out.Cnf == nil {
+					out.Cnf = new(Confirmation)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*out.Cnf).UnmarshalEasyJSON(in)
+				}
+			}
+		case "act":
+			if in.IsNull() {
+				in.Skip()
+				out.Act = nil
+			} else {
+				if out.Act == nil {
+					out.Act = new(Actor)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*out.Act).UnmarshalEasyJSON(in)
+				}
+			}
+		case "token_type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TokenType = string(in.String())
+			}
+		case "iss":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Iss = string(in.String())
+			}
+		case "jti":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Jti = string(in.String())
+			}
+		case "exp":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Exp = int64(in.Int64())
+			}
+		case "iat":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Iat = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel19(out *jwriter.Writer, in IntrospectionResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"active\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Active))
+	}
+	if in.Revoked {
+		const prefix string = ",\"revoked\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Revoked))
+	}
+	if in.Sub != "" {
+		const prefix string = ",\"sub\":"
+		out.RawString(prefix)
+		out.String(string(in.Sub))
+	}
+	if in.Session != "" {
+		const prefix string = ",\"session\":"
+		out.RawString(prefix)
+		out.String(string(in.Session))
+	}
+	if in.Deployer != "" {
+		const prefix string = ",\"deployer\":"
+		out.RawString(prefix)
+		out.String(string(in.Deployer))
+	}
+	if in.Scope != "" {
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	if in.ClientID != "" {
+		const prefix string = ",\"client_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientID))
+	}
+	if len(in.Aud) != 0 {
+		const prefix string = ",\"aud\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v65, v66 := range in.Aud {
+				if v65 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v66))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Cnf != nil {
+		const prefix string = ",\"cnf\":"
+		out.RawString(prefix)
+		(*in.Cnf).MarshalEasyJSON(out)
+	}
+	if in.Act != nil {
+		const prefix string = ",\"act\":"
+		out.RawString(prefix)
+		(*in.Act).MarshalEasyJSON(out)
+	}
+	if in.TokenType != "" {
+		const prefix string = ",\"token_type\":"
+		out.RawString(prefix)
+		out.String(string(in.TokenType))
+	}
+	if in.Iss != "" {
+		const prefix string = ",\"iss\":"
+		out.RawString(prefix)
+		out.String(string(in.Iss))
+	}
+	if in.Jti != "" {
+		const prefix string = ",\"jti\":"
+		out.RawString(prefix)
+		out.String(string(in.Jti))
+	}
+	if in.Exp != 0 {
+		const prefix string = ",\"exp\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Exp))
+	}
+	if in.Iat != 0 {
+		const prefix string = ",\"iat\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Iat))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v IntrospectionResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v IntrospectionResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *IntrospectionResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *IntrospectionResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel19(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel20(in *jlexer.Lexer, out *GetQRCodeRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "pwd":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Pwd = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel20(out *jwriter.Writer, in GetQRCodeRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"pwd\":"
+		out.RawString(prefix)
+		out.String(string(in.Pwd))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v GetQRCodeRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel20(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetQRCodeRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel20(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *GetQRCodeRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel20(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetQRCodeRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel20(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel21(in *jlexer.Lexer, out *FederationState) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "provider":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Provider = string(in.String())
+			}
+		case "nonce":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Nonce = string(in.String())
+			}
+		case "code_verifier":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CodeVerifier = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel21(out *jwriter.Writer, in FederationState) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"provider\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Provider))
+	}
+	{
+		const prefix string = ",\"nonce\":"
+		out.RawString(prefix)
+		out.String(string(in.Nonce))
+	}
+	{
+		const prefix string = ",\"code_verifier\":"
+		out.RawString(prefix)
+		out.String(string(in.CodeVerifier))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FederationState) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FederationState) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FederationState) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FederationState) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel21(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel22(in *jlexer.Lexer, out *FederationProvider) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "login_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LoginURL = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel22(out *jwriter.Writer, in FederationProvider) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"login_url\":"
+		out.RawString(prefix)
+		out.String(string(in.LoginURL))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FederationProvider) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FederationProvider) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FederationProvider) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FederationProvider) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel22(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel23(in *jlexer.Lexer, out *FederatedIdentity) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "provider":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Provider = string(in.String())
+			}
+		case "subject":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Subject = string(in.String())
+			}
+		case "user_email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserEmail = string(in.String())
+			}
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "create_dt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreateDt).UnmarshalJSON(data))
+				}
+			}
+		case "last_login_dt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.LastLoginDt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel23(out *jwriter.Writer, in FederatedIdentity) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"provider\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Provider))
+	}
+	{
+		const prefix string = ",\"subject\":"
+		out.RawString(prefix)
+		out.String(string(in.Subject))
+	}
+	{
+		const prefix string = ",\"user_email\":"
+		out.RawString(prefix)
+		out.String(string(in.UserEmail))
+	}
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"create_dt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreateDt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"last_login_dt\":"
+		out.RawString(prefix)
+		out.Raw((in.LastLoginDt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FederatedIdentity) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FederatedIdentity) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FederatedIdentity) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FederatedIdentity) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel23(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel24(in *jlexer.Lexer, out *EncryptionKey) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "kid":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Kid = string(in.String())
+			}
+		case "audience":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Audience = string(in.String())
+			}
+		case "private":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Private = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel24(out *jwriter.Writer, in EncryptionKey) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"kid\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Kid))
+	}
+	{
+		const prefix string = ",\"audience\":"
+		out.RawString(prefix)
+		out.String(string(in.Audience))
+	}
+	{
+		const prefix string = ",\"private\":"
+		out.RawString(prefix)
+		out.String(string(in.Private))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v EncryptionKey) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v EncryptionKey) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *EncryptionKey) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *EncryptionKey) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel24(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel25(in *jlexer.Lexer, out *DeviceAuthorizationResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "device_code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DeviceCode = string(in.String())
+			}
+		case "user_code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserCode = string(in.String())
+			}
+		case "verification_uri":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.VerificationURI = string(in.String())
+			}
+		case "verification_uri_complete":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.VerificationURIComplete = string(in.String())
+			}
+		case "expires_in":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ExpiresIn = int64(in.Int64())
+			}
+		case "interval":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Interval = int64(in.Int64())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel25(out *jwriter.Writer, in DeviceAuthorizationResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"device_code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.DeviceCode))
+	}
+	{
+		const prefix string = ",\"user_code\":"
+		out.RawString(prefix)
+		out.String(string(in.UserCode))
+	}
+	{
+		const prefix string = ",\"verification_uri\":"
+		out.RawString(prefix)
+		out.String(string(in.VerificationURI))
+	}
+	{
+		const prefix string = ",\"verification_uri_complete\":"
+		out.RawString(prefix)
+		out.String(string(in.VerificationURIComplete))
+	}
+	{
+		const prefix string = ",\"expires_in\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ExpiresIn))
+	}
+	{
+		const prefix string = ",\"interval\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Interval))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DeviceAuthorizationResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DeviceAuthorizationResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DeviceAuthorizationResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DeviceAuthorizationResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel25(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel26(in *jlexer.Lexer, out *DeviceAuthorization) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "client_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "user_code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserCode = string(in.String())
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		case "audience":
+			if in.IsNull() {
+				in.Skip()
+				out.Audience = nil
+			} else {
+				in.Delim('[')
+				if out.Audience == nil {
+					if !in.IsDelim(']') {
+						out.Audience = make([]string, 0, 4)
+					} else {
+						out.Audience = []string{}
+					}
+				} else {
+					out.Audience = (out.Audience)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v67 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v67 = string(in.String())
+					}
+					out.Audience = append(out.Audience, v67)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "interval":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Interval = time.Duration(in.Int64())
+			}
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "ticket":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Ticket = string(in.String())
+			}
+		case "amr":
+			if in.IsNull() {
+				in.Skip()
+				out.AMR = nil
+			} else {
+				in.Delim('[')
+				if out.AMR == nil {
+					if !in.IsDelim(']') {
+						out.AMR = make([]string, 0, 4)
+					} else {
+						out.AMR = []string{}
+					}
+				} else {
+					out.AMR = (out.AMR)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v68 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v68 = string(in.String())
+					}
+					out.AMR = append(out.AMR, v68)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "mfa":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.MFA = string(in.String())
+			}
+		case "user_agent":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserAgent = string(in.String())
+			}
+		case "ip":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IP = string(in.String())
+			}
+		case "auth_time":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.AuthTime).UnmarshalJSON(data))
+				}
+			}
+		case "expires_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.ExpiresAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel26(out *jwriter.Writer, in DeviceAuthorization) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"client_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ClientID))
+	}
+	{
+		const prefix string = ",\"user_code\":"
+		out.RawString(prefix)
+		out.String(string(in.UserCode))
+	}
+	{
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	{
+		const prefix string = ",\"audience\":"
+		out.RawString(prefix)
+		if in.Audience == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v69, v70 := range in.Audience {
+				if v69 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v70))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"interval\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Interval))
+	}
+	if in.Email != "" {
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	if in.Ticket != "" {
+		const prefix string = ",\"ticket\":"
+		out.RawString(prefix)
+		out.String(string(in.Ticket))
+	}
+	if len(in.AMR) != 0 {
+		const prefix string = ",\"amr\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v71, v72 := range in.AMR {
+				if v71 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v72))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.MFA != "" {
+		const prefix string = ",\"mfa\":"
+		out.RawString(prefix)
+		out.String(string(in.MFA))
+	}
+	{
+		const prefix string = ",\"user_agent\":"
+		out.RawString(prefix)
+		out.String(string(in.UserAgent))
+	}
+	{
+		const prefix string = ",\"ip\":"
+		out.RawString(prefix)
+		out.String(string(in.IP))
+	}
+	{
+		const prefix string = ",\"auth_time\":"
+		out.RawString(prefix)
+		out.Raw((in.AuthTime).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((in.ExpiresAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DeviceAuthorization) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DeviceAuthorization) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DeviceAuthorization) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DeviceAuthorization) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel26(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel27(in *jlexer.Lexer, out *DeploymentCertificate) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "Serial":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Serial = string(in.String())
+			}
+		case "Deployment":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Deployment = string(in.String())
+			}
+		case "Deployer":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Deployer = string(in.String())
+			}
+		case "SpiffeID":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SpiffeID = string(in.String())
+			}
+		case "NotBefore":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.NotBefore).UnmarshalJSON(data))
+				}
+			}
+		case "NotAfter":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.NotAfter).UnmarshalJSON(data))
+				}
+			}
+		case "RevokedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.RevokedAt = nil
+			} else {
+				if out.RevokedAt == nil {
+					out.RevokedAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.RevokedAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "RevocationReason":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RevocationReason = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel27(out *jwriter.Writer, in DeploymentCertificate) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Serial\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Serial))
+	}
+	{
+		const prefix string = ",\"Deployment\":"
+		out.RawString(prefix)
+		out.String(string(in.Deployment))
+	}
+	{
+		const prefix string = ",\"Deployer\":"
+		out.RawString(prefix)
+		out.String(string(in.Deployer))
+	}
+	{
+		const prefix string = ",\"SpiffeID\":"
+		out.RawString(prefix)
+		out.String(string(in.SpiffeID))
+	}
+	{
+		const prefix string = ",\"NotBefore\":"
+		out.RawString(prefix)
+		out.Raw((in.NotBefore).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"NotAfter\":"
+		out.RawString(prefix)
+		out.Raw((in.NotAfter).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"RevokedAt\":"
+		out.RawString(prefix)
+		if in.RevokedAt == nil {
+			out.RawString("null")
+		} else {
+			out.Raw((*in.RevokedAt).MarshalJSON())
+		}
+	}
+	{
+		const prefix string = ",\"RevocationReason\":"
+		out.RawString(prefix)
+		out.Int(int(in.RevocationReason))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v DeploymentCertificate) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel27(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v DeploymentCertificate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel27(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *DeploymentCertificate) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel27(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *DeploymentCertificate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel27(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel28(in *jlexer.Lexer, out *Deployment) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "ID":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "Deployer":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Deployer = string(in.String())
+			}
+		case "Name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "State":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.State = string(in.String())
+			}
+		case "IP":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IP = string(in.String())
+			}
+		case "LastConnection":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.LastConnection).UnmarshalJSON(data))
+				}
+			}
+		case "CreateDt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreateDt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel28(out *jwriter.Writer, in Deployment) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"ID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"Deployer\":"
+		out.RawString(prefix)
+		out.String(string(in.Deployer))
+	}
+	{
+		const prefix string = ",\"Name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"State\":"
+		out.RawString(prefix)
+		out.String(string(in.State))
+	}
+	{
+		const prefix string = ",\"IP\":"
+		out.RawString(prefix)
+		out.String(string(in.IP))
+	}
+	{
+		const prefix string = ",\"LastConnection\":"
+		out.RawString(prefix)
+		out.Raw((in.LastConnection).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"CreateDt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreateDt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Deployment) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel28(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Deployment) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel28(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Deployment) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel28(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Deployment) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel28(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel29(in *jlexer.Lexer, out *ConsentRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "request":
+			if in.IsNull() {
+				in.Skip()
+				out.Request = nil
+			} else {
+				if out.Request == nil {
+					out.Request = new(AuthorizationRequest)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*out.Request).UnmarshalEasyJSON(in)
+				}
+			}
+		case "client_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientName = string(in.String())
+			}
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v73 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v73 = string(in.String())
+					}
+					out.Scopes = append(out.Scopes, v73)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "user_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserID = string(in.String())
+			}
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "user_agent":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserAgent = string(in.String())
+			}
+		case "ip":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IP = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel29(out *jwriter.Writer, in ConsentRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"request\":"
+		out.RawString(prefix)
+		if in.Request == nil {
+			out.RawString("null")
+		} else {
+			(*in.Request).MarshalEasyJSON(out)
+		}
+	}
+	{
+		const prefix string = ",\"client_name\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientName))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v74, v75 := range in.Scopes {
+				if v74 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v75))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix)
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"user_agent\":"
+		out.RawString(prefix)
+		out.String(string(in.UserAgent))
+	}
+	{
+		const prefix string = ",\"ip\":"
+		out.RawString(prefix)
+		out.String(string(in.IP))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ConsentRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel29(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ConsentRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel29(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ConsentRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel29(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ConsentRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel29(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel30(in *jlexer.Lexer, out *Consent) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "client_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "client_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientName = string(in.String())
+			}
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v76 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v76 = string(in.String())
+					}
+					out.Scopes = append(out.Scopes, v76)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "granted_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreateDt).UnmarshalJSON(data))
+				}
+			}
+		case "updated_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.UpdateDt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel30(out *jwriter.Writer, in Consent) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"client_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ClientID))
+	}
+	{
+		const prefix string = ",\"client_name\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientName))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v77, v78 := range in.Scopes {
+				if v77 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v78))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"granted_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreateDt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		out.Raw((in.UpdateDt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Consent) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel30(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Consent) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel30(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Consent) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel30(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Consent) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel30(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel31(in *jlexer.Lexer, out *Confirmation) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "jkt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.JKT = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel31(out *jwriter.Writer, in Confirmation) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"jkt\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.JKT))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Confirmation) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel31(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Confirmation) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel31(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Confirmation) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel31(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Confirmation) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel31(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel32(in *jlexer.Lexer, out *ClientRegistration) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "client_secret":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientSecret = string(in.String())
+			}
+		case "client_id_issued_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientIDIssuedAt = int64(in.Int64())
+			}
+		case "client_secret_expires_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientSecretExpiresAt = int64(in.Int64())
+			}
+		case "registration_access_token":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RegistrationAccessToken = string(in.String())
+			}
+		case "registration_client_uri":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RegistrationClientURI = string(in.String())
+			}
+		case "owner":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Owner = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "client_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "redirect_uris":
+			if in.IsNull() {
+				in.Skip()
+				out.RedirectURIs = nil
+			} else {
+				in.Delim('[')
+				if out.RedirectURIs == nil {
+					if !in.IsDelim(']') {
+						out.RedirectURIs = make([]string, 0, 4)
+					} else {
+						out.RedirectURIs = []string{}
+					}
+				} else {
+					out.RedirectURIs = (out.RedirectURIs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v79 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v79 = string(in.String())
+					}
+					out.RedirectURIs = append(out.RedirectURIs, v79)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "client_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientName = string(in.String())
+			}
+		case "grant_types":
+			if in.IsNull() {
+				in.Skip()
+				out.GrantTypes = nil
+			} else {
+				in.Delim('[')
+				if out.GrantTypes == nil {
+					if !in.IsDelim(']') {
+						out.GrantTypes = make([]string, 0, 4)
+					} else {
+						out.GrantTypes = []string{}
+					}
+				} else {
+					out.GrantTypes = (out.GrantTypes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v80 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v80 = string(in.String())
+					}
+					out.GrantTypes = append(out.GrantTypes, v80)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		case "token_endpoint_auth_method":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TokenEndpointAuthMethod = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel32(out *jwriter.Writer, in ClientRegistration) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ClientSecret != "" {
+		const prefix string = ",\"client_secret\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ClientSecret))
+	}
+	{
+		const prefix string = ",\"client_id_issued_at\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.ClientIDIssuedAt))
+	}
+	{
+		const prefix string = ",\"client_secret_expires_at\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.ClientSecretExpiresAt))
+	}
+	if in.RegistrationAccessToken != "" {
+		const prefix string = ",\"registration_access_token\":"
+		out.RawString(prefix)
+		out.String(string(in.RegistrationAccessToken))
+	}
+	if in.RegistrationClientURI != "" {
+		const prefix string = ",\"registration_client_uri\":"
+		out.RawString(prefix)
+		out.String(string(in.RegistrationClientURI))
+	}
+	{
+		const prefix string = ",\"owner\":"
+		out.RawString(prefix)
+		out.String(string(in.Owner))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	if in.ClientID != "" {
+		const prefix string = ",\"client_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientID))
+	}
+	{
+		const prefix string = ",\"redirect_uris\":"
+		out.RawString(prefix)
+		if in.RedirectURIs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v81, v82 := range in.RedirectURIs {
+				if v81 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v82))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ClientName != "" {
+		const prefix string = ",\"client_name\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientName))
+	}
+	{
+		const prefix string = ",\"grant_types\":"
+		out.RawString(prefix)
+		if in.GrantTypes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v83, v84 := range in.GrantTypes {
+				if v83 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v84))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Scope != "" {
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	{
+		const prefix string = ",\"token_endpoint_auth_method\":"
+		out.RawString(prefix)
+		out.String(string(in.TokenEndpointAuthMethod))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ClientRegistration) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel32(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ClientRegistration) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel32(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ClientRegistration) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel32(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ClientRegistration) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel32(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel33(in *jlexer.Lexer, out *ClientMetadata) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "client_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "redirect_uris":
+			if in.IsNull() {
+				in.Skip()
+				out.RedirectURIs = nil
+			} else {
+				in.Delim('[')
+				if out.RedirectURIs == nil {
+					if !in.IsDelim(']') {
+						out.RedirectURIs = make([]string, 0, 4)
+					} else {
+						out.RedirectURIs = []string{}
+					}
+				} else {
+					out.RedirectURIs = (out.RedirectURIs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v85 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v85 = string(in.String())
+					}
+					out.RedirectURIs = append(out.RedirectURIs, v85)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "client_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientName = string(in.String())
+			}
+		case "grant_types":
+			if in.IsNull() {
+				in.Skip()
+				out.GrantTypes = nil
+			} else {
+				in.Delim('[')
+				if out.GrantTypes == nil {
+					if !in.IsDelim(']') {
+						out.GrantTypes = make([]string, 0, 4)
+					} else {
+						out.GrantTypes = []string{}
+					}
+				} else {
+					out.GrantTypes = (out.GrantTypes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v86 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v86 = string(in.String())
+					}
+					out.GrantTypes = append(out.GrantTypes, v86)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		case "token_endpoint_auth_method":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TokenEndpointAuthMethod = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel33(out *jwriter.Writer, in ClientMetadata) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.ClientID != "" {
+		const prefix string = ",\"client_id\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.ClientID))
+	}
+	{
+		const prefix string = ",\"redirect_uris\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.RedirectURIs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v87, v88 := range in.RedirectURIs {
+				if v87 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v88))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.ClientName != "" {
+		const prefix string = ",\"client_name\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientName))
+	}
+	{
+		const prefix string = ",\"grant_types\":"
+		out.RawString(prefix)
+		if in.GrantTypes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v89, v90 := range in.GrantTypes {
+				if v89 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v90))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Scope != "" {
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	{
+		const prefix string = ",\"token_endpoint_auth_method\":"
+		out.RawString(prefix)
+		out.String(string(in.TokenEndpointAuthMethod))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ClientMetadata) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel33(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ClientMetadata) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel33(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ClientMetadata) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel33(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ClientMetadata) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel33(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel34(in *jlexer.Lexer, out *Client) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "ID":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "Name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Name = string(in.String())
+			}
+		case "SecretHash":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SecretHash = string(in.String())
+			}
+		case "Scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v91 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v91 = string(in.String())
+					}
+					out.Scopes = append(out.Scopes, v91)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "Audiences":
+			if in.IsNull() {
+				in.Skip()
+				out.Audiences = nil
+			} else {
+				in.Delim('[')
+				if out.Audiences == nil {
+					if !in.IsDelim(']') {
+						out.Audiences = make([]string, 0, 4)
+					} else {
+						out.Audiences = []string{}
+					}
+				} else {
+					out.Audiences = (out.Audiences)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v92 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v92 = string(in.String())
+					}
+					out.Audiences = append(out.Audiences, v92)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "GrantTypes":
+			if in.IsNull() {
+				in.Skip()
+				out.GrantTypes = nil
+			} else {
+				in.Delim('[')
+				if out.GrantTypes == nil {
+					if !in.IsDelim(']') {
+						out.GrantTypes = make([]string, 0, 4)
+					} else {
+						out.GrantTypes = []string{}
+					}
+				} else {
+					out.GrantTypes = (out.GrantTypes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v93 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v93 = string(in.String())
+					}
+					out.GrantTypes = append(out.GrantTypes, v93)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "RedirectURIs":
+			if in.IsNull() {
+				in.Skip()
+				out.RedirectURIs = nil
+			} else {
+				in.Delim('[')
+				if out.RedirectURIs == nil {
+					if !in.IsDelim(']') {
+						out.RedirectURIs = make([]string, 0, 4)
+					} else {
+						out.RedirectURIs = []string{}
+					}
+				} else {
+					out.RedirectURIs = (out.RedirectURIs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v94 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v94 = string(in.String())
+					}
+					out.RedirectURIs = append(out.RedirectURIs, v94)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "ThirdParty":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ThirdParty = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel34(out *jwriter.Writer, in Client) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"ID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"Name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"SecretHash\":"
+		out.RawString(prefix)
+		out.String(string(in.SecretHash))
+	}
+	{
+		const prefix string = ",\"Scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v95, v96 := range in.Scopes {
+				if v95 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v96))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"Audiences\":"
+		out.RawString(prefix)
+		if in.Audiences == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v97, v98 := range in.Audiences {
+				if v97 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v98))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"GrantTypes\":"
+		out.RawString(prefix)
+		if in.GrantTypes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v99, v100 := range in.GrantTypes {
+				if v99 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v100))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"RedirectURIs\":"
+		out.RawString(prefix)
+		if in.RedirectURIs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v101, v102 := range in.RedirectURIs {
+				if v101 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v102))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"ThirdParty\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.ThirdParty))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Client) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel34(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Client) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel34(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Client) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel34(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Client) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel34(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel35(in *jlexer.Lexer, out *CertificateStatusResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "serial":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Serial = string(in.String())
+			}
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "revoked_at":
+			if in.IsNull() {
+				in.Skip()
+				out.RevokedAt = nil
+			} else {
+				if out.RevokedAt == nil {
+					out.RevokedAt = new(time.Time)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					if data := in.Raw(); in.Ok() {
+						in.AddError((*out.RevokedAt).UnmarshalJSON(data))
+					}
+				}
+			}
+		case "revocation_reason":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RevocationReason = string(in.String())
+			}
+		case "this_update":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.ThisUpdate).UnmarshalJSON(data))
+				}
+			}
+		case "next_update":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.NextUpdate).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel35(out *jwriter.Writer, in CertificateStatusResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"serial\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Serial))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	if in.RevokedAt != nil {
+		const prefix string = ",\"revoked_at\":"
+		out.RawString(prefix)
+		out.Raw((*in.RevokedAt).MarshalJSON())
+	}
+	if in.RevocationReason != "" {
+		const prefix string = ",\"revocation_reason\":"
+		out.RawString(prefix)
+		out.String(string(in.RevocationReason))
+	}
+	{
+		const prefix string = ",\"this_update\":"
+		out.RawString(prefix)
+		out.Raw((in.ThisUpdate).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"next_update\":"
+		out.RawString(prefix)
+		out.Raw((in.NextUpdate).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CertificateStatusResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel35(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CertificateStatusResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel35(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CertificateStatusResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel35(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CertificateStatusResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel35(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel36(in *jlexer.Lexer, out *CertificateResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "certificate":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Certificate = string(in.String())
+			}
+		case "ca":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CA = string(in.String())
+			}
+		case "serial":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Serial = string(in.String())
+			}
+		case "spiffe_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SpiffeID = string(in.String())
+			}
+		case "not_after":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.NotAfter).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel36(out *jwriter.Writer, in CertificateResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"certificate\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Certificate))
+	}
+	{
+		const prefix string = ",\"ca\":"
+		out.RawString(prefix)
+		out.String(string(in.CA))
+	}
+	{
+		const prefix string = ",\"serial\":"
+		out.RawString(prefix)
+		out.String(string(in.Serial))
+	}
+	{
+		const prefix string = ",\"spiffe_id\":"
+		out.RawString(prefix)
+		out.String(string(in.SpiffeID))
+	}
+	{
+		const prefix string = ",\"not_after\":"
+		out.RawString(prefix)
+		out.Raw((in.NotAfter).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CertificateResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel36(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CertificateResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel36(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CertificateResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel36(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CertificateResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel36(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel37(in *jlexer.Lexer, out *CertificateRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "csr":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CSR = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel37(out *jwriter.Writer, in CertificateRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"csr\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.CSR))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v CertificateRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel37(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v CertificateRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel37(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *CertificateRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel37(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *CertificateRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel37(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel38(in *jlexer.Lexer, out *AuthorizationRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "ResponseType":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ResponseType = string(in.String())
+			}
+		case "ClientID":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "RedirectURI":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RedirectURI = string(in.String())
+			}
+		case "Scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		case "Audience":
+			if in.IsNull() {
+				in.Skip()
+				out.Audience = nil
+			} else {
+				in.Delim('[')
+				if out.Audience == nil {
+					if !in.IsDelim(']') {
+						out.Audience = make([]string, 0, 4)
+					} else {
+						out.Audience = []string{}
+					}
+				} else {
+					out.Audience = (out.Audience)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v103 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v103 = string(in.String())
+					}
+					out.Audience = append(out.Audience, v103)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "State":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.State = string(in.String())
+			}
+		case "CodeChallenge":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CodeChallenge = string(in.String())
+			}
+		case "CodeChallengeMethod":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CodeChallengeMethod = string(in.String())
+			}
+		case "DPoPJKT":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DPoPJKT = string(in.String())
+			}
+		case "Nonce":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Nonce = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel38(out *jwriter.Writer, in AuthorizationRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"ResponseType\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ResponseType))
+	}
+	{
+		const prefix string = ",\"ClientID\":"
+		out.RawString(prefix)
+		out.String(string(in.ClientID))
+	}
+	{
+		const prefix string = ",\"RedirectURI\":"
+		out.RawString(prefix)
+		out.String(string(in.RedirectURI))
+	}
+	{
+		const prefix string = ",\"Scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	{
+		const prefix string = ",\"Audience\":"
+		out.RawString(prefix)
+		if in.Audience == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v104, v105 := range in.Audience {
+				if v104 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v105))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"State\":"
+		out.RawString(prefix)
+		out.String(string(in.State))
+	}
+	{
+		const prefix string = ",\"CodeChallenge\":"
+		out.RawString(prefix)
+		out.String(string(in.CodeChallenge))
+	}
+	{
+		const prefix string = ",\"CodeChallengeMethod\":"
+		out.RawString(prefix)
+		out.String(string(in.CodeChallengeMethod))
+	}
+	{
+		const prefix string = ",\"DPoPJKT\":"
+		out.RawString(prefix)
+		out.String(string(in.DPoPJKT))
+	}
+	{
+		const prefix string = ",\"Nonce\":"
+		out.RawString(prefix)
+		out.String(string(in.Nonce))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AuthorizationRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel38(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AuthorizationRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel38(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AuthorizationRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel38(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AuthorizationRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel38(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel39(in *jlexer.Lexer, out *AuthorizationCodeRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "Code":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Code = string(in.String())
+			}
+		case "RedirectURI":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RedirectURI = string(in.String())
+			}
+		case "CodeVerifier":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CodeVerifier = string(in.String())
+			}
+		case "DPoPJKT":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DPoPJKT = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel39(out *jwriter.Writer, in AuthorizationCodeRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"RedirectURI\":"
+		out.RawString(prefix)
+		out.String(string(in.RedirectURI))
+	}
+	{
+		const prefix string = ",\"CodeVerifier\":"
+		out.RawString(prefix)
+		out.String(string(in.CodeVerifier))
+	}
+	{
+		const prefix string = ",\"DPoPJKT\":"
+		out.RawString(prefix)
+		out.String(string(in.DPoPJKT))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AuthorizationCodeRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel39(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AuthorizationCodeRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel39(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AuthorizationCodeRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel39(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AuthorizationCodeRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel39(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel40(in *jlexer.Lexer, out *AuthorizationCode) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "client_id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ClientID = string(in.String())
+			}
+		case "redirect_uri":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RedirectURI = string(in.String())
+			}
+		case "email":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Email = string(in.String())
+			}
+		case "scope":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Scope = string(in.String())
+			}
+		case "audience":
+			if in.IsNull() {
+				in.Skip()
+				out.Audience = nil
+			} else {
+				in.Delim('[')
+				if out.Audience == nil {
+					if !in.IsDelim(']') {
+						out.Audience = make([]string, 0, 4)
+					} else {
+						out.Audience = []string{}
+					}
+				} else {
+					out.Audience = (out.Audience)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v106 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v106 = string(in.String())
+					}
+					out.Audience = append(out.Audience, v106)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "code_challenge":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CodeChallenge = string(in.String())
+			}
+		case "dpop_jkt":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DPoPJKT = string(in.String())
+			}
+		case "nonce":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Nonce = string(in.String())
+			}
+		case "amr":
+			if in.IsNull() {
+				in.Skip()
+				out.AMR = nil
+			} else {
+				in.Delim('[')
+				if out.AMR == nil {
+					if !in.IsDelim(']') {
+						out.AMR = make([]string, 0, 4)
+					} else {
+						out.AMR = []string{}
+					}
+				} else {
+					out.AMR = (out.AMR)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v107 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v107 = string(in.String())
+					}
+					out.AMR = append(out.AMR, v107)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "user_agent":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserAgent = string(in.String())
+			}
+		case "ip":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IP = string(in.String())
+			}
+		case "mfa":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.MFA = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel40(out *jwriter.Writer, in AuthorizationCode) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"client_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ClientID))
+	}
+	{
+		const prefix string = ",\"redirect_uri\":"
+		out.RawString(prefix)
+		out.String(string(in.RedirectURI))
+	}
+	{
+		const prefix string = ",\"email\":"
+		out.RawString(prefix)
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"scope\":"
+		out.RawString(prefix)
+		out.String(string(in.Scope))
+	}
+	{
+		const prefix string = ",\"audience\":"
+		out.RawString(prefix)
+		if in.Audience == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v108, v109 := range in.Audience {
+				if v108 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v109))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"code_challenge\":"
+		out.RawString(prefix)
+		out.String(string(in.CodeChallenge))
+	}
+	if in.DPoPJKT != "" {
+		const prefix string = ",\"dpop_jkt\":"
+		out.RawString(prefix)
+		out.String(string(in.DPoPJKT))
+	}
+	if in.Nonce != "" {
+		const prefix string = ",\"nonce\":"
+		out.RawString(prefix)
+		out.String(string(in.Nonce))
+	}
+	{
+		const prefix string = ",\"amr\":"
+		out.RawString(prefix)
+		if in.AMR == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v110, v111 := range in.AMR {
+				if v110 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v111))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"user_agent\":"
+		out.RawString(prefix)
+		out.String(string(in.UserAgent))
+	}
+	{
+		const prefix string = ",\"ip\":"
+		out.RawString(prefix)
+		out.String(string(in.IP))
+	}
+	{
+		const prefix string = ",\"mfa\":"
+		out.RawString(prefix)
+		out.String(string(in.MFA))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v AuthorizationCode) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel40(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AuthorizationCode) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel40(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *AuthorizationCode) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel40(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AuthorizationCode) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel40(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel41(in *jlexer.Lexer, out *Actor) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "sub":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Sub = string(in.String())
+			}
+		case "act":
+			if in.IsNull() {
+				in.Skip()
+				out.Act = nil
+			} else {
+				if out.Act == nil {
+					out.Act = new(Actor)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*out.Act).UnmarshalEasyJSON(in)
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel41(out *jwriter.Writer, in Actor) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"sub\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Sub))
+	}
+	if in.Act != nil {
+		const prefix string = ",\"act\":"
+		out.RawString(prefix)
+		(*in.Act).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Actor) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel41(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Actor) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel41(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Actor) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel41(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Actor) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel41(l, v)
+}
+func easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel42(in *jlexer.Lexer, out *ActiveSession) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "user_agent":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UserAgent = string(in.String())
+			}
+		case "ip":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IP = string(in.String())
+			}
+		case "mfa":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.MFA = string(in.String())
+			}
+		case "client":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Client = string(in.String())
+			}
+		case "created_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.CreatedAt).UnmarshalJSON(data))
+				}
+			}
+		case "last_used_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				if data := in.Raw(); in.Ok() {
+					in.AddError((out.LastUsedAt).UnmarshalJSON(data))
+				}
+			}
+		case "current":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Current = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel42(out *jwriter.Writer, in ActiveSession) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"user_agent\":"
+		out.RawString(prefix)
+		out.String(string(in.UserAgent))
+	}
+	{
+		const prefix string = ",\"ip\":"
+		out.RawString(prefix)
+		out.String(string(in.IP))
+	}
+	{
+		const prefix string = ",\"mfa\":"
+		out.RawString(prefix)
+		out.String(string(in.MFA))
+	}
+	{
+		const prefix string = ",\"client\":"
+		out.RawString(prefix)
+		out.String(string(in.Client))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"last_used_at\":"
+		out.RawString(prefix)
+		out.Raw((in.LastUsedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"current\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Current))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ActiveSession) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel42(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ActiveSession) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC80ae7adEncodeGithubComMxmrykovPoloniumAuthInternalModel42(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ActiveSession) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel42(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ActiveSession) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC80ae7adDecodeGithubComMxmrykovPoloniumAuthInternalModel42(l, v)
+}
@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+type (
+	AuthorizationRequest struct {
+		ResponseType        string
+		ClientID            string
+		RedirectURI         string
+		Scope               string
+		Audience            []string
+		State               string
+		CodeChallenge       string
+		CodeChallengeMethod string
+		DPoPJKT             string
+		Nonce               string
+	}
+
+	// AuthorizationCode is what a code stands for until it is redeemed.
+	AuthorizationCode struct {
+		ClientID      string    `json:"client_id"`
+		RedirectURI   string    `json:"redirect_uri"`
+		Email         string    `json:"email"`
+		Scope         string    `json:"scope"`
+		Audience      []string  `json:"audience"`
+		CodeChallenge string    `json:"code_challenge"`
+		DPoPJKT       string    `json:"dpop_jkt,omitempty"`
+		Nonce         string    `json:"nonce,omitempty"`
+		AMR           []string  `json:"amr"`
+		UserAgent     string    `json:"user_agent"`
+		IP            string    `json:"ip"`
+		MFA           string    `json:"mfa"`
+		CreatedAt     time.Time `json:"created_at"`
+	}
+
+	// UserInfo holds the OpenID Connect claims of a user. Only sub is
+	// always present, the rest depends on the granted scopes.
+	UserInfo struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email,omitempty"`
+		EmailVerified *bool  `json:"email_verified,omitempty"`
+		Deployer      string `json:"deployer,omitempty"`
+	}
+
+	// DeviceAuthorization is the state of a device code until the device
+	// redeems it. Email and Ticket are set once a user logs in to review it,
+	// the ticket proving that login when the user decides. Interval grows
+	// each time the device polls too often.
+	DeviceAuthorization struct {
+		ClientID  string        `json:"client_id"`
+		UserCode  string        `json:"user_code"`
+		Scope     string        `json:"scope"`
+		Audience  []string      `json:"audience"`
+		Status    string        `json:"status"`
+		Interval  time.Duration `json:"interval"`
+		Email     string        `json:"email,omitempty"`
+		Ticket    string        `json:"ticket,omitempty"`
+		AMR       []string      `json:"amr,omitempty"`
+		MFA       string        `json:"mfa,omitempty"`
+		UserAgent string        `json:"user_agent"`
+		IP        string        `json:"ip"`
+		AuthTime  time.Time     `json:"auth_time"`
+		ExpiresAt time.Time     `json:"expires_at"`
+	}
+
+	// ConsentRequest is an authorization request waiting for the consent
+	// of the user who has just logged in.
+	ConsentRequest struct {
+		ID         string                `json:"id"`
+		Request    *AuthorizationRequest `json:"request"`
+		ClientName string                `json:"client_name"`
+		Scopes     []string              `json:"scopes"`
+		UserID     string                `json:"user_id"`
+		Email      string                `json:"email"`
+		UserAgent  string                `json:"user_agent"`
+		IP         string                `json:"ip"`
+	}
+
+	// Consent holds the scopes a user has granted to a third-party client.
+	Consent struct {
+		ClientID   string    `json:"client_id"`
+		ClientName string    `json:"client_name"`
+		Scopes     []string  `json:"scopes"`
+		CreateDt   time.Time `json:"granted_at"`
+		UpdateDt   time.Time `json:"updated_at"`
+	}
+
+	AuthorizationCodeRequest struct {
+		Code         string
+		RedirectURI  string
+		CodeVerifier string
+		DPoPJKT      string
+	}
+)
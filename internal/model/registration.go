@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type (
+	// RegisteredClient is a client registered through the registration
+	// endpoint. It can only be used once an admin has approved it.
+	RegisteredClient struct {
+		Client
+		Name                    string
+		Owner                   string
+		Status                  string
+		TokenEndpointAuthMethod string
+		RegistrationTokenHash   string
+		CreateDt                time.Time
+	}
+
+	// ClientMetadata is the client metadata of RFC 7591, section 2.
+	ClientMetadata struct {
+		ClientID                string   `json:"client_id,omitempty"`
+		RedirectURIs            []string `json:"redirect_uris"`
+		ClientName              string   `json:"client_name,omitempty"`
+		GrantTypes              []string `json:"grant_types"`
+		Scope                   string   `json:"scope,omitempty"`
+		TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method"`
+	}
+
+	ClientRegistration struct {
+		ClientMetadata
+		ClientSecret            string `json:"client_secret,omitempty"`
+		ClientIDIssuedAt        int64  `json:"client_id_issued_at"`
+		ClientSecretExpiresAt   int64  `json:"client_secret_expires_at"`
+		RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+		RegistrationClientURI   string `json:"registration_client_uri,omitempty"`
+		Owner                   string `json:"owner"`
+		Status                  string `json:"status"`
+	}
+)
@@ -6,13 +6,37 @@ type (
 	}
 
 	SignupConfirmCodeRequest struct {
-		Email string `json:"email"`
-		Pwd   string `json:"pwd"`
-		Code  string `json:"code"`
+		Email    string   `json:"email"`
+		Pwd      string   `json:"pwd"`
+		Code     string   `json:"code"`
+		ClientID string   `json:"client_id"`
+		Scope    string   `json:"scope"`
+		Audience []string `json:"audience"`
 	}
 
 	GetQRCodeRequest struct {
 		Email string `json:"email"`
 		Pwd   string `json:"pwd"`
 	}
+
+	RefreshTokenRequest struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	TokenExchangeRequest struct {
+		SubjectToken       string
+		SubjectTokenType   string
+		RequestedTokenType string
+		Audience           []string
+		Scope              string
+	}
+
+	SSHCertificateRequest struct {
+		PublicKey     string   `json:"public_key"`
+		SourceAddress []string `json:"source_address"`
+	}
+
+	CertificateRequest struct {
+		CSR string `json:"csr"`
+	}
 )
@@ -1,9 +1,77 @@
 package model
 
+import "time"
+
 type (
 	Response struct {
 		Data    interface{} `json:"data"`
 		Message string      `json:"message"`
 		Error   string      `json:"error"`
 	}
+
+	IntrospectionResponse struct {
+		Active    bool          `json:"active"`
+		Revoked   bool          `json:"revoked,omitempty"`
+		Sub       string        `json:"sub,omitempty"`
+		Session   string        `json:"session,omitempty"`
+		Deployer  string        `json:"deployer,omitempty"`
+		Scope     string        `json:"scope,omitempty"`
+		ClientID  string        `json:"client_id,omitempty"`
+		Aud       []string      `json:"aud,omitempty"`
+		Cnf       *Confirmation `json:"cnf,omitempty"`
+		Act       *Actor        `json:"act,omitempty"`
+		TokenType string        `json:"token_type,omitempty"`
+		Iss       string        `json:"iss,omitempty"`
+		Jti       string        `json:"jti,omitempty"`
+		Exp       int64         `json:"exp,omitempty"`
+		Iat       int64         `json:"iat,omitempty"`
+	}
+
+	TokenResponse struct {
+		AccessToken     string `json:"access_token"`
+		IssuedTokenType string `json:"issued_token_type,omitempty"`
+		TokenType       string `json:"token_type"`
+		ExpiresIn       int64  `json:"expires_in"`
+		RefreshToken    string `json:"refresh_token,omitempty"`
+		IDToken         string `json:"id_token,omitempty"`
+		Scope           string `json:"scope,omitempty"`
+	}
+
+	DeviceAuthorizationResponse struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int64  `json:"expires_in"`
+		Interval                int64  `json:"interval"`
+	}
+
+	OAuthError struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description,omitempty"`
+	}
+
+	SSHCertificateResponse struct {
+		Certificate string   `json:"certificate"`
+		Serial      uint64   `json:"serial"`
+		Principals  []string `json:"principals"`
+		ValidBefore int64    `json:"valid_before"`
+	}
+
+	CertificateResponse struct {
+		Certificate string    `json:"certificate"`
+		CA          string    `json:"ca"`
+		Serial      string    `json:"serial"`
+		SpiffeID    string    `json:"spiffe_id"`
+		NotAfter    time.Time `json:"not_after"`
+	}
+
+	CertificateStatusResponse struct {
+		Serial           string     `json:"serial"`
+		Status           string     `json:"status"`
+		RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+		RevocationReason string     `json:"revocation_reason,omitempty"`
+		ThisUpdate       time.Time  `json:"this_update"`
+		NextUpdate       time.Time  `json:"next_update"`
+	}
 )
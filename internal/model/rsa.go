@@ -2,6 +2,7 @@ package model
 
 import "crypto/rsa"
 
+//easyjson:skip
 type RSAKeyPair struct {
 	PrivateKey *rsa.PrivateKey
 	PublicKey  *rsa.PublicKey
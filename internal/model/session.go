@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+type (
+	Session struct {
+		ID         string    `json:"id"`
+		User       string    `json:"user"`
+		UserAgent  string    `json:"user_agent"`
+		IP         string    `json:"ip"`
+		MFA        string    `json:"mfa"`
+		Client     string    `json:"client"`
+		Audience   []string  `json:"audience"`
+		Scope      string    `json:"scope"`
+		DPoPJKT    string    `json:"dpop_jkt,omitempty"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastUsedAt time.Time `json:"last_used_at"`
+	}
+
+	// Confirmation binds a token to the key thumbprint of a DPoP proof.
+	Confirmation struct {
+		JKT string `json:"jkt"`
+	}
+
+	// Actor names the party acting for the subject of an exchanged token.
+	// Act holds the previous actor when a token is exchanged again.
+	Actor struct {
+		Sub string `json:"sub"`
+		Act *Actor `json:"act,omitempty"`
+	}
+
+	ActiveSession struct {
+		ID         string    `json:"id"`
+		UserAgent  string    `json:"user_agent"`
+		IP         string    `json:"ip"`
+		MFA        string    `json:"mfa"`
+		Client     string    `json:"client"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastUsedAt time.Time `json:"last_used_at"`
+		Current    bool      `json:"current"`
+	}
+
+	RefreshFamily struct {
+		User    string `json:"user"`
+		Current string `json:"current"`
+		Revoked bool   `json:"revoked"`
+	}
+)
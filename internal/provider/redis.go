@@ -9,12 +9,39 @@ import (
 	"github.com/mxmrykov/polonium-auth/internal/config"
 )
 
+var ErrKeyNotFound = errors.New("key not found")
+
+var compareAndSwapScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// incrScript starts the expiry of a counter with its first increment, so
+// that the counter counts within a fixed window.
+var incrScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
 type (
 	IRedis interface {
 		Get(key string) (string, error)
+		GetDel(key string) (string, error)
 		IsExists(key string) (bool, error)
 		Set(key, value string, ttl time.Duration) error
+		SetNX(key, value string, ttl time.Duration) (bool, error)
 		Drop(key string) error
+		CompareAndSwap(key, old, value string, ttl time.Duration) (bool, error)
+		Incr(key string, window time.Duration) (int64, error)
+		AddMember(key, member string, ttl time.Duration) error
+		DropMember(key, member string) error
+		Members(key string) ([]string, error)
 	}
 
 	rdb struct {
@@ -39,6 +66,26 @@ func (r *rdb) Get(key string) (string, error) {
 	defer cancel()
 	val, err := r.db.Get(ctx, key).Result()
 	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrKeyNotFound
+		}
+
+		return "", err
+	}
+
+	return val, nil
+}
+
+// GetDel reads and removes the key atomically.
+func (r *rdb) GetDel(key string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	val, err := r.db.GetDel(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrKeyNotFound
+		}
+
 		return "", err
 	}
 
@@ -67,8 +114,54 @@ func (r *rdb) Set(key, value string, ttl time.Duration) error {
 	return r.db.Set(ctx, key, value, ttl).Err()
 }
 
+func (r *rdb) SetNX(key, value string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return r.db.SetNX(ctx, key, value, ttl).Result()
+}
+
 func (r *rdb) Drop(key string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	return r.db.Del(ctx, key).Err()
 }
+
+func (r *rdb) CompareAndSwap(key, old, value string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	swapped, err := compareAndSwapScript.Run(ctx, r.db, []string{key}, old, value, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return swapped == 1, nil
+}
+
+func (r *rdb) Incr(key string, window time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return incrScript.Run(ctx, r.db, []string{key}, window.Milliseconds()).Int64()
+}
+
+func (r *rdb) AddMember(key, member string, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := r.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, member)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+func (r *rdb) DropMember(key, member string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return r.db.SRem(ctx, key, member).Err()
+}
+
+func (r *rdb) Members(key string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return r.db.SMembers(ctx, key).Result()
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,8 @@ type (
 	IVault interface {
 		Write(ctx context.Context, path string, data map[string]interface{}) error
 		Read(ctx context.Context, path string) (map[string]interface{}, error)
+		ReadVersioned(ctx context.Context, path string) (map[string]interface{}, int, error)
+		WriteCAS(ctx context.Context, path string, data map[string]interface{}, version int) error
 	}
 
 	vaultClient struct {
@@ -63,3 +66,31 @@ func (v *vaultClient) Read(ctx context.Context, path string) (map[string]interfa
 
 	return secret.Data, nil
 }
+
+func (v *vaultClient) ReadVersioned(ctx context.Context, path string) (map[string]interface{}, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, v.connectionTtl)
+	defer cancel()
+
+	secret, err := v.kv2.Get(ctx, path)
+	if err != nil {
+		if errors.Is(err, api.ErrSecretNotFound) {
+			return nil, 0, nil
+		}
+
+		return nil, 0, err
+	}
+
+	if secret == nil || secret.VersionMetadata == nil {
+		return nil, 0, nil
+	}
+
+	return secret.Data, secret.VersionMetadata.Version, nil
+}
+
+func (v *vaultClient) WriteCAS(ctx context.Context, path string, data map[string]interface{}, version int) error {
+	ctx, cancel := context.WithTimeout(ctx, v.connectionTtl)
+	defer cancel()
+
+	_, err := v.kv2.Put(ctx, path, data, api.WithCheckAndSet(version))
+	return err
+}
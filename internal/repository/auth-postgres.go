@@ -3,11 +3,14 @@ package repository
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/mxmrykov/polonium-auth/internal/config"
 	"github.com/mxmrykov/polonium-auth/internal/model"
 	"github.com/mxmrykov/polonium-auth/internal/provider"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
 )
 
 type (
@@ -15,6 +18,26 @@ type (
 		IsUserExists(ctx context.Context, email string) (bool, error)
 		Signup(ctx context.Context, user *model.User) error
 		VerificateUser(ctx context.Context, user string) error
+		GetUser(ctx context.Context, email string) (*model.User, error)
+		GetDeployment(ctx context.Context, id string) (*model.Deployment, error)
+		SaveCertificate(ctx context.Context, cert *model.DeploymentCertificate) error
+		GetCertificate(ctx context.Context, serial string, revokedReason int) (*model.DeploymentCertificate, error)
+		ListRevokedCertificates(ctx context.Context, revokedReason int) ([]model.DeploymentCertificate, error)
+		RevokeCertificates(ctx context.Context, deployment string, reason int) (int64, error)
+		GetClient(ctx context.Context, id string) (*model.Client, error)
+		RegisterClient(ctx context.Context, client *model.RegisteredClient) error
+		GetRegisteredClient(ctx context.Context, id string) (*model.RegisteredClient, error)
+		ListRegisteredClients(ctx context.Context, status string) ([]model.RegisteredClient, error)
+		UpdateRegisteredClient(ctx context.Context, client *model.RegisteredClient) error
+		ReviewClient(ctx context.Context, id, status string) error
+		DeleteRegisteredClient(ctx context.Context, id string) error
+		GetConsent(ctx context.Context, user, client string) ([]string, bool, error)
+		SaveConsent(ctx context.Context, user, client string, scopes []string) error
+		ListConsents(ctx context.Context, user string) ([]model.Consent, error)
+		DeleteConsent(ctx context.Context, user, client string) error
+		GetFederatedIdentity(ctx context.Context, provider, subject string) (*model.FederatedIdentity, error)
+		LinkFederatedIdentity(ctx context.Context, identity *model.FederatedIdentity) error
+		TouchFederatedIdentity(ctx context.Context, provider, subject, email string) error
 	}
 
 	authPostgres struct {
@@ -32,6 +55,66 @@ var (
 
 	//go:embed sql/verificate.sql
 	verificateQuery string
+
+	//go:embed sql/getUser.sql
+	getUserQuery string
+
+	//go:embed sql/getDeployment.sql
+	getDeploymentQuery string
+
+	//go:embed sql/saveCertificate.sql
+	saveCertificateQuery string
+
+	//go:embed sql/getCertificate.sql
+	getCertificateQuery string
+
+	//go:embed sql/listRevokedCertificates.sql
+	listRevokedCertificatesQuery string
+
+	//go:embed sql/revokeCertificates.sql
+	revokeCertificatesQuery string
+
+	//go:embed sql/getClient.sql
+	getClientQuery string
+
+	//go:embed sql/registerClient.sql
+	registerClientQuery string
+
+	//go:embed sql/getRegisteredClient.sql
+	getRegisteredClientQuery string
+
+	//go:embed sql/listRegisteredClients.sql
+	listRegisteredClientsQuery string
+
+	//go:embed sql/updateRegisteredClient.sql
+	updateRegisteredClientQuery string
+
+	//go:embed sql/reviewClient.sql
+	reviewClientQuery string
+
+	//go:embed sql/deleteRegisteredClient.sql
+	deleteRegisteredClientQuery string
+
+	//go:embed sql/getConsent.sql
+	getConsentQuery string
+
+	//go:embed sql/saveConsent.sql
+	saveConsentQuery string
+
+	//go:embed sql/listConsents.sql
+	listConsentsQuery string
+
+	//go:embed sql/deleteConsent.sql
+	deleteConsentQuery string
+
+	//go:embed sql/getFederatedIdentity.sql
+	getFederatedIdentityQuery string
+
+	//go:embed sql/linkFederatedIdentity.sql
+	linkFederatedIdentityQuery string
+
+	//go:embed sql/touchFederatedIdentity.sql
+	touchFederatedIdentityQuery string
 )
 
 func NewAuthPostgres(cfg *config.Psql) (IAuthPostgres, error) {
@@ -86,3 +169,397 @@ func (a *authPostgres) VerificateUser(ctx context.Context, user string) error {
 
 	return nil
 }
+
+func (a *authPostgres) GetUser(ctx context.Context, email string) (*model.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	user := new(model.User)
+	if err := a.pg.GetConnect().QueryRow(ctx, getUserQuery, email).Scan(
+		&user.Email, &user.Id, &user.Verified, &user.Banned, &user.SshSign, &user.Deployer, &user.CreateDt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, vars.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (a *authPostgres) GetDeployment(ctx context.Context, id string) (*model.Deployment, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	deployment := new(model.Deployment)
+	if err := a.pg.GetConnect().QueryRow(ctx, getDeploymentQuery, id).Scan(
+		&deployment.ID, &deployment.Deployer, &deployment.Name, &deployment.State,
+		&deployment.IP, &deployment.LastConnection, &deployment.CreateDt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, vars.ErrDeploymentNotFound
+		}
+
+		return nil, err
+	}
+
+	return deployment, nil
+}
+
+func (a *authPostgres) SaveCertificate(ctx context.Context, cert *model.DeploymentCertificate) error {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	if _, err := a.pg.GetConnect().Exec(
+		ctx, saveCertificateQuery,
+		cert.Serial, cert.Deployment, cert.Deployer, cert.SpiffeID, cert.NotBefore, cert.NotAfter,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetCertificate reports certificates of revoked or deleted deployments as
+// revoked with revokedReason.
+func (a *authPostgres) GetCertificate(
+	ctx context.Context,
+	serial string,
+	revokedReason int,
+) (*model.DeploymentCertificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	cert, err := scanCertificate(a.pg.GetConnect().QueryRow(
+		ctx, getCertificateQuery,
+		serial, vars.DeploymentStateRevoked, revokedReason,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, vars.ErrCertificateNotFound
+		}
+
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// ListRevokedCertificates returns the unexpired revoked certificates, the
+// ones of revoked or deleted deployments included.
+func (a *authPostgres) ListRevokedCertificates(
+	ctx context.Context,
+	revokedReason int,
+) ([]model.DeploymentCertificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	rows, err := a.pg.GetConnect().Query(
+		ctx, listRevokedCertificatesQuery,
+		vars.DeploymentStateRevoked, revokedReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var certs []model.DeploymentCertificate
+	for rows.Next() {
+		cert, err := scanCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, *cert)
+	}
+
+	return certs, rows.Err()
+}
+
+func (a *authPostgres) RevokeCertificates(ctx context.Context, deployment string, reason int) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	tag, err := a.pg.GetConnect().Exec(ctx, revokeCertificatesQuery, deployment, reason)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (a *authPostgres) GetClient(ctx context.Context, id string) (*model.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	client := new(model.Client)
+	if err := a.pg.GetConnect().QueryRow(ctx, getClientQuery, id, vars.ClientStatusApproved).Scan(
+		&client.ID, &client.SecretHash, &client.Scopes, &client.Audiences,
+		&client.GrantTypes, &client.RedirectURIs, &client.Name, &client.ThirdParty,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, vars.ErrUnknownClient
+		}
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (a *authPostgres) RegisterClient(ctx context.Context, client *model.RegisteredClient) error {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	if _, err := a.pg.GetConnect().Exec(
+		ctx, registerClientQuery,
+		client.ID, client.SecretHash, client.Scopes, client.Audiences, client.GrantTypes, client.RedirectURIs,
+		client.Name, client.Owner, client.Status, client.TokenEndpointAuthMethod, client.RegistrationTokenHash,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *authPostgres) GetRegisteredClient(ctx context.Context, id string) (*model.RegisteredClient, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	client, err := scanRegisteredClient(a.pg.GetConnect().QueryRow(ctx, getRegisteredClientQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, vars.ErrUnknownClient
+		}
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (a *authPostgres) ListRegisteredClients(ctx context.Context, status string) ([]model.RegisteredClient, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	rows, err := a.pg.GetConnect().Query(ctx, listRegisteredClientsQuery, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []model.RegisteredClient
+	for rows.Next() {
+		client, err := scanRegisteredClient(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		clients = append(clients, *client)
+	}
+
+	return clients, rows.Err()
+}
+
+func (a *authPostgres) UpdateRegisteredClient(ctx context.Context, client *model.RegisteredClient) error {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	tag, err := a.pg.GetConnect().Exec(
+		ctx, updateRegisteredClientQuery,
+		client.ID, client.Scopes, client.GrantTypes, client.RedirectURIs, client.Name, client.Status,
+	)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return vars.ErrUnknownClient
+	}
+
+	return nil
+}
+
+func (a *authPostgres) ReviewClient(ctx context.Context, id, status string) error {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	tag, err := a.pg.GetConnect().Exec(ctx, reviewClientQuery, id, status)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return vars.ErrUnknownClient
+	}
+
+	return nil
+}
+
+func (a *authPostgres) DeleteRegisteredClient(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	tag, err := a.pg.GetConnect().Exec(ctx, deleteRegisteredClientQuery, id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return vars.ErrUnknownClient
+	}
+
+	return nil
+}
+
+// GetConsent returns the scopes the user has granted to the client and
+// reports false when the user has never given consent to it.
+func (a *authPostgres) GetConsent(ctx context.Context, user, client string) ([]string, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	var scopes []string
+	if err := a.pg.GetConnect().QueryRow(ctx, getConsentQuery, user, client).Scan(&scopes); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	return scopes, true, nil
+}
+
+// SaveConsent adds the scopes to the ones already granted.
+func (a *authPostgres) SaveConsent(ctx context.Context, user, client string, scopes []string) error {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	if _, err := a.pg.GetConnect().Exec(ctx, saveConsentQuery, user, client, scopes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *authPostgres) ListConsents(ctx context.Context, user string) ([]model.Consent, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	rows, err := a.pg.GetConnect().Query(ctx, listConsentsQuery, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var consents []model.Consent
+	for rows.Next() {
+		var consent model.Consent
+		if err := rows.Scan(
+			&consent.ClientID, &consent.ClientName, &consent.Scopes, &consent.CreateDt, &consent.UpdateDt,
+		); err != nil {
+			return nil, err
+		}
+
+		consents = append(consents, consent)
+	}
+
+	return consents, rows.Err()
+}
+
+func (a *authPostgres) DeleteConsent(ctx context.Context, user, client string) error {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	tag, err := a.pg.GetConnect().Exec(ctx, deleteConsentQuery, user, client)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return vars.ErrConsentNotFound
+	}
+
+	return nil
+}
+
+func (a *authPostgres) GetFederatedIdentity(
+	ctx context.Context,
+	provider, subject string,
+) (*model.FederatedIdentity, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	identity := new(model.FederatedIdentity)
+	if err := a.pg.GetConnect().QueryRow(ctx, getFederatedIdentityQuery, provider, subject).Scan(
+		&identity.Provider, &identity.Subject, &identity.UserEmail, &identity.Email,
+		&identity.CreateDt, &identity.LastLoginDt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, vars.ErrFederatedIdentityNotFound
+		}
+
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// LinkFederatedIdentity does nothing when the upstream account is linked
+// already, so that two concurrent first logins end up with the same link.
+func (a *authPostgres) LinkFederatedIdentity(ctx context.Context, identity *model.FederatedIdentity) error {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	if _, err := a.pg.GetConnect().Exec(
+		ctx, linkFederatedIdentityQuery,
+		identity.Provider, identity.Subject, identity.UserEmail, identity.Email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *authPostgres) TouchFederatedIdentity(ctx context.Context, provider, subject, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, a.connectionTtl)
+	defer cancel()
+
+	if _, err := a.pg.GetConnect().Exec(ctx, touchFederatedIdentityQuery, provider, subject, email); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func scanRegisteredClient(row pgx.Row) (*model.RegisteredClient, error) {
+	client := new(model.RegisteredClient)
+	if err := row.Scan(
+		&client.ID, &client.SecretHash, &client.Scopes, &client.Audiences, &client.GrantTypes, &client.RedirectURIs,
+		&client.Name, &client.Owner, &client.Status, &client.TokenEndpointAuthMethod, &client.RegistrationTokenHash,
+		&client.CreateDt,
+	); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func scanCertificate(row pgx.Row) (*model.DeploymentCertificate, error) {
+	var reason *int
+
+	cert := new(model.DeploymentCertificate)
+	if err := row.Scan(
+		&cert.Serial, &cert.Deployment, &cert.Deployer, &cert.SpiffeID,
+		&cert.NotBefore, &cert.NotAfter, &cert.RevokedAt, &reason,
+	); err != nil {
+		return nil, err
+	}
+
+	if reason != nil {
+		cert.RevocationReason = *reason
+	}
+
+	return cert, nil
+}
@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/mailru/easyjson"
 	"github.com/mxmrykov/polonium-auth/internal/config"
+	"github.com/mxmrykov/polonium-auth/internal/model"
 	"github.com/mxmrykov/polonium-auth/internal/provider"
 	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/mxmrykov/polonium-auth/pkg/utils"
 )
 
 type (
@@ -15,7 +20,35 @@ type (
 		SetCode(user, code string) error
 		GetCode(user string) (string, error)
 		DropCode(user string) error
-		NewAuthSession(user, session string) error
+		NewAuthSession(session *model.Session, ttl time.Duration) error
+		GetAuthSession(session string) (*model.Session, error)
+		ListAuthSessions(user string) ([]*model.Session, error)
+		DropAuthSession(user, session string) error
+		DropAuthSessions(user string) ([]string, error)
+		RevokeToken(jti string, ttl time.Duration) error
+		IsTokenRevoked(jti string) (bool, error)
+		UseDPoPProof(jti string, ttl time.Duration) (bool, error)
+		NewDPoPNonce(nonce string, ttl time.Duration) error
+		IsDPoPNonceValid(nonce string) (bool, error)
+		NewRefreshFamily(session string, family *model.RefreshFamily, ttl time.Duration) error
+		GetRefreshFamily(session string) (*model.RefreshFamily, error)
+		RotateRefreshToken(session, presented, next string, ttl time.Duration) (*model.RefreshFamily, error)
+		RevokeRefreshFamily(session string, family *model.RefreshFamily, ttl time.Duration) error
+		NewAuthorizationCode(code string, ac *model.AuthorizationCode, ttl time.Duration) error
+		UseAuthorizationCode(code string) (*model.AuthorizationCode, error)
+		NewDeviceAuthorization(deviceCode string, da *model.DeviceAuthorization, ttl time.Duration) (bool, error)
+		GetDeviceAuthorization(deviceCode string) (*model.DeviceAuthorization, error)
+		UpdateDeviceAuthorization(deviceCode string, da *model.DeviceAuthorization) error
+		PollDeviceAuthorization(deviceCode string, interval time.Duration) (bool, error)
+		UseDeviceAuthorization(deviceCode string) (*model.DeviceAuthorization, error)
+		GetUserCode(userCode string) (string, error)
+		UseUserCode(userCode string) (string, error)
+		UserCodeFailures(ip string) (int, error)
+		CountUserCodeFailure(ip string, window time.Duration) error
+		NewConsentRequest(id string, cr *model.ConsentRequest, ttl time.Duration) error
+		UseConsentRequest(id string) (*model.ConsentRequest, error)
+		NewFederationState(state string, fs *model.FederationState, ttl time.Duration) error
+		UseFederationState(state string) (*model.FederationState, error)
 	}
 
 	authRedis struct {
@@ -56,7 +89,443 @@ func (a *authRedis) DropCode(user string) error {
 	return a.rdb.Drop(key)
 }
 
-func (a *authRedis) NewAuthSession(user, session string) error {
+// NewAuthSession stores the session and indexes it under its user. It is
+// also used to prolong an existing session.
+func (a *authRedis) NewAuthSession(session *model.Session, ttl time.Duration) error {
+	val, err := easyjson.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("cannot marshal session: %v", err)
+	}
+
+	if err := a.rdb.Set(fmt.Sprintf(vars.AuthSessions, session.ID), string(val), ttl); err != nil {
+		return err
+	}
+
+	return a.rdb.AddMember(fmt.Sprintf(vars.AuthSessionsUsers, session.User), session.ID, ttl)
+}
+
+func (a *authRedis) GetAuthSession(session string) (*model.Session, error) {
+	raw, err := a.rdb.Get(fmt.Sprintf(vars.AuthSessions, session))
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return nil, vars.ErrSessionNotFound
+		}
+
+		return nil, err
+	}
+
+	s := new(model.Session)
+	if err := easyjson.Unmarshal([]byte(raw), s); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal session: %v", err)
+	}
+
+	return s, nil
+}
+
+func (a *authRedis) ListAuthSessions(user string) ([]*model.Session, error) {
+	key := fmt.Sprintf(vars.AuthSessionsUsers, user)
+
+	ids, err := a.rdb.Members(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*model.Session, 0, len(ids))
+	for _, id := range ids {
+		s, err := a.GetAuthSession(id)
+		if err != nil {
+			if errors.Is(err, vars.ErrSessionNotFound) {
+				_ = a.rdb.DropMember(key, id)
+				continue
+			}
+
+			return nil, err
+		}
+
+		sessions = append(sessions, s)
+	}
+
+	return sessions, nil
+}
+
+func (a *authRedis) DropAuthSession(user, session string) error {
+	if err := a.rdb.Drop(fmt.Sprintf(vars.AuthSessions, session)); err != nil {
+		return err
+	}
+
+	if err := a.rdb.Drop(fmt.Sprintf(vars.AuthRefreshFamilies, session)); err != nil {
+		return err
+	}
+
+	return a.rdb.DropMember(fmt.Sprintf(vars.AuthSessionsUsers, user), session)
+}
+
+func (a *authRedis) DropAuthSessions(user string) ([]string, error) {
 	key := fmt.Sprintf(vars.AuthSessionsUsers, user)
-	return a.rdb.Set(key, session, time.Hour)
+
+	ids, err := a.rdb.Members(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if err := a.rdb.Drop(fmt.Sprintf(vars.AuthSessions, id)); err != nil {
+			return nil, err
+		}
+
+		if err := a.rdb.Drop(fmt.Sprintf(vars.AuthRefreshFamilies, id)); err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, a.rdb.Drop(key)
+}
+
+func (a *authRedis) RevokeToken(jti string, ttl time.Duration) error {
+	key := fmt.Sprintf(vars.AuthRevokedTokens, jti)
+	return a.rdb.Set(key, "1", ttl)
+}
+
+func (a *authRedis) IsTokenRevoked(jti string) (bool, error) {
+	key := fmt.Sprintf(vars.AuthRevokedTokens, jti)
+	return a.rdb.IsExists(key)
+}
+
+// UseDPoPProof remembers the proof jti and reports false when it was
+// already used.
+func (a *authRedis) UseDPoPProof(jti string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf(vars.AuthDPoPProofs, jti)
+	return a.rdb.SetNX(key, "1", ttl)
+}
+
+func (a *authRedis) NewDPoPNonce(nonce string, ttl time.Duration) error {
+	key := fmt.Sprintf(vars.AuthDPoPNonces, nonce)
+	return a.rdb.Set(key, "1", ttl)
+}
+
+func (a *authRedis) IsDPoPNonceValid(nonce string) (bool, error) {
+	key := fmt.Sprintf(vars.AuthDPoPNonces, nonce)
+	return a.rdb.IsExists(key)
+}
+
+func (a *authRedis) NewRefreshFamily(session string, family *model.RefreshFamily, ttl time.Duration) error {
+	val, err := easyjson.Marshal(family)
+	if err != nil {
+		return fmt.Errorf("cannot marshal refresh family: %v", err)
+	}
+
+	key := fmt.Sprintf(vars.AuthRefreshFamilies, session)
+	return a.rdb.Set(key, string(val), ttl)
+}
+
+func (a *authRedis) GetRefreshFamily(session string) (*model.RefreshFamily, error) {
+	family, _, err := a.refreshFamily(fmt.Sprintf(vars.AuthRefreshFamilies, session))
+	return family, err
+}
+
+// refreshFamily returns the family stored under key along with its raw
+// value, which RotateRefreshToken compares against when swapping it.
+func (a *authRedis) refreshFamily(key string) (*model.RefreshFamily, string, error) {
+	raw, err := a.rdb.Get(key)
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return nil, "", vars.ErrRefreshFamilyNotFound
+		}
+
+		return nil, "", fmt.Errorf("cannot get refresh family: %v", err)
+	}
+
+	family := new(model.RefreshFamily)
+	if err := easyjson.Unmarshal([]byte(raw), family); err != nil {
+		return nil, "", fmt.Errorf("cannot unmarshal refresh family: %v", err)
+	}
+
+	return family, raw, nil
+}
+
+// RotateRefreshToken retires the presented refresh token in favour of next.
+// Presenting a retired token is reported as a reuse, and the caller is
+// expected to revoke the family.
+func (a *authRedis) RotateRefreshToken(session, presented, next string, ttl time.Duration) (*model.RefreshFamily, error) {
+	key := fmt.Sprintf(vars.AuthRefreshFamilies, session)
+
+	family, raw, err := a.refreshFamily(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if family.Revoked {
+		return family, vars.ErrRefreshFamilyRevoked
+	}
+
+	if family.Current != presented {
+		return family, vars.ErrRefreshTokenReused
+	}
+
+	rotated := *family
+	rotated.Current = next
+
+	val, err := easyjson.Marshal(rotated)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal refresh family: %v", err)
+	}
+
+	swapped, err := a.rdb.CompareAndSwap(key, raw, string(val), ttl)
+	if err != nil {
+		return nil, fmt.Errorf("cannot rotate refresh token: %v", err)
+	}
+
+	// the same token was rotated concurrently, which is a reuse as well
+	if !swapped {
+		return family, vars.ErrRefreshTokenReused
+	}
+
+	return &rotated, nil
+}
+
+func (a *authRedis) RevokeRefreshFamily(session string, family *model.RefreshFamily, ttl time.Duration) error {
+	revoked := *family
+	revoked.Revoked = true
+
+	val, err := easyjson.Marshal(revoked)
+	if err != nil {
+		return fmt.Errorf("cannot marshal refresh family: %v", err)
+	}
+
+	return a.rdb.Set(fmt.Sprintf(vars.AuthRefreshFamilies, session), string(val), ttl)
+}
+
+func (a *authRedis) NewAuthorizationCode(code string, ac *model.AuthorizationCode, ttl time.Duration) error {
+	val, err := easyjson.Marshal(ac)
+	if err != nil {
+		return fmt.Errorf("cannot marshal authorization code: %v", err)
+	}
+
+	return a.rdb.Set(fmt.Sprintf(vars.AuthOAuthCodes, code), string(val), ttl)
+}
+
+// UseAuthorizationCode removes the code while reading it, so that it can
+// only be redeemed once.
+func (a *authRedis) UseAuthorizationCode(code string) (*model.AuthorizationCode, error) {
+	raw, err := a.rdb.GetDel(fmt.Sprintf(vars.AuthOAuthCodes, code))
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return nil, vars.ErrAuthCodeNotFound
+		}
+
+		return nil, err
+	}
+
+	ac := new(model.AuthorizationCode)
+	if err := easyjson.Unmarshal([]byte(raw), ac); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal authorization code: %v", err)
+	}
+
+	return ac, nil
+}
+
+// NewDeviceAuthorization stores the device code and indexes it under its
+// normalized user code. It reports false when the user code is taken.
+func (a *authRedis) NewDeviceAuthorization(deviceCode string, da *model.DeviceAuthorization, ttl time.Duration) (bool, error) {
+	val, err := easyjson.Marshal(da)
+	if err != nil {
+		return false, fmt.Errorf("cannot marshal device authorization: %v", err)
+	}
+
+	userCodeKey := fmt.Sprintf(vars.AuthOAuthUserCodes, utils.NormalizeUserCode(da.UserCode))
+	ok, err := a.rdb.SetNX(userCodeKey, deviceCode, ttl)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return true, a.rdb.Set(fmt.Sprintf(vars.AuthOAuthDeviceCodes, deviceCode), string(val), ttl)
+}
+
+func (a *authRedis) GetDeviceAuthorization(deviceCode string) (*model.DeviceAuthorization, error) {
+	_, da, err := a.getDeviceAuthorization(deviceCode)
+	return da, err
+}
+
+// UpdateDeviceAuthorization replaces a pending device authorization. Once
+// approved or denied it no longer changes, so it is decided only once.
+func (a *authRedis) UpdateDeviceAuthorization(deviceCode string, da *model.DeviceAuthorization) error {
+	raw, current, err := a.getDeviceAuthorization(deviceCode)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(current.ExpiresAt)
+	if current.Status != vars.DeviceAuthorizationPending || ttl <= 0 {
+		return vars.ErrDeviceCodeNotFound
+	}
+
+	val, err := easyjson.Marshal(da)
+	if err != nil {
+		return fmt.Errorf("cannot marshal device authorization: %v", err)
+	}
+
+	swapped, err := a.rdb.CompareAndSwap(fmt.Sprintf(vars.AuthOAuthDeviceCodes, deviceCode), raw, string(val), ttl)
+	if err != nil {
+		return fmt.Errorf("cannot update device authorization: %v", err)
+	}
+
+	if !swapped {
+		return vars.ErrDeviceCodeNotFound
+	}
+
+	return nil
+}
+
+// PollDeviceAuthorization reports false when the device polled again
+// within the interval.
+func (a *authRedis) PollDeviceAuthorization(deviceCode string, interval time.Duration) (bool, error) {
+	key := fmt.Sprintf(vars.AuthOAuthDevicePolls, deviceCode)
+	return a.rdb.SetNX(key, "1", interval)
+}
+
+// UseDeviceAuthorization removes the device code while reading it, so that
+// it can only be redeemed once.
+func (a *authRedis) UseDeviceAuthorization(deviceCode string) (*model.DeviceAuthorization, error) {
+	raw, err := a.rdb.GetDel(fmt.Sprintf(vars.AuthOAuthDeviceCodes, deviceCode))
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return nil, vars.ErrDeviceCodeNotFound
+		}
+
+		return nil, err
+	}
+
+	da := new(model.DeviceAuthorization)
+	if err := easyjson.Unmarshal([]byte(raw), da); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal device authorization: %v", err)
+	}
+
+	return da, nil
+}
+
+// GetUserCode returns the device code of the user code.
+func (a *authRedis) GetUserCode(userCode string) (string, error) {
+	deviceCode, err := a.rdb.Get(fmt.Sprintf(vars.AuthOAuthUserCodes, utils.NormalizeUserCode(userCode)))
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return "", vars.ErrUserCodeNotFound
+		}
+
+		return "", err
+	}
+
+	return deviceCode, nil
+}
+
+// UseUserCode returns the device code of the user code and removes the
+// user code, so that it can only be entered once.
+func (a *authRedis) UseUserCode(userCode string) (string, error) {
+	deviceCode, err := a.rdb.GetDel(fmt.Sprintf(vars.AuthOAuthUserCodes, utils.NormalizeUserCode(userCode)))
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return "", vars.ErrUserCodeNotFound
+		}
+
+		return "", err
+	}
+
+	return deviceCode, nil
+}
+
+// UserCodeFailures returns how many unknown user codes were entered from
+// the ip within the current window.
+func (a *authRedis) UserCodeFailures(ip string) (int, error) {
+	raw, err := a.rdb.Get(fmt.Sprintf(vars.AuthOAuthUserCodeIPs, ip))
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return strconv.Atoi(raw)
+}
+
+// CountUserCodeFailure counts an unknown user code entered from the ip. The
+// window starts with the first failure.
+func (a *authRedis) CountUserCodeFailure(ip string, window time.Duration) error {
+	_, err := a.rdb.Incr(fmt.Sprintf(vars.AuthOAuthUserCodeIPs, ip), window)
+	return err
+}
+
+func (a *authRedis) getDeviceAuthorization(deviceCode string) (string, *model.DeviceAuthorization, error) {
+	raw, err := a.rdb.Get(fmt.Sprintf(vars.AuthOAuthDeviceCodes, deviceCode))
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return "", nil, vars.ErrDeviceCodeNotFound
+		}
+
+		return "", nil, err
+	}
+
+	da := new(model.DeviceAuthorization)
+	if err := easyjson.Unmarshal([]byte(raw), da); err != nil {
+		return "", nil, fmt.Errorf("cannot unmarshal device authorization: %v", err)
+	}
+
+	return raw, da, nil
+}
+
+func (a *authRedis) NewConsentRequest(id string, cr *model.ConsentRequest, ttl time.Duration) error {
+	val, err := easyjson.Marshal(cr)
+	if err != nil {
+		return fmt.Errorf("cannot marshal consent request: %v", err)
+	}
+
+	return a.rdb.Set(fmt.Sprintf(vars.AuthOAuthConsents, id), string(val), ttl)
+}
+
+// UseConsentRequest removes the consent request while reading it, so that
+// the user answers it only once.
+func (a *authRedis) UseConsentRequest(id string) (*model.ConsentRequest, error) {
+	raw, err := a.rdb.GetDel(fmt.Sprintf(vars.AuthOAuthConsents, id))
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return nil, vars.ErrConsentRequestNotFound
+		}
+
+		return nil, err
+	}
+
+	cr := new(model.ConsentRequest)
+	if err := easyjson.Unmarshal([]byte(raw), cr); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal consent request: %v", err)
+	}
+
+	return cr, nil
+}
+
+func (a *authRedis) NewFederationState(state string, fs *model.FederationState, ttl time.Duration) error {
+	val, err := easyjson.Marshal(fs)
+	if err != nil {
+		return fmt.Errorf("cannot marshal federation state: %v", err)
+	}
+
+	return a.rdb.Set(fmt.Sprintf(vars.AuthFederationStates, state), string(val), ttl)
+}
+
+// UseFederationState removes the state while reading it, so that a callback
+// cannot be replayed.
+func (a *authRedis) UseFederationState(state string) (*model.FederationState, error) {
+	raw, err := a.rdb.GetDel(fmt.Sprintf(vars.AuthFederationStates, state))
+	if err != nil {
+		if errors.Is(err, provider.ErrKeyNotFound) {
+			return nil, vars.ErrFederationStateNotFound
+		}
+
+		return nil, err
+	}
+
+	fs := new(model.FederationState)
+	if err := easyjson.Unmarshal([]byte(raw), fs); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal federation state: %v", err)
+	}
+
+	return fs, nil
 }
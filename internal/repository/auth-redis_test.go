@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/provider"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+// memRedis is a provider.IRedis over maps. Expiry is not modelled.
+type memRedis struct {
+	provider.IRedis
+
+	values map[string]string
+	sets   map[string]map[string]bool
+}
+
+func newMemRedis() *memRedis {
+	return &memRedis{values: map[string]string{}, sets: map[string]map[string]bool{}}
+}
+
+func (m *memRedis) Get(key string) (string, error) {
+	val, ok := m.values[key]
+	if !ok {
+		return "", provider.ErrKeyNotFound
+	}
+
+	return val, nil
+}
+
+func (m *memRedis) IsExists(key string) (bool, error) {
+	_, ok := m.values[key]
+	return ok, nil
+}
+
+func (m *memRedis) Set(key, value string, _ time.Duration) error {
+	m.values[key] = value
+	return nil
+}
+
+func (m *memRedis) Drop(key string) error {
+	delete(m.values, key)
+	return nil
+}
+
+func (m *memRedis) CompareAndSwap(key, old, value string, _ time.Duration) (bool, error) {
+	if m.values[key] != old {
+		return false, nil
+	}
+
+	m.values[key] = value
+	return true, nil
+}
+
+func (m *memRedis) AddMember(key, member string, _ time.Duration) error {
+	if m.sets[key] == nil {
+		m.sets[key] = map[string]bool{}
+	}
+	m.sets[key][member] = true
+	return nil
+}
+
+func (m *memRedis) DropMember(key, member string) error {
+	delete(m.sets[key], member)
+	return nil
+}
+
+func (m *memRedis) Members(key string) ([]string, error) {
+	members := make([]string, 0, len(m.sets[key]))
+	for member := range m.sets[key] {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (m *memRedis) Incr(key string, _ time.Duration) (int64, error) {
+	count, _ := strconv.ParseInt(m.values[key], 10, 64)
+	count++
+	m.values[key] = strconv.FormatInt(count, 10)
+	return count, nil
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	a := &authRedis{rdb: newMemRedis()}
+
+	if err := a.NewRefreshFamily("session", &model.RefreshFamily{User: "user", Current: "first"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	family, err := a.RotateRefreshToken("session", "first", "second", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if family.Current != "second" || family.User != "user" {
+		t.Fatalf("unexpected family after rotation: %+v", family)
+	}
+
+	family, err = a.RotateRefreshToken("session", "first", "third", time.Hour)
+	if !errors.Is(err, vars.ErrRefreshTokenReused) {
+		t.Fatalf("retired token: got %v, want %v", err, vars.ErrRefreshTokenReused)
+	}
+
+	// reuse is only reported, the caller decides to revoke
+	if family.Revoked || family.Current != "second" {
+		t.Fatalf("unexpected family after reuse: %+v", family)
+	}
+
+	if err := a.RevokeRefreshFamily("session", family, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.RotateRefreshToken("session", "second", "third", time.Hour); !errors.Is(err, vars.ErrRefreshFamilyRevoked) {
+		t.Errorf("current token of a revoked family: got %v, want %v", err, vars.ErrRefreshFamilyRevoked)
+	}
+
+	if family, err := a.GetRefreshFamily("session"); err != nil || !family.Revoked || family.Current != "second" {
+		t.Errorf("stored family: got %+v, %v", family, err)
+	}
+
+	if _, err := a.GetRefreshFamily("unknown"); !errors.Is(err, vars.ErrRefreshFamilyNotFound) {
+		t.Errorf("unknown family: got %v, want %v", err, vars.ErrRefreshFamilyNotFound)
+	}
+
+	if _, err := a.RotateRefreshToken("unknown", "first", "second", time.Hour); !errors.Is(err, vars.ErrRefreshFamilyNotFound) {
+		t.Errorf("unknown family: got %v, want %v", err, vars.ErrRefreshFamilyNotFound)
+	}
+}
+
+func TestAuthSessions(t *testing.T) {
+	rdb := newMemRedis()
+	a := &authRedis{rdb: rdb}
+
+	for _, id := range []string{"laptop", "phone"} {
+		if err := a.NewAuthSession(&model.Session{ID: id, User: "user"}, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := a.NewRefreshFamily(id, &model.RefreshFamily{User: "user", Current: id}, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sessions, err := a.ListAuthSessions("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sessions) != 2 {
+		t.Fatalf("listed %d sessions, want 2", len(sessions))
+	}
+
+	if err := a.DropAuthSession("user", "phone"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.GetAuthSession("phone"); !errors.Is(err, vars.ErrSessionNotFound) {
+		t.Errorf("dropped session: got %v, want %v", err, vars.ErrSessionNotFound)
+	}
+
+	if _, err := a.RotateRefreshToken("phone", "phone", "next", time.Hour); !errors.Is(err, vars.ErrRefreshFamilyNotFound) {
+		t.Errorf("refresh family of a dropped session: got %v, want %v", err, vars.ErrRefreshFamilyNotFound)
+	}
+
+	// a session that expired on its own is pruned from the index
+	if err := rdb.Drop(fmt.Sprintf(vars.AuthSessions, "laptop")); err != nil {
+		t.Fatal(err)
+	}
+
+	if sessions, err := a.ListAuthSessions("user"); err != nil || len(sessions) != 0 {
+		t.Errorf("sessions after expiry: %v, %v", sessions, err)
+	}
+
+	if members, _ := rdb.Members(fmt.Sprintf(vars.AuthSessionsUsers, "user")); len(members) != 0 {
+		t.Errorf("index still lists %v", members)
+	}
+}
+
+func TestDropAuthSessions(t *testing.T) {
+	rdb := newMemRedis()
+	a := &authRedis{rdb: rdb}
+
+	for _, s := range []*model.Session{{ID: "laptop", User: "user"}, {ID: "phone", User: "user"}, {ID: "other", User: "other"}} {
+		if err := a.NewAuthSession(s, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := a.NewRefreshFamily(s.ID, &model.RefreshFamily{User: s.User, Current: s.ID}, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dropped, err := a.DropAuthSessions("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dropped) != 2 {
+		t.Errorf("dropped %v, want both sessions of the user", dropped)
+	}
+
+	for _, id := range []string{"laptop", "phone"} {
+		if _, err := a.GetAuthSession(id); !errors.Is(err, vars.ErrSessionNotFound) {
+			t.Errorf("session %s: got %v, want %v", id, err, vars.ErrSessionNotFound)
+		}
+
+		if _, err := a.RotateRefreshToken(id, id, "next", time.Hour); !errors.Is(err, vars.ErrRefreshFamilyNotFound) {
+			t.Errorf("refresh family %s: got %v, want %v", id, err, vars.ErrRefreshFamilyNotFound)
+		}
+	}
+
+	if _, err := a.GetAuthSession("other"); err != nil {
+		t.Errorf("session of another user: %v", err)
+	}
+}
+
+func TestUserCodeFailures(t *testing.T) {
+	a := &authRedis{rdb: newMemRedis()}
+
+	for want := 0; want < 3; want++ {
+		failures, err := a.UserCodeFailures("10.0.0.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if failures != want {
+			t.Fatalf("UserCodeFailures() = %d, want %d", failures, want)
+		}
+
+		if err := a.CountUserCodeFailure("10.0.0.1", time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if failures, _ := a.UserCodeFailures("10.0.0.2"); failures != 0 {
+		t.Errorf("UserCodeFailures() of another ip = %d, want 0", failures)
+	}
+}
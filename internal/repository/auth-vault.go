@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"fmt"
+	"net/url"
 
+	"github.com/mailru/easyjson"
 	"github.com/mxmrykov/polonium-auth/internal/config"
+	"github.com/mxmrykov/polonium-auth/internal/model"
 	"github.com/mxmrykov/polonium-auth/internal/provider"
 	"github.com/mxmrykov/polonium-auth/internal/vars"
 )
@@ -14,6 +17,16 @@ type (
 		PutNewUser(ctx context.Context, user, pwd, totpSecret string) error
 		GetPwdHash(ctx context.Context, user string) (string, error)
 		GetTOTPSecret(ctx context.Context, user string) (string, error)
+		GetSigningKeys(ctx context.Context, alg string) (*model.SigningKeySet, int, error)
+		PutSigningKeys(ctx context.Context, alg string, set *model.SigningKeySet, version int) error
+		GetEncryptionKey(ctx context.Context, audience string) (*model.EncryptionKey, error)
+		PutEncryptionKey(ctx context.Context, audience string, key *model.EncryptionKey) error
+		GetSSHCAKey(ctx context.Context) (string, error)
+		PutSSHCAKey(ctx context.Context, key string) error
+		GetX509CA(ctx context.Context) (*model.X509CA, error)
+		PutX509CA(ctx context.Context, ca *model.X509CA) error
+		GetSAMLKey(ctx context.Context) (*model.SAMLKey, error)
+		PutSAMLKey(ctx context.Context, key *model.SAMLKey) error
 	}
 
 	authVault struct {
@@ -86,3 +99,210 @@ func (a *authVault) GetTOTPSecret(ctx context.Context, user string) (string, err
 
 	return val.(string), nil
 }
+
+func (a *authVault) GetSigningKeys(ctx context.Context, alg string) (*model.SigningKeySet, int, error) {
+	secret, version, err := a.vault.ReadVersioned(ctx, fmt.Sprintf(vars.AuthJWTSigningKeys, alg))
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	set := new(model.SigningKeySet)
+	if secret == nil {
+		return set, 0, nil
+	}
+
+	val, ok := secret["val"].(string)
+
+	if !ok {
+		return nil, 0, vars.ErrNoSuchVariableInVault
+	}
+
+	if err := easyjson.Unmarshal([]byte(val), set); err != nil {
+		return nil, 0, fmt.Errorf("cannot unmarshal signing keys: %v", err)
+	}
+
+	return set, version, nil
+}
+
+func (a *authVault) PutSigningKeys(ctx context.Context, alg string, set *model.SigningKeySet, version int) error {
+	val, err := easyjson.Marshal(set)
+
+	if err != nil {
+		return fmt.Errorf("cannot marshal signing keys: %v", err)
+	}
+
+	return a.vault.WriteCAS(
+		ctx,
+		fmt.Sprintf(vars.AuthJWTSigningKeys, alg),
+		map[string]interface{}{
+			"val": string(val),
+		},
+		version,
+	)
+}
+
+// GetEncryptionKey returns nil when the audience has no key yet.
+func (a *authVault) GetEncryptionKey(ctx context.Context, audience string) (*model.EncryptionKey, error) {
+	secret, _, err := a.vault.ReadVersioned(ctx, fmt.Sprintf(vars.AuthJWEAudienceKeys, url.PathEscape(audience)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == nil {
+		return nil, nil
+	}
+
+	val, ok := secret["val"].(string)
+
+	if !ok {
+		return nil, vars.ErrNoSuchVariableInVault
+	}
+
+	key := new(model.EncryptionKey)
+	if err := easyjson.Unmarshal([]byte(val), key); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal encryption key: %v", err)
+	}
+
+	return key, nil
+}
+
+// PutEncryptionKey only creates the key, an existing key is never replaced.
+func (a *authVault) PutEncryptionKey(ctx context.Context, audience string, key *model.EncryptionKey) error {
+	val, err := easyjson.Marshal(key)
+
+	if err != nil {
+		return fmt.Errorf("cannot marshal encryption key: %v", err)
+	}
+
+	return a.vault.WriteCAS(
+		ctx,
+		fmt.Sprintf(vars.AuthJWEAudienceKeys, url.PathEscape(audience)),
+		map[string]interface{}{
+			"val": string(val),
+		},
+		0,
+	)
+}
+
+// GetSSHCAKey returns an empty key when the CA is not initialised yet.
+func (a *authVault) GetSSHCAKey(ctx context.Context) (string, error) {
+	secret, _, err := a.vault.ReadVersioned(ctx, vars.AuthSSHCAKey)
+
+	if err != nil {
+		return "", err
+	}
+
+	if secret == nil {
+		return "", nil
+	}
+
+	val, ok := secret["val"].(string)
+
+	if !ok {
+		return "", vars.ErrNoSuchVariableInVault
+	}
+
+	return val, nil
+}
+
+// PutSSHCAKey only creates the key, the CA key is never replaced.
+func (a *authVault) PutSSHCAKey(ctx context.Context, key string) error {
+	return a.vault.WriteCAS(
+		ctx,
+		vars.AuthSSHCAKey,
+		map[string]interface{}{
+			"val": key,
+		},
+		0,
+	)
+}
+
+// GetX509CA returns nil when the CA is not initialised yet.
+func (a *authVault) GetX509CA(ctx context.Context) (*model.X509CA, error) {
+	secret, _, err := a.vault.ReadVersioned(ctx, vars.AuthX509CA)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == nil {
+		return nil, nil
+	}
+
+	val, ok := secret["val"].(string)
+
+	if !ok {
+		return nil, vars.ErrNoSuchVariableInVault
+	}
+
+	ca := new(model.X509CA)
+	if err := easyjson.Unmarshal([]byte(val), ca); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal x509 ca: %v", err)
+	}
+
+	return ca, nil
+}
+
+// PutX509CA only creates the CA, it is never replaced.
+func (a *authVault) PutX509CA(ctx context.Context, ca *model.X509CA) error {
+	val, err := easyjson.Marshal(ca)
+
+	if err != nil {
+		return fmt.Errorf("cannot marshal x509 ca: %v", err)
+	}
+
+	return a.vault.WriteCAS(
+		ctx,
+		vars.AuthX509CA,
+		map[string]interface{}{
+			"val": string(val),
+		},
+		0,
+	)
+}
+
+// GetSAMLKey returns nil when the key is not created yet.
+func (a *authVault) GetSAMLKey(ctx context.Context) (*model.SAMLKey, error) {
+	secret, _, err := a.vault.ReadVersioned(ctx, vars.AuthSAMLKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == nil {
+		return nil, nil
+	}
+
+	val, ok := secret["val"].(string)
+
+	if !ok {
+		return nil, vars.ErrNoSuchVariableInVault
+	}
+
+	key := new(model.SAMLKey)
+	if err := easyjson.Unmarshal([]byte(val), key); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal saml key: %v", err)
+	}
+
+	return key, nil
+}
+
+// PutSAMLKey only creates the key, it is never replaced.
+func (a *authVault) PutSAMLKey(ctx context.Context, key *model.SAMLKey) error {
+	val, err := easyjson.Marshal(key)
+
+	if err != nil {
+		return fmt.Errorf("cannot marshal saml key: %v", err)
+	}
+
+	return a.vault.WriteCAS(
+		ctx,
+		vars.AuthSAMLKey,
+		map[string]interface{}{
+			"val": string(val),
+		},
+		0,
+	)
+}
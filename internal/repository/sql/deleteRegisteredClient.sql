@@ -0,0 +1 @@
+delete from oauth_clients where id = $1 and owner <> ''
\ No newline at end of file
@@ -0,0 +1,6 @@
+select c.serial, c.deployment_id, c.deployer, c.spiffe_id, c.not_before, c.not_after,
+       coalesce(c.revoked_at, case when d.id is null or d.state = $2 then c.not_before end),
+       coalesce(c.revocation_reason, case when d.id is null or d.state = $2 then $3 end)
+from deployment_certificate c
+left join deployment d on d.id = c.deployment_id
+where c.serial = $1
\ No newline at end of file
@@ -0,0 +1 @@
+select id, secret_hash, scopes, audiences, grant_types, redirect_uris, name, owner <> '' from oauth_clients where id = $1 and status = $2
\ No newline at end of file
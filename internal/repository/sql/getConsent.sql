@@ -0,0 +1 @@
+select scopes from oauth_consents where user_id = $1 and client_id = $2
\ No newline at end of file
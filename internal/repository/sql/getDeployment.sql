@@ -0,0 +1 @@
+select id, deployer, name, state, ip, last_connection, create_dt from deployment where id = $1
\ No newline at end of file
@@ -0,0 +1 @@
+select provider, subject, user_email, email, create_dt, last_login_dt from federated_identities where provider = $1 and subject = $2
\ No newline at end of file
@@ -0,0 +1,4 @@
+select id, secret_hash, scopes, audiences, grant_types, redirect_uris,
+       name, owner, status, token_endpoint_auth_method, registration_token_hash, create_dt
+from oauth_clients
+where id = $1
\ No newline at end of file
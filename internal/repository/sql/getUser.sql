@@ -0,0 +1 @@
+select email, id, verificated, baned, ssh_sign, deployer, create_dt from users where email = $1
\ No newline at end of file
@@ -0,0 +1,2 @@
+insert into federated_identities (provider, subject, user_email, email) values ($1, $2, $3, $4)
+on conflict (provider, subject) do nothing
\ No newline at end of file
@@ -0,0 +1,5 @@
+select c.client_id, coalesce(o.name, ''), c.scopes, c.create_dt, c.update_dt
+from oauth_consents c
+left join oauth_clients o on o.id = c.client_id
+where c.user_id = $1
+order by c.update_dt desc
\ No newline at end of file
@@ -0,0 +1,5 @@
+select id, secret_hash, scopes, audiences, grant_types, redirect_uris,
+       name, owner, status, token_endpoint_auth_method, registration_token_hash, create_dt
+from oauth_clients
+where status = $1 and owner <> ''
+order by create_dt
\ No newline at end of file
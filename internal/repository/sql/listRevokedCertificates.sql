@@ -0,0 +1,7 @@
+select c.serial, c.deployment_id, c.deployer, c.spiffe_id, c.not_before, c.not_after,
+       coalesce(c.revoked_at, c.not_before),
+       coalesce(c.revocation_reason, $2)
+from deployment_certificate c
+left join deployment d on d.id = c.deployment_id
+where c.not_after > now()
+  and (c.revoked_at is not null or d.id is null or d.state = $1)
\ No newline at end of file
@@ -0,0 +1,2 @@
+insert into oauth_clients (id, secret_hash, scopes, audiences, grant_types, redirect_uris, name, owner, status, token_endpoint_auth_method, registration_token_hash)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
\ No newline at end of file
@@ -0,0 +1 @@
+update oauth_clients set status = $2, update_dt = now() where id = $1 and owner <> ''
\ No newline at end of file
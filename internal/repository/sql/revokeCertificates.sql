@@ -0,0 +1 @@
+update deployment_certificate set revoked_at = now(), revocation_reason = $2 where deployment_id = $1 and revoked_at is null and not_after > now()
\ No newline at end of file
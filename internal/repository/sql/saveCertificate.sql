@@ -0,0 +1 @@
+insert into deployment_certificate (serial, deployment_id, deployer, spiffe_id, not_before, not_after) values ($1, $2, $3, $4, $5, $6)
\ No newline at end of file
@@ -0,0 +1,3 @@
+insert into oauth_consents (user_id, client_id, scopes) values ($1, $2, $3)
+on conflict (user_id, client_id) do update
+set scopes = array(select distinct unnest(oauth_consents.scopes || excluded.scopes)), update_dt = now()
\ No newline at end of file
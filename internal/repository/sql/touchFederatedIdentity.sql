@@ -0,0 +1 @@
+update federated_identities set email = $3, last_login_dt = now() where provider = $1 and subject = $2
\ No newline at end of file
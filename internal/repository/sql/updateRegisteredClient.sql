@@ -0,0 +1,3 @@
+update oauth_clients
+set scopes = $2, grant_types = $3, redirect_uris = $4, name = $5, status = $6, update_dt = now()
+where id = $1
\ No newline at end of file
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/server/httpHost/middlewares"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	Consent struct {
+		consent service.IConsent
+	}
+)
+
+func NewConsent(consent service.IConsent) *Consent {
+	return &Consent{
+		consent: consent,
+	}
+}
+
+func (cs *Consent) Consents(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	consents, err := cs.consent.List(c.Request.Context(), claims.UserID)
+	if err != nil {
+		logger.Err(err).Msg("cannot list consents")
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Data: consents,
+	})
+}
+
+func (cs *Consent) RevokeConsent(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	// ---===Delegated tokens cannot revoke consents===---
+	if claims.Actor != nil {
+		c.JSON(http.StatusForbidden, model.Response{
+			Error: "delegated tokens cannot revoke consents",
+		})
+		return
+	}
+
+	if err := cs.consent.Revoke(c.Request.Context(), claims.UserID, c.Param("client")); err != nil {
+		logger.Err(err).Msg("cannot revoke consent")
+
+		if errors.Is(err, vars.ErrConsentNotFound) {
+			c.JSON(http.StatusNotFound, model.Response{
+				Error: "consent not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Message: "consent revoked",
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mailru/easyjson"
 	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/server/httpHost/middlewares"
 	"github.com/mxmrykov/polonium-auth/internal/service"
 	"github.com/mxmrykov/polonium-auth/internal/vars"
 	"github.com/rs/zerolog/log"
@@ -15,18 +17,24 @@ import (
 
 type (
 	ExtAuth struct {
-		auth service.IAuth
-		totp service.ITOTP
+		auth          service.IAuth
+		totp          service.ITOTP
+		defaultClient string
+		loginClients  []string
 	}
 )
 
 func NewExtAuth(
 	auth service.IAuth,
 	totp service.ITOTP,
+	defaultClient string,
+	loginClients []string,
 ) *ExtAuth {
 	return &ExtAuth{
-		auth: auth,
-		totp: totp,
+		auth:          auth,
+		totp:          totp,
+		defaultClient: defaultClient,
+		loginClients:  loginClients,
 	}
 }
 
@@ -235,6 +243,19 @@ func (ea *ExtAuth) Complete(c *gin.Context) {
 		return
 	}
 
+	// ---===Only public first-party clients log in here===---
+	if r.ClientID == "" {
+		r.ClientID = ea.defaultClient
+	}
+
+	if !slices.Contains(ea.loginClients, r.ClientID) {
+		logger.Str("client", r.ClientID).Msg("client cannot use the login endpoint")
+		c.JSON(http.StatusBadRequest, model.Response{
+			Error: vars.ErrUnauthorizedClient.Error(),
+		})
+		return
+	}
+
 	if err := ea.auth.VerifyUser(ctx, r.Email, r.Pwd); err != nil {
 		logger.Err(err).Msg("cannot verify user")
 
@@ -275,10 +296,35 @@ func (ea *ExtAuth) Complete(c *gin.Context) {
 		return
 	}
 
-	access, refresh, err := ea.auth.CreateSession(r.Email)
+	access, refresh, err := ea.auth.CreateSession(ctx, r.Email, &model.Session{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+		MFA:       vars.MFAMethodTOTP,
+		Client:    r.ClientID,
+		Scope:     r.Scope,
+		Audience:  r.Audience,
+		DPoPJKT:   middlewares.DPoPJKT(c),
+	})
 
 	if err != nil {
 		logger.Err(err).Msg("cannot create session")
+
+		if errors.Is(err, vars.ErrUnknownClient) ||
+			errors.Is(err, vars.ErrInvalidScope) ||
+			errors.Is(err, vars.ErrInvalidAudience) {
+			c.JSON(http.StatusBadRequest, model.Response{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, vars.ErrUserBanned) {
+			c.JSON(http.StatusForbidden, model.Response{
+				Error: "user is banned",
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, model.Response{
 			Error: "cannot create session",
 		})
@@ -293,15 +339,7 @@ func (ea *ExtAuth) Complete(c *gin.Context) {
 		return
 	}
 
-	c.SetCookie(
-		vars.CookiePoloniumAuth,
-		refresh,
-		3600*24,
-		"/",
-		"localhost",
-		false,
-		true,
-	)
+	setRefreshCookie(c, refresh)
 
 	c.JSON(http.StatusOK, model.Response{
 		Data:    access,
@@ -309,6 +347,66 @@ func (ea *ExtAuth) Complete(c *gin.Context) {
 	})
 }
 
+func (ea *ExtAuth) RefreshToken(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	// ---===Get refresh token from cookie or body===---
+	refresh, err := c.Cookie(vars.CookiePoloniumAuth)
+	if err != nil || refresh == "" {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logger.Err(err).Msg("cannot read body")
+			c.JSON(http.StatusBadRequest, model.Response{
+				Error: "cannot read request body",
+			})
+			return
+		}
+
+		r := new(model.RefreshTokenRequest)
+		if err := easyjson.Unmarshal(body, r); err != nil || r.RefreshToken == "" {
+			logger.Msg("no refresh token provided")
+			c.JSON(http.StatusBadRequest, model.Response{
+				Error: "no refresh token provided",
+			})
+			return
+		}
+		refresh = r.RefreshToken
+	}
+
+	// ---===Rotate refresh token===---
+	access, refresh, err := ea.auth.RefreshSession(c.Request.Context(), refresh, middlewares.DPoPJKT(c))
+	if err != nil {
+		logger.Err(err).Msg("cannot refresh session")
+
+		if errors.Is(err, vars.ErrInvalidRefreshToken) ||
+			errors.Is(err, vars.ErrSessionNotFound) ||
+			errors.Is(err, vars.ErrUserNotFound) ||
+			errors.Is(err, vars.ErrUserBanned) ||
+			errors.Is(err, vars.ErrRefreshFamilyNotFound) ||
+			errors.Is(err, vars.ErrRefreshFamilyRevoked) ||
+			errors.Is(err, vars.ErrRefreshTokenReused) ||
+			errors.Is(err, vars.ErrDPoPKeyMismatch) {
+			clearRefreshCookie(c)
+			c.JSON(http.StatusUnauthorized, model.Response{
+				Error: "session expired",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	setRefreshCookie(c, refresh)
+
+	c.JSON(http.StatusOK, model.Response{
+		Data:    access,
+		Message: "token refreshed",
+	})
+}
+
 func (ea *ExtAuth) Authorize(c *gin.Context) {
 	ctx := c.Request.Context()
 	logger := log.Log().Str("logID", c.GetString("logID"))
@@ -358,3 +456,108 @@ func (ea *ExtAuth) Authorize(c *gin.Context) {
 		Message: "processed",
 	})
 }
+
+func (ea *ExtAuth) Sessions(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	sessions, err := ea.auth.ListSessions(claims.UserID, claims.Session)
+	if err != nil {
+		logger.Err(err).Msg("cannot list sessions")
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Data: sessions,
+	})
+}
+
+func (ea *ExtAuth) RevokeSession(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	if err := ea.auth.RevokeSession(claims.UserID, c.Param("session")); err != nil {
+		logger.Err(err).Msg("cannot revoke session")
+
+		if errors.Is(err, vars.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, model.Response{
+				Error: "session not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Message: "session revoked",
+	})
+}
+
+func (ea *ExtAuth) Logout(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	if err := ea.auth.Logout(claims.UserID, claims.Session); err != nil {
+		logger.Err(err).Msg("cannot logout")
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	clearRefreshCookie(c)
+
+	c.JSON(http.StatusOK, model.Response{
+		Message: "logged out",
+	})
+}
+
+func (ea *ExtAuth) LogoutAll(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	if err := ea.auth.LogoutAll(claims.UserID); err != nil {
+		logger.Err(err).Msg("cannot logout everywhere")
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	clearRefreshCookie(c)
+
+	c.JSON(http.StatusOK, model.Response{
+		Message: "logged out everywhere",
+	})
+}
+
+func setRefreshCookie(c *gin.Context, refresh string) {
+	c.SetCookie(
+		vars.CookiePoloniumAuth,
+		refresh,
+		3600*24,
+		"/",
+		"localhost",
+		false,
+		true,
+	)
+}
+
+func clearRefreshCookie(c *gin.Context) {
+	c.SetCookie(
+		vars.CookiePoloniumAuth,
+		"",
+		-1,
+		"/",
+		"localhost",
+		false,
+		true,
+	)
+}
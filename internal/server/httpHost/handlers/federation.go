@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	Federation struct {
+		federation service.IFederation
+		returnURL  string
+	}
+)
+
+func NewFederation(federation service.IFederation, returnURL string) *Federation {
+	return &Federation{
+		federation: federation,
+		returnURL:  returnURL,
+	}
+}
+
+func (f *Federation) Providers(c *gin.Context) {
+	c.JSON(http.StatusOK, model.Response{
+		Data: f.federation.Providers(),
+	})
+}
+
+func (f *Federation) Login(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	target, err := f.federation.Begin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		logger.Err(err).Msg("cannot begin federated login")
+
+		if errors.Is(err, vars.ErrUnknownFederationProvider) {
+			c.JSON(http.StatusNotFound, model.Response{
+				Error: "federation provider not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadGateway, model.Response{
+			Error: "federation provider is unavailable",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, target)
+}
+
+// Callback is where the upstream provider sends the browser back. Failures
+// are reported to the return url, since there is no page of ours to show.
+func (f *Federation) Callback(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	provider := c.Param("provider")
+
+	// ---===Upstream errors===---
+	if upstreamErr := c.Query("error"); upstreamErr != "" {
+		logger.Str("provider", provider).Str("error", upstreamErr).Msg("upstream login failed")
+		f.fail(c, "access_denied")
+		return
+	}
+
+	_, refresh, err := f.federation.Complete(
+		c.Request.Context(),
+		provider,
+		c.Query("state"),
+		c.Query("code"),
+		&model.Session{
+			UserAgent: c.Request.UserAgent(),
+			IP:        c.ClientIP(),
+		},
+	)
+	if err != nil {
+		logger.Err(err).Str("provider", provider).Msg("cannot complete federated login")
+
+		switch {
+		case errors.Is(err, vars.ErrUnknownFederationProvider), errors.Is(err, vars.ErrFederationStateNotFound):
+			f.fail(c, "invalid_request")
+		case errors.Is(err, vars.ErrFederatedEmailUnverified):
+			f.fail(c, "email_unverified")
+		case errors.Is(err, vars.ErrFederatedDomainNotAllowed):
+			f.fail(c, "domain_not_allowed")
+		case errors.Is(err, vars.ErrFederatedAccountExists):
+			f.fail(c, "account_exists")
+		case errors.Is(err, vars.ErrUserBanned):
+			f.fail(c, "user_banned")
+		default:
+			f.fail(c, "server_error")
+		}
+		return
+	}
+
+	setRefreshCookie(c, refresh)
+	// the browser gets the access token with the refresh cookie
+	c.Redirect(http.StatusFound, f.returnURL)
+}
+
+func (f *Federation) fail(c *gin.Context, code string) {
+	target, err := url.Parse(f.returnURL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Response{
+			Error: code,
+		})
+		return
+	}
+
+	query := target.Query()
+	query.Set("error", code)
+	target.RawQuery = query.Encode()
+
+	c.Redirect(http.StatusFound, target.String())
+}
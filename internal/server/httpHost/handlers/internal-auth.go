@@ -1 +1,50 @@
 package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/server/httpHost/middlewares"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	IntAuth struct {
+		auth service.IAuth
+	}
+)
+
+func NewIntAuth(auth service.IAuth) *IntAuth {
+	return &IntAuth{
+		auth: auth,
+	}
+}
+
+func (ia *IntAuth) Introspect(c *gin.Context) {
+	logger := log.Log().
+		Str("logID", c.GetString("logID")).
+		Str("client", middlewares.Client(c).ID)
+
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, model.OAuthError{
+			Error:            "invalid_request",
+			ErrorDescription: "token is required",
+		})
+		return
+	}
+
+	introspection, err := ia.auth.Introspect(token)
+	if err != nil {
+		logger.Err(err).Msg("cannot introspect token")
+		c.JSON(http.StatusServiceUnavailable, model.OAuthError{
+			Error: "temporarily_unavailable",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, introspection)
+}
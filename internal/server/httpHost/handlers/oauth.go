@@ -0,0 +1,525 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/server/httpHost/middlewares"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	OAuth struct {
+		auth    service.IAuth
+		oauth   service.IOAuth
+		totp    service.ITOTP
+		issuer  string
+		baseURL string
+		form    *template.Template
+		device  *template.Template
+		consent *template.Template
+	}
+
+	authorizeForm struct {
+		*model.AuthorizationRequest
+		Action string
+		Error  string
+	}
+
+	consentForm struct {
+		*model.ConsentRequest
+		Action string
+	}
+
+	// deviceForm asks for the user code and the login, then shows the
+	// client, scopes and ip of the device only to the logged in user.
+	deviceForm struct {
+		UserCode   string
+		Ticket     string
+		ClientName string
+		Scopes     []string
+		DeviceIP   string
+		Action     string
+		Error      string
+		Message    string
+	}
+)
+
+func NewOAuth(auth service.IAuth, oauth service.IOAuth, totp service.ITOTP, issuer, baseURL string) *OAuth {
+	return &OAuth{
+		auth:    auth,
+		oauth:   oauth,
+		totp:    totp,
+		issuer:  issuer,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		form:    template.Must(template.New("authorize").Parse(vars.AuthorizeForm)),
+		device:  template.Must(template.New("device").Parse(vars.DeviceForm)),
+		consent: template.Must(template.New("consent").Parse(vars.ConsentForm)),
+	}
+}
+
+// Authorize starts the authorization code flow and shows the login form.
+func (o *OAuth) Authorize(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	r := authorizationRequest(c.Query, c.QueryArray)
+
+	if _, err := o.oauth.ValidateAuthorization(c.Request.Context(), r); err != nil {
+		logger.Err(err).Str("client", r.ClientID).Msg("invalid authorization request")
+		o.authorizeError(c, r, err)
+		return
+	}
+
+	o.renderForm(c, http.StatusOK, r, "")
+}
+
+// AuthorizeLogin checks the password and TOTP code sent by the login form
+// and redirects back to the client with an authorization code.
+func (o *OAuth) AuthorizeLogin(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	r := authorizationRequest(c.PostForm, c.PostFormArray)
+
+	if _, err := o.oauth.ValidateAuthorization(ctx, r); err != nil {
+		logger.Err(err).Str("client", r.ClientID).Msg("invalid authorization request")
+		o.authorizeError(c, r, err)
+		return
+	}
+
+	// ---===Verify password and TOTP code===---
+	email := c.PostForm("email")
+	if status, message := login(c, o.auth, o.totp, email); status != http.StatusOK {
+		o.renderForm(c, status, r, message)
+		return
+	}
+
+	// ---===Ask third-party clients for consent===---
+	consent, err := o.oauth.RequestConsent(ctx, r, email, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		logger.Err(err).Msg("cannot request consent")
+		o.authorizeError(c, r, err)
+		return
+	}
+
+	if consent != nil {
+		render(c, o.consent, http.StatusOK, consentForm{
+			ConsentRequest: consent,
+			Action:         vars.PathOAuthConsent,
+		})
+		return
+	}
+
+	// ---===Issue code===---
+	code, err := o.oauth.IssueAuthorizationCode(ctx, r, email, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		logger.Err(err).Msg("cannot issue authorization code")
+		o.authorizeError(c, r, err)
+		return
+	}
+
+	o.redirect(c, r, url.Values{"code": {code}})
+}
+
+// AuthorizeConsent takes the answer of the consent form and redirects back
+// to the client.
+func (o *OAuth) AuthorizeConsent(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	approved := c.PostForm("decision") == "approve"
+	consent, code, err := o.oauth.DecideConsent(c.Request.Context(), c.PostForm("consent_id"), approved)
+	if err != nil {
+		logger.Err(err).Bool("approved", approved).Msg("consent is not granted")
+
+		if consent == nil {
+			c.JSON(http.StatusBadRequest, model.OAuthError{
+				Error:            "invalid_request",
+				ErrorDescription: "consent request does not exist or expired",
+			})
+			return
+		}
+
+		if errors.Is(err, vars.ErrAccessDenied) {
+			o.redirect(c, consent.Request, url.Values{"error": {"access_denied"}})
+			return
+		}
+
+		o.authorizeError(c, consent.Request, err)
+		return
+	}
+
+	o.redirect(c, consent.Request, url.Values{"code": {code}})
+}
+
+// DeviceAuthorization starts the device flow for a browserless client.
+func (o *OAuth) DeviceAuthorization(c *gin.Context) {
+	client := middlewares.Client(c)
+	logger := log.Log().
+		Str("logID", c.GetString("logID")).
+		Str("client", client.ID)
+
+	response, err := o.oauth.AuthorizeDevice(
+		c.Request.Context(),
+		client,
+		c.PostForm("scope"),
+		append(c.PostFormArray("audience"), c.PostFormArray("resource")...),
+		c.Request.UserAgent(),
+		c.ClientIP(),
+	)
+	if err != nil {
+		logger.Err(err).Msg("cannot authorize device")
+		status, oauthErr := tokenError(err)
+		c.JSON(status, oauthErr)
+		return
+	}
+
+	response.VerificationURI = o.baseURL + vars.PathOAuthDevice
+	response.VerificationURIComplete = response.VerificationURI + "?" + url.Values{
+		"user_code": {response.UserCode},
+	}.Encode()
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// Device shows the form where the user enters the code of a device and
+// logs in. Nothing about the device is shown before the login.
+func (o *OAuth) Device(c *gin.Context) {
+	o.renderDeviceForm(c, http.StatusOK, deviceForm{UserCode: c.Query("user_code")})
+}
+
+// DeviceLogin checks the password and TOTP code sent by the device form and
+// shows the client and scopes of the user code, then records the decision
+// the user sends back with the ticket.
+func (o *OAuth) DeviceLogin(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	form := deviceForm{UserCode: c.PostForm("user_code")}
+
+	// ---===Decide===---
+	if ticket := c.PostForm("ticket"); ticket != "" {
+		approved := c.PostForm("decision") == "approve"
+		da, err := o.oauth.DecideDevice(ctx, form.UserCode, ticket, approved)
+		if err != nil {
+			logger.Err(err).Msg("cannot decide device authorization")
+			o.renderDeviceForm(c, deviceError(&form, err), form)
+			return
+		}
+
+		form.Message = fmt.Sprintf("Access of %s to your account was denied.", da.ClientID)
+		if approved {
+			form.Message = fmt.Sprintf("%s is connected to your account, you can return to your device.", da.ClientID)
+		}
+
+		o.renderDeviceForm(c, http.StatusOK, form)
+		return
+	}
+
+	// ---===Verify password and TOTP code===---
+	email := c.PostForm("email")
+	if status, message := login(c, o.auth, o.totp, email); status != http.StatusOK {
+		form.Error = message
+		o.renderDeviceForm(c, status, form)
+		return
+	}
+
+	// ---===Show what the user decides on===---
+	da, client, err := o.oauth.PendingDevice(ctx, form.UserCode, email, c.ClientIP())
+	if err != nil {
+		logger.Err(err).Msg("cannot get device authorization")
+		o.renderDeviceForm(c, deviceError(&form, err), form)
+		return
+	}
+
+	form.Ticket = da.Ticket
+	form.ClientName = client.Name
+	form.Scopes = strings.Fields(da.Scope)
+	form.DeviceIP = da.IP
+
+	o.renderDeviceForm(c, http.StatusOK, form)
+}
+
+// deviceError sets the message of a failed device step on the form, which
+// then asks for the user code and the login again.
+func deviceError(form *deviceForm, err error) int {
+	switch {
+	case errors.Is(err, vars.ErrUserCodeNotFound):
+		form.Error = "Invalid or expired device code"
+		return http.StatusBadRequest
+	case errors.Is(err, vars.ErrTooManyUserCodes):
+		form.Error = "Too many invalid device codes, please try again later"
+		return http.StatusTooManyRequests
+	default:
+		form.Error = "Unexpected error, please try again"
+		return http.StatusInternalServerError
+	}
+}
+
+func (o *OAuth) Revoke(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, model.OAuthError{
+			Error:            "invalid_request",
+			ErrorDescription: "token is required",
+		})
+		return
+	}
+
+	if err := o.auth.RevokeToken(middlewares.Client(c), token); err != nil {
+		logger.Err(err).Msg("cannot revoke token")
+		c.Header("Retry-After", "5")
+		c.JSON(http.StatusServiceUnavailable, model.OAuthError{
+			Error: "temporarily_unavailable",
+		})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (o *OAuth) Token(c *gin.Context) {
+	ctx := c.Request.Context()
+	client := middlewares.Client(c)
+	logger := log.Log().
+		Str("logID", c.GetString("logID")).
+		Str("client", client.ID)
+
+	var (
+		response *model.TokenResponse
+		err      error
+	)
+
+	switch grantType := c.PostForm("grant_type"); grantType {
+	case vars.GrantTypeAuthorizationCode:
+		response, err = o.oauth.RedeemAuthorizationCode(ctx, client, &model.AuthorizationCodeRequest{
+			Code:         c.PostForm("code"),
+			RedirectURI:  c.PostForm("redirect_uri"),
+			CodeVerifier: c.PostForm("code_verifier"),
+			DPoPJKT:      middlewares.DPoPJKT(c),
+		})
+	case vars.GrantTypeDeviceCode:
+		response, err = o.oauth.RedeemDeviceCode(ctx, client, c.PostForm("device_code"), middlewares.DPoPJKT(c))
+	case vars.GrantTypeClientCredentials:
+		response, err = o.oauth.ClientCredentials(
+			ctx,
+			client,
+			c.PostForm("scope"),
+			append(c.PostFormArray("audience"), c.PostFormArray("resource")...),
+			middlewares.DPoPJKT(c),
+		)
+	case vars.GrantTypeTokenExchange:
+		response, err = o.oauth.ExchangeToken(ctx, client, &model.TokenExchangeRequest{
+			SubjectToken:       c.PostForm("subject_token"),
+			SubjectTokenType:   c.PostForm("subject_token_type"),
+			RequestedTokenType: c.PostForm("requested_token_type"),
+			Audience:           append(c.PostFormArray("audience"), c.PostFormArray("resource")...),
+			Scope:              c.PostForm("scope"),
+		})
+	default:
+		err = fmt.Errorf("%w: %s", vars.ErrUnsupportedGrantType, grantType)
+	}
+
+	if err != nil {
+		logger.Err(err).Msg("cannot issue token")
+		status, oauthErr := tokenError(err)
+		c.JSON(status, oauthErr)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// UserInfo is the OpenID Connect UserInfo endpoint.
+func (o *OAuth) UserInfo(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	info, err := o.oauth.UserInfo(c.Request.Context(), claims.Email, claims.Scope)
+	if err != nil {
+		logger.Err(err).Msg("cannot get user info")
+
+		if errors.Is(err, vars.ErrUserNotFound) || errors.Is(err, vars.ErrUserBanned) {
+			c.Header(vars.HeaderWWWAuthenticate, `Bearer realm="polonium", error="invalid_token"`)
+			c.JSON(http.StatusUnauthorized, model.OAuthError{
+				Error: "invalid_token",
+			})
+			return
+		}
+
+		c.JSON(http.StatusServiceUnavailable, model.OAuthError{
+			Error: "temporarily_unavailable",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, info)
+}
+
+func tokenError(err error) (int, model.OAuthError) {
+	switch {
+	case errors.Is(err, vars.ErrUnsupportedGrantType):
+		return http.StatusBadRequest, model.OAuthError{Error: "unsupported_grant_type"}
+	case errors.Is(err, vars.ErrUnauthorizedClient):
+		return http.StatusBadRequest, model.OAuthError{Error: "unauthorized_client"}
+	case errors.Is(err, vars.ErrInvalidRequest):
+		return http.StatusBadRequest, model.OAuthError{Error: "invalid_request"}
+	case errors.Is(err, vars.ErrInvalidGrant):
+		return http.StatusBadRequest, model.OAuthError{Error: "invalid_grant", ErrorDescription: err.Error()}
+	case errors.Is(err, vars.ErrInvalidScope):
+		return http.StatusBadRequest, model.OAuthError{Error: "invalid_scope"}
+	case errors.Is(err, vars.ErrInvalidAudience):
+		return http.StatusBadRequest, model.OAuthError{Error: "invalid_target"}
+	case errors.Is(err, vars.ErrAuthorizationPending):
+		return http.StatusBadRequest, model.OAuthError{Error: "authorization_pending"}
+	case errors.Is(err, vars.ErrSlowDown):
+		return http.StatusBadRequest, model.OAuthError{Error: "slow_down"}
+	case errors.Is(err, vars.ErrAccessDenied):
+		return http.StatusBadRequest, model.OAuthError{Error: "access_denied"}
+	case errors.Is(err, vars.ErrExpiredToken):
+		return http.StatusBadRequest, model.OAuthError{Error: "expired_token"}
+	}
+
+	return http.StatusServiceUnavailable, model.OAuthError{Error: "temporarily_unavailable"}
+}
+
+// authorizeError sends the error back to the client, unless the client or
+// its redirect uri cannot be trusted (RFC 6749, section 4.1.2.1).
+func (o *OAuth) authorizeError(c *gin.Context, r *model.AuthorizationRequest, err error) {
+	if errors.Is(err, vars.ErrUnknownClient) || errors.Is(err, vars.ErrInvalidRedirectURI) {
+		c.JSON(http.StatusBadRequest, model.OAuthError{
+			Error:            "invalid_request",
+			ErrorDescription: err.Error(),
+		})
+		return
+	}
+
+	params := url.Values{}
+	switch {
+	case errors.Is(err, vars.ErrUnsupportedResponseType):
+		params.Set("error", "unsupported_response_type")
+	case errors.Is(err, vars.ErrUnauthorizedClient):
+		params.Set("error", "unauthorized_client")
+	case errors.Is(err, vars.ErrInvalidRequest):
+		params.Set("error", "invalid_request")
+		params.Set("error_description", err.Error())
+	case errors.Is(err, vars.ErrInvalidScope):
+		params.Set("error", "invalid_scope")
+	case errors.Is(err, vars.ErrInvalidAudience):
+		params.Set("error", "invalid_target")
+	default:
+		params.Set("error", "server_error")
+	}
+
+	o.redirect(c, r, params)
+}
+
+// redirect returns to the validated redirect uri with the state and the
+// issuer (RFC 9207) added to params.
+func (o *OAuth) redirect(c *gin.Context, r *model.AuthorizationRequest, params url.Values) {
+	target, err := url.Parse(r.RedirectURI)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.OAuthError{Error: "invalid_request"})
+		return
+	}
+
+	query := target.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	if r.State != "" {
+		query.Set("state", r.State)
+	}
+	query.Set("iss", o.issuer)
+	target.RawQuery = query.Encode()
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, target.String())
+}
+
+// login verifies the password and TOTP code of a login form. The status is
+// http.StatusOK on success, otherwise the message is shown on the form.
+func login(c *gin.Context, auth service.IAuth, totp service.ITOTP, email string) (int, string) {
+	ctx := c.Request.Context()
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	if err := auth.VerifyUser(ctx, email, c.PostForm("pwd")); err != nil {
+		logger.Err(err).Msg("cannot verify user")
+
+		if errors.Is(err, vars.ErrUserNotFound) || errors.Is(err, vars.ErrIncorrectPwd) {
+			return http.StatusUnauthorized, "Invalid email or password"
+		}
+
+		return http.StatusInternalServerError, "Unexpected error, please try again"
+	}
+
+	codeCorrect, err := totp.IsCodeCorrect(ctx, email, c.PostForm("code"))
+	if err != nil {
+		logger.Err(err).Msg("cannot verify 2FA code")
+		return http.StatusInternalServerError, "Unexpected error, please try again"
+	}
+
+	if !codeCorrect {
+		logger.Msg("code is incorrect")
+		return http.StatusUnauthorized, "Incorrect authenticator code"
+	}
+
+	if err := auth.VerificateUser(ctx, email); err != nil {
+		logger.Err(err).Msg("cannot verificate user")
+		return http.StatusInternalServerError, "Unexpected error, please try again"
+	}
+
+	return http.StatusOK, ""
+}
+
+func (o *OAuth) renderForm(c *gin.Context, status int, r *model.AuthorizationRequest, message string) {
+	render(c, o.form, status, authorizeForm{
+		AuthorizationRequest: r,
+		Action:               vars.PathOAuthAuthorize,
+		Error:                message,
+	})
+}
+
+func (o *OAuth) renderDeviceForm(c *gin.Context, status int, form deviceForm) {
+	form.Action = vars.PathOAuthDevice
+	render(c, o.device, status, form)
+}
+
+func render(c *gin.Context, page *template.Template, status int, data any) {
+	var body bytes.Buffer
+	if err := page.Execute(&body, data); err != nil {
+		log.Log().Str("logID", c.GetString("logID")).Err(err).Msg("cannot render form")
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "frame-ancestors 'none'")
+	c.Data(status, "text/html; charset=utf-8", body.Bytes())
+}
+
+func authorizationRequest(value func(string) string, values func(string) []string) *model.AuthorizationRequest {
+	return &model.AuthorizationRequest{
+		ResponseType:        value("response_type"),
+		ClientID:            value("client_id"),
+		RedirectURI:         value("redirect_uri"),
+		Scope:               value("scope"),
+		Audience:            append(values("audience"), values("resource")...),
+		State:               value("state"),
+		CodeChallenge:       value("code_challenge"),
+		CodeChallengeMethod: value("code_challenge_method"),
+		DPoPJKT:             value("dpop_jkt"),
+		Nonce:               value("nonce"),
+	}
+}
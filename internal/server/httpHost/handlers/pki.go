@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/server/httpHost/middlewares"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	PKI struct {
+		pki service.IPKI
+	}
+)
+
+func NewPKI(pki service.IPKI) *PKI {
+	return &PKI{
+		pki: pki,
+	}
+}
+
+func (p *PKI) CACertificate(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	cert, err := p.pki.CACertificate()
+	if err != nil {
+		logger.Err(err).Msg("cannot get x509 ca certificate")
+		c.String(http.StatusServiceUnavailable, "x509 ca is unavailable")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/x-pem-file", cert)
+}
+
+func (p *PKI) CRL(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	crl, nextUpdate, err := p.pki.CRL(c.Request.Context())
+	if err != nil {
+		logger.Err(err).Msg("cannot build crl")
+		c.String(http.StatusServiceUnavailable, "crl is unavailable")
+		return
+	}
+
+	c.Header("Expires", nextUpdate.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, "application/pkix-crl", crl)
+}
+
+func (p *PKI) CertificateStatus(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	status, err := p.pki.CertificateStatus(c.Request.Context(), c.Param("serial"))
+	if err != nil {
+		logger.Err(err).Msg("cannot get certificate status")
+		c.JSON(http.StatusServiceUnavailable, model.Response{
+			Error: "certificate status is unavailable",
+		})
+		return
+	}
+
+	c.Header("Expires", status.NextUpdate.UTC().Format(http.TimeFormat))
+	c.JSON(http.StatusOK, model.Response{
+		Data: status,
+	})
+}
+
+func (p *PKI) IssueCertificate(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	// ---===Get body===---
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		logger.Err(err).Msg("cannot read body")
+		c.JSON(http.StatusBadRequest, model.Response{
+			Error: "cannot read request body",
+		})
+		return
+	}
+
+	r := new(model.CertificateRequest)
+	if err := easyjson.Unmarshal(body, r); err != nil {
+		logger.Err(err).Msg("cannot unmarshal request")
+		c.JSON(http.StatusBadRequest, model.Response{
+			Error: "wrong request body",
+		})
+		return
+	}
+
+	// ---===Sign certificate request===---
+	cert, err := p.pki.IssueCertificate(ctx, claims.UserID, claims.Deployer, c.Param("deployment"), r.CSR)
+	if err != nil {
+		logger.Err(err).Msg("cannot issue certificate")
+		status, response := pkiError(err)
+		c.JSON(status, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Data: cert,
+	})
+}
+
+func (p *PKI) RevokeCertificates(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	revoked, err := p.pki.RevokeCertificates(
+		c.Request.Context(),
+		claims.UserID,
+		claims.Deployer,
+		c.Param("deployment"),
+		c.Query("reason"),
+	)
+	if err != nil {
+		logger.Err(err).Msg("cannot revoke certificates")
+		status, response := pkiError(err)
+		c.JSON(status, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Data: gin.H{"revoked": revoked},
+	})
+}
+
+func pkiError(err error) (int, model.Response) {
+	switch {
+	case errors.Is(err, vars.ErrInvalidCSR), errors.Is(err, vars.ErrInvalidRevocationReason):
+		return http.StatusBadRequest, model.Response{Error: err.Error()}
+	case errors.Is(err, vars.ErrDeploymentNotFound):
+		return http.StatusNotFound, model.Response{Error: "deployment not found"}
+	case errors.Is(err, vars.ErrDeploymentRevoked):
+		return http.StatusForbidden, model.Response{Error: "deployment is revoked"}
+	}
+
+	return http.StatusInternalServerError, model.Response{Error: "unexpected error"}
+}
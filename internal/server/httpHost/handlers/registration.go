@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/server/httpHost/middlewares"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	Registration struct {
+		registration service.IRegistration
+		baseURL      string
+	}
+)
+
+func NewRegistration(registration service.IRegistration, baseURL string) *Registration {
+	return &Registration{
+		registration: registration,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Register is the client registration endpoint of RFC 7591. Only users
+// logged in with their own token can register clients.
+func (r *Registration) Register(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	if claims.Actor != nil {
+		c.JSON(http.StatusForbidden, model.OAuthError{
+			Error:            "access_denied",
+			ErrorDescription: "delegated tokens cannot register clients",
+		})
+		return
+	}
+
+	metadata, ok := clientMetadata(c)
+	if !ok {
+		return
+	}
+
+	registered, err := r.registration.Register(c.Request.Context(), claims.Email, metadata)
+	if err != nil {
+		logger.Err(err).Msg("cannot register client")
+		registrationError(c, err)
+		return
+	}
+
+	r.respond(c, http.StatusCreated, registered)
+}
+
+// GetRegistration reads the client configuration (RFC 7592, section 2.1).
+func (r *Registration) GetRegistration(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	registered, err := r.registration.Get(c.Request.Context(), c.Param("client_id"), registrationToken(c))
+	if err != nil {
+		logger.Err(err).Msg("cannot get client registration")
+		registrationError(c, err)
+		return
+	}
+
+	r.respond(c, http.StatusOK, registered)
+}
+
+// UpdateRegistration replaces the client metadata (RFC 7592, section 2.2).
+func (r *Registration) UpdateRegistration(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	metadata, ok := clientMetadata(c)
+	if !ok {
+		return
+	}
+
+	registered, err := r.registration.Update(
+		c.Request.Context(),
+		c.Param("client_id"),
+		registrationToken(c),
+		metadata,
+	)
+	if err != nil {
+		logger.Err(err).Msg("cannot update client registration")
+		registrationError(c, err)
+		return
+	}
+
+	r.respond(c, http.StatusOK, registered)
+}
+
+// DeleteRegistration removes the client (RFC 7592, section 2.3).
+func (r *Registration) DeleteRegistration(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	if err := r.registration.Delete(c.Request.Context(), c.Param("client_id"), registrationToken(c)); err != nil {
+		logger.Err(err).Msg("cannot delete client registration")
+		registrationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListClients shows the registered clients in a status, pending by default.
+func (r *Registration) ListClients(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	status := c.DefaultQuery("status", vars.ClientStatusPending)
+	if !slices.Contains([]string{
+		vars.ClientStatusPending,
+		vars.ClientStatusApproved,
+		vars.ClientStatusRejected,
+	}, status) {
+		c.JSON(http.StatusBadRequest, model.Response{
+			Error: "Unknown client status",
+		})
+		return
+	}
+
+	clients, err := r.registration.List(c.Request.Context(), status)
+	if err != nil {
+		logger.Err(err).Msg("cannot list clients")
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	for _, client := range clients {
+		client.RegistrationClientURI = r.clientURI(client.ClientID)
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Data: clients,
+	})
+}
+
+func (r *Registration) ApproveClient(c *gin.Context) {
+	r.review(c, true)
+}
+
+func (r *Registration) RejectClient(c *gin.Context) {
+	r.review(c, false)
+}
+
+func (r *Registration) review(c *gin.Context, approved bool) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	if err := r.registration.Review(c.Request.Context(), c.Param("client_id"), claims.Email, approved); err != nil {
+		logger.Err(err).Msg("cannot review client")
+
+		if errors.Is(err, vars.ErrUnknownClient) {
+			c.JSON(http.StatusNotFound, model.Response{
+				Error: "Client does not exist",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (r *Registration) respond(c *gin.Context, status int, registered *model.ClientRegistration) {
+	registered.RegistrationClientURI = r.clientURI(registered.ClientID)
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, registered)
+}
+
+func (r *Registration) clientURI(id string) string {
+	return r.baseURL + vars.PathOAuthRegister + "/" + id
+}
+
+func clientMetadata(c *gin.Context) (*model.ClientMetadata, bool) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		log.Log().Str("logID", c.GetString("logID")).Err(err).Msg("cannot read body")
+		c.JSON(http.StatusBadRequest, model.OAuthError{
+			Error:            "invalid_client_metadata",
+			ErrorDescription: "cannot read request body",
+		})
+		return nil, false
+	}
+
+	metadata := new(model.ClientMetadata)
+	if err := easyjson.Unmarshal(body, metadata); err != nil {
+		log.Log().Str("logID", c.GetString("logID")).Err(err).Msg("cannot unmarshal request")
+		c.JSON(http.StatusBadRequest, model.OAuthError{
+			Error:            "invalid_client_metadata",
+			ErrorDescription: "wrong request body",
+		})
+		return nil, false
+	}
+
+	return metadata, true
+}
+
+func registrationToken(c *gin.Context) string {
+	header := c.GetHeader(vars.HeaderAuthorization)
+	if len(header) <= len("Bearer ") || !strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return ""
+	}
+
+	return strings.TrimSpace(header[len("Bearer "):])
+}
+
+func registrationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, vars.ErrInvalidRegistrationToken):
+		c.Header(vars.HeaderWWWAuthenticate, `Bearer realm="polonium", error="invalid_token"`)
+		c.JSON(http.StatusUnauthorized, model.OAuthError{Error: "invalid_token"})
+	case errors.Is(err, vars.ErrInvalidClientRedirectURI):
+		c.JSON(http.StatusBadRequest, model.OAuthError{Error: "invalid_redirect_uri", ErrorDescription: err.Error()})
+	case errors.Is(err, vars.ErrInvalidClientMetadata):
+		c.JSON(http.StatusBadRequest, model.OAuthError{Error: "invalid_client_metadata", ErrorDescription: err.Error()})
+	default:
+		c.JSON(http.StatusServiceUnavailable, model.OAuthError{Error: "temporarily_unavailable"})
+	}
+}
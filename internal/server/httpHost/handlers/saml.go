@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	samlBindingRedirect = "redirect"
+	samlBindingPOST     = "post"
+)
+
+type (
+	SAML struct {
+		auth service.IAuth
+		totp service.ITOTP
+		saml service.ISAML
+		form *template.Template
+		post *template.Template
+	}
+
+	samlLoginForm struct {
+		ServiceProvider string
+		SAMLRequest     string
+		RelayState      string
+		Binding         string
+		Action          string
+		Error           string
+	}
+
+	samlPostForm struct {
+		ACSURL       template.URL
+		SAMLResponse string
+		RelayState   string
+	}
+)
+
+func NewSAML(auth service.IAuth, totp service.ITOTP, saml service.ISAML) *SAML {
+	return &SAML{
+		auth: auth,
+		totp: totp,
+		saml: saml,
+		form: template.Must(template.New("saml-login").Parse(vars.SAMLLoginForm)),
+		post: template.Must(template.New("saml-post").Parse(vars.SAMLPostForm)),
+	}
+}
+
+func (s *SAML) Metadata(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	metadata, err := s.saml.Metadata(c.Param("tenant"))
+	if err != nil {
+		logger.Err(err).Msg("cannot get saml metadata")
+
+		if errors.Is(err, vars.ErrUnknownSAMLTenant) {
+			c.JSON(http.StatusNotFound, model.Response{
+				Error: "saml tenant not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+// SSO takes an AuthnRequest with the HTTP-Redirect binding and shows the
+// login form.
+func (s *SAML) SSO(c *gin.Context) {
+	s.sso(c, c.Query("SAMLRequest"), c.Query("RelayState"), samlBindingRedirect)
+}
+
+// SSOPost takes an AuthnRequest with the HTTP-POST binding.
+func (s *SAML) SSOPost(c *gin.Context) {
+	s.sso(c, c.PostForm("SAMLRequest"), c.PostForm("RelayState"), samlBindingPOST)
+}
+
+// Login checks the password and TOTP code sent by the login form and posts
+// the signed response to the service provider.
+func (s *SAML) Login(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	tenant := c.Param("tenant")
+	form := samlLoginForm{
+		SAMLRequest: c.PostForm("SAMLRequest"),
+		RelayState:  c.PostForm("RelayState"),
+		Binding:     c.PostForm("binding"),
+		Action:      fmt.Sprintf(vars.PathSAMLLogin, tenant),
+	}
+	deflated := form.Binding == samlBindingRedirect
+
+	r, err := s.saml.ParseRequest(tenant, form.SAMLRequest, deflated)
+	if err != nil {
+		logger.Err(err).Msg("invalid saml authn request")
+		s.requestError(c, err)
+		return
+	}
+	form.ServiceProvider = r.Issuer
+
+	// ---===Verify password and TOTP code===---
+	email := c.PostForm("email")
+	if status, message := login(c, s.auth, s.totp, email); status != http.StatusOK {
+		form.Error = message
+		render(c, s.form, status, form)
+		return
+	}
+
+	// ---===Issue assertion===---
+	acsURL, response, err := s.saml.Respond(c.Request.Context(), tenant, form.SAMLRequest, deflated, email, c.ClientIP())
+	if err != nil {
+		logger.Err(err).Msg("cannot issue saml assertion")
+
+		if errors.Is(err, vars.ErrUserBanned) {
+			form.Error = "User is banned"
+			render(c, s.form, http.StatusForbidden, form)
+			return
+		}
+
+		s.requestError(c, err)
+		return
+	}
+
+	render(c, s.post, http.StatusOK, samlPostForm{
+		// the url is one registered for the service provider
+		ACSURL:       template.URL(acsURL),
+		SAMLResponse: response,
+		RelayState:   form.RelayState,
+	})
+}
+
+func (s *SAML) sso(c *gin.Context, request, relayState, binding string) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	tenant := c.Param("tenant")
+
+	r, err := s.saml.ParseRequest(tenant, request, binding == samlBindingRedirect)
+	if err != nil {
+		logger.Err(err).Msg("invalid saml authn request")
+		s.requestError(c, err)
+		return
+	}
+
+	render(c, s.form, http.StatusOK, samlLoginForm{
+		ServiceProvider: r.Issuer,
+		SAMLRequest:     request,
+		RelayState:      relayState,
+		Binding:         binding,
+		Action:          fmt.Sprintf(vars.PathSAMLLogin, tenant),
+	})
+}
+
+// requestError answers requests that cannot be sent back to the service
+// provider, because it or its assertion consumer service is unknown.
+func (s *SAML) requestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, vars.ErrUnknownSAMLTenant):
+		c.JSON(http.StatusNotFound, model.Response{
+			Error: "saml tenant not found",
+		})
+	case errors.Is(err, vars.ErrInvalidSAMLRequest),
+		errors.Is(err, vars.ErrUnknownServiceProvider),
+		errors.Is(err, vars.ErrInvalidACSURL):
+		c.JSON(http.StatusBadRequest, model.Response{
+			Error: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Response{
+			Error: "unexpected error",
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/server/httpHost/middlewares"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	SSH struct {
+		ssh service.ISSH
+	}
+)
+
+func NewSSH(ssh service.ISSH) *SSH {
+	return &SSH{
+		ssh: ssh,
+	}
+}
+
+// CAPublicKey is served as plain text so that hosts can fetch it straight
+// into the TrustedUserCAKeys file.
+func (s *SSH) CAPublicKey(c *gin.Context) {
+	logger := log.Log().Str("logID", c.GetString("logID"))
+
+	key, err := s.ssh.CAPublicKey()
+	if err != nil {
+		logger.Err(err).Msg("cannot get ssh ca public key")
+		c.String(http.StatusServiceUnavailable, "ssh ca is unavailable")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.String(http.StatusOK, key)
+}
+
+func (s *SSH) SignCertificate(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := log.Log().Str("logID", c.GetString("logID"))
+	claims := middlewares.Claims(c)
+
+	// ---===Delegated tokens cannot get user certificates===---
+	if claims.Actor != nil {
+		c.JSON(http.StatusForbidden, model.Response{
+			Error: "delegated tokens cannot request ssh certificates",
+		})
+		return
+	}
+
+	// ---===Get body===---
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		logger.Err(err).Msg("cannot read body")
+		c.JSON(http.StatusBadRequest, model.Response{
+			Error: "cannot read request body",
+		})
+		return
+	}
+
+	r := new(model.SSHCertificateRequest)
+	if err := easyjson.Unmarshal(body, r); err != nil {
+		logger.Err(err).Msg("cannot unmarshal request")
+		c.JSON(http.StatusBadRequest, model.Response{
+			Error: "wrong request body",
+		})
+		return
+	}
+
+	// ---===Sign public key===---
+	cert, err := s.ssh.SignUserKey(ctx, claims.Email, r.PublicKey, r.SourceAddress)
+	if err != nil {
+		logger.Err(err).Msg("cannot sign ssh certificate")
+
+		switch {
+		case errors.Is(err, vars.ErrInvalidPublicKey), errors.Is(err, vars.ErrInvalidSourceAddress):
+			c.JSON(http.StatusBadRequest, model.Response{
+				Error: err.Error(),
+			})
+		case errors.Is(err, vars.ErrUserBanned), errors.Is(err, vars.ErrUserNotFound):
+			c.JSON(http.StatusForbidden, model.Response{
+				Error: "user cannot request ssh certificates",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, model.Response{
+				Error: "unexpected error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Data: cert,
+	})
+}
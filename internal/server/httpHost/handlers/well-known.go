@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+type (
+	WellKnown struct {
+		jProcessor *auth.JWTProcessor
+		baseURL    string
+	}
+)
+
+func NewWellKnown(
+	jProcessor *auth.JWTProcessor,
+	baseURL string,
+) *WellKnown {
+	return &WellKnown{
+		jProcessor: jProcessor,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (wk *WellKnown) JWKS(c *gin.Context) {
+	wk.setCacheHeaders(c)
+	c.JSON(http.StatusOK, wk.jProcessor.JWKS())
+}
+
+func (wk *WellKnown) OpenIDConfiguration(c *gin.Context) {
+	wk.setCacheHeaders(c)
+	c.JSON(http.StatusOK, model.OpenIDConfiguration{
+		Issuer:                           wk.jProcessor.Issuer(),
+		JwksURI:                          wk.baseURL + vars.PathJWKS,
+		AuthorizationEndpoint:            wk.baseURL + vars.PathOAuthAuthorize,
+		UserinfoEndpoint:                 wk.baseURL + vars.PathOAuthUserInfo,
+		ScopesSupported:                  []string{vars.ScopeOpenID, vars.ScopeEmail, vars.ScopeProfile, vars.ScopeOfflineAccess, vars.ScopeSSHSign},
+		AcrValuesSupported:               []string{vars.ACRMFA},
+		ResponseTypesSupported:           []string{vars.ResponseTypeCode},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: wk.jProcessor.Algorithms(),
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "iat", "nbf", "jti",
+			"user_id", "email", "deployer", "session", "client_id", "scope", "cnf", "act",
+			"email_verified", "auth_time", "nonce", "amr", "acr", "azp",
+		},
+		RegistrationEndpoint:        wk.baseURL + vars.PathOAuthRegister,
+		DeviceAuthorizationEndpoint: wk.baseURL + vars.PathOAuthDeviceAuthorization,
+		TokenEndpoint:               wk.baseURL + vars.PathOAuthToken,
+		GrantTypesSupported: []string{
+			vars.GrantTypeAuthorizationCode,
+			vars.GrantTypeClientCredentials,
+			vars.GrantTypeDeviceCode,
+			vars.GrantTypeTokenExchange,
+		},
+		TokenEndpointAuthMethodsSupported: []string{
+			vars.AuthMethodClientSecretBasic,
+			vars.AuthMethodClientSecretPost,
+			vars.AuthMethodNone,
+		},
+		CodeChallengeMethodsSupported: []string{vars.CodeChallengeMethodS256},
+		RevocationEndpoint:            wk.baseURL + vars.PathOAuthRevoke,
+		RevocationAuthMethodsSupported: []string{
+			vars.AuthMethodClientSecretBasic,
+			vars.AuthMethodClientSecretPost,
+			vars.AuthMethodNone,
+		},
+		DPoPSigningAlgValuesSupported: auth.DPoPAlgorithms,
+	})
+}
+
+func (wk *WellKnown) setCacheHeaders(c *gin.Context) {
+	c.Header("Cache-Control", fmt.Sprintf(
+		"public, max-age=%d, must-revalidate",
+		int(wk.jProcessor.JWKSCacheTTL().Seconds()),
+	))
+}
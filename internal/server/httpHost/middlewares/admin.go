@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// AdminMW must run after AuthMW. It only lets through users listed in
+// APP_AUTH_ADMINS, with their own tokens rather than delegated ones.
+func AdminMW(admins []string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		claims := Claims(context)
+
+		if claims.Actor != nil || !slices.Contains(admins, claims.Email) {
+			log.Log().
+				Str("logID", context.GetString("logID")).
+				Str("user", claims.UserID).
+				Msg("user is not an admin")
+			context.AbortWithStatusJSON(http.StatusForbidden, model.Response{
+				Error: "Admin access required",
+			})
+			return
+		}
+
+		context.Next()
+	}
+}
@@ -2,7 +2,9 @@ package middlewares
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -10,69 +12,182 @@ import (
 	"github.com/mxmrykov/polonium-auth/internal/model"
 	"github.com/mxmrykov/polonium-auth/internal/repository"
 	"github.com/mxmrykov/polonium-auth/internal/vars"
-	"github.com/mxmrykov/polonium-auth/pkg/utils"
 	"github.com/rs/zerolog/log"
 )
 
-func AuthMW(jp *auth.JWTProcessor, authRdb repository.IAuthRedis) gin.HandlerFunc {
+const (
+	bearerScheme = "Bearer"
+	dpopScheme   = "DPoP"
+)
+
+// AuthMW accepts access tokens issued for the audience that carry every
+// required scope. Tokens bound to a DPoP key must come with a valid proof.
+func AuthMW(
+	jp *auth.JWTProcessor,
+	authRdb repository.IAuthRedis,
+	dpop *auth.DPoPVerifier,
+	audience string,
+	scopes ...string,
+) gin.HandlerFunc {
 	return func(context *gin.Context) {
-		refresh, err := context.Request.Cookie(vars.CookiePoloniumAuth)
-		if err != nil {
-			log.Log().Msg("no polonium-auth token provided")
+		scheme, token, ok := accessToken(context.Request.Header.Get(vars.HeaderAuthorization))
+		if !ok {
+			log.Log().Str("logID", context.GetString("logID")).Msg("no access token provided")
+			context.Header(vars.HeaderWWWAuthenticate, `Bearer realm="polonium"`)
 			context.AbortWithStatusJSON(http.StatusUnauthorized, model.Response{
-				Error: "No polonium-auth token provided",
+				Error: "No access token provided",
 			})
 			return
 		}
 
-		claims, err := jp.TokenVerify(refresh.Value)
+		claims, err := jp.TokenVerify(token, audience, scopes...)
+		if err == nil && claims.Subject != "access" {
+			err = errors.New("not an access token")
+		}
+
 		if err != nil {
-			log.Log().Msg("cannot verify refresh token")
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				context.AbortWithStatusJSON(http.StatusUnauthorized, model.Response{
-					Error: "Session expired",
+			log.Log().Str("logID", context.GetString("logID")).Err(err).Msg("cannot verify access token")
+
+			if errors.Is(err, vars.ErrRevocationCheckFailed) {
+				context.AbortWithStatusJSON(http.StatusServiceUnavailable, model.Response{
+					Error: "Cannot check token",
 				})
 				return
 			}
 
-			context.AbortWithStatusJSON(http.StatusUnauthorized, model.Response{
-				Error: "Invalid token",
-			})
+			if errors.Is(err, vars.ErrInsufficientScope) {
+				context.Header(vars.HeaderWWWAuthenticate, fmt.Sprintf(
+					`%s realm="polonium", error="insufficient_scope", scope="%s"`,
+					scheme, strings.Join(scopes, " "),
+				))
+				context.AbortWithStatusJSON(http.StatusForbidden, model.Response{
+					Error: "Insufficient scope",
+				})
+				return
+			}
+
+			description := "Invalid access token"
+			switch {
+			case errors.Is(err, jwt.ErrTokenExpired):
+				description = "Access token expired"
+			case errors.Is(err, vars.ErrTokenRevoked):
+				description = "Access token revoked"
+			}
+
+			unauthorized(context, scheme, "invalid_token", description)
 			return
 		}
 
-		access := context.Request.Header.Get(vars.HeaderAuthorization)
-		if _, err = jp.TokenVerify(access); err != nil {
-			log.Log().Msg("cannot verify access token")
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				log.Log().Msg("renewing access token")
-				session := utils.NewSession()
-				if err := authRdb.NewAuthSession(claims.UserID, session); err != nil {
-					context.AbortWithStatusJSON(http.StatusServiceUnavailable, model.Response{
-						Error: "Cannot renew token",
-					})
-					return
-				}
-
-				newAccess, err := jp.GenerateAccessToken(claims.UserID, session)
-				if err != nil {
-					context.AbortWithStatusJSON(http.StatusServiceUnavailable, model.Response{
-						Error: "Cannot renew token",
-					})
-					return
-				}
-
-				context.JSON(http.StatusResetContent, model.Response{
-					Data:    newAccess,
-					Message: "Cannot renew token",
+		if claims.Confirmation != nil || scheme == dpopScheme {
+			if claims.Confirmation == nil || scheme != dpopScheme {
+				log.Log().Str("logID", context.GetString("logID")).Msg("token binding does not match scheme")
+				unauthorized(context, scheme, "invalid_token", "Token binding does not match authorization scheme")
+				return
+			}
+
+			if !checkDPoPProof(context, dpop, token, claims.Confirmation.JKT) {
+				return
+			}
+		}
+
+		session, err := authRdb.GetAuthSession(claims.Session)
+		if err != nil || session.User != claims.UserID {
+			log.Log().Str("logID", context.GetString("logID")).Err(err).Msg("session is not active")
+
+			if err != nil && !errors.Is(err, vars.ErrSessionNotFound) {
+				context.AbortWithStatusJSON(http.StatusServiceUnavailable, model.Response{
+					Error: "Cannot check session",
 				})
 				return
 			}
 
-			context.AbortWithStatusJSON(http.StatusUnauthorized, model.Response{
-				Error: "Invalid access token",
-			})
+			unauthorized(context, scheme, "invalid_token", "Session revoked")
 			return
 		}
+
+		context.Set(vars.ContextClaims, claims)
+		context.Next()
+	}
+}
+
+func Claims(context *gin.Context) *auth.CustomClaims {
+	claims, _ := context.MustGet(vars.ContextClaims).(*auth.CustomClaims)
+	return claims
+}
+
+func checkDPoPProof(context *gin.Context, dpop *auth.DPoPVerifier, token, jkt string) bool {
+	logger := log.Log().Str("logID", context.GetString("logID"))
+
+	proofs := context.Request.Header.Values(vars.HeaderDPoP)
+	if len(proofs) != 1 {
+		logger.Int("proofs", len(proofs)).Msg("exactly one dpop proof is required")
+		unauthorized(context, dpopScheme, "invalid_dpop_proof", "Exactly one DPoP proof is required")
+		return false
+	}
+
+	proofJKT, err := dpop.Verify(proofs[0], context.Request.Method, context.Request.URL.Path, token)
+	if err == nil && proofJKT != jkt {
+		err = vars.ErrDPoPKeyMismatch
+	}
+
+	if err != nil {
+		logger.Err(err).Msg("cannot verify dpop proof")
+
+		switch {
+		case errors.Is(err, vars.ErrUseDPoPNonce):
+			if !setDPoPNonce(context, dpop) {
+				return false
+			}
+			unauthorized(context, dpopScheme, "use_dpop_nonce", "Resource server requires nonce in DPoP proof")
+		case errors.Is(err, vars.ErrInvalidDPoPProof),
+			errors.Is(err, vars.ErrDPoPProofReplayed),
+			errors.Is(err, vars.ErrDPoPKeyMismatch):
+			unauthorized(context, dpopScheme, "invalid_dpop_proof", "Invalid DPoP proof")
+		default:
+			context.AbortWithStatusJSON(http.StatusServiceUnavailable, model.Response{
+				Error: "Cannot check DPoP proof",
+			})
+		}
+
+		return false
 	}
+
+	return true
+}
+
+func setDPoPNonce(context *gin.Context, dpop *auth.DPoPVerifier) bool {
+	nonce, err := dpop.NewNonce()
+	if err != nil {
+		log.Log().Str("logID", context.GetString("logID")).Err(err).Msg("cannot issue dpop nonce")
+		context.AbortWithStatusJSON(http.StatusServiceUnavailable, model.Response{
+			Error: "Cannot issue DPoP nonce",
+		})
+		return false
+	}
+
+	context.Header(vars.HeaderDPoPNonce, nonce)
+	return true
+}
+
+func accessToken(header string) (string, string, bool) {
+	for _, scheme := range []string{bearerScheme, dpopScheme} {
+		prefix := scheme + " "
+		if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+			return scheme, strings.TrimSpace(header[len(prefix):]), true
+		}
+	}
+
+	return "", "", false
+}
+
+func unauthorized(context *gin.Context, scheme, code, description string) {
+	challenge := fmt.Sprintf(`%s realm="polonium", error="%s", error_description="%s"`, scheme, code, description)
+	if scheme == dpopScheme {
+		challenge += fmt.Sprintf(`, algs="%s"`, strings.Join(auth.DPoPAlgorithms, " "))
+	}
+
+	context.Header(vars.HeaderWWWAuthenticate, challenge)
+	context.AbortWithStatusJSON(http.StatusUnauthorized, model.Response{
+		Error: description,
+	})
 }
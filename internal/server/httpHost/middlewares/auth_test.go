@@ -0,0 +1,185 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/config"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+func TestAuthMW(t *testing.T) {
+	keys := newTestKeys(t)
+	sessions := &fakeSessions{
+		sessions: map[string]*model.Session{
+			"session": {ID: "session", User: "user-id"},
+			"foreign": {ID: "foreign", User: "other-user"},
+		},
+		revoked: map[string]bool{},
+	}
+	jp := auth.NewJWTProcessor(keys, newTestFormat(t, keys), sessions, time.Minute, time.Hour, "issuer")
+	expired := auth.NewJWTProcessor(keys, newTestFormat(t, keys), sessions, -time.Minute, time.Hour, "issuer")
+
+	access, err := jp.GenerateAccessToken(testUser, &model.Session{ID: "session", Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refresh, err := jp.GenerateRefreshToken(testUser, &model.Session{ID: "session"}, "jti")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err := expired.GenerateAccessToken(testUser, &model.Session{ID: "session", Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err := jp.GenerateAccessToken(testUser, &model.Session{ID: "revoked", Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	foreign, err := jp.GenerateAccessToken(testUser, &model.Session{ID: "foreign", Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	denied, err := jp.GenerateAccessToken(testUser, &model.Session{ID: "session", Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherAudience, err := jp.GenerateAccessToken(testUser, &model.Session{ID: "session", Audience: []string{"billing"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bound, err := jp.GenerateAccessToken(testUser, &model.Session{ID: "session", Audience: []string{"api"}, DPoPJKT: "jkt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := jp.TokenVerify(denied, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions.revoked[claims.ID] = true
+
+	tests := []struct {
+		name        string
+		header      string
+		status      int
+		description string
+	}{
+		{name: "valid access token", header: "Bearer " + access, status: http.StatusOK},
+		{name: "lower case scheme", header: "bearer " + access, status: http.StatusOK},
+		{name: "no token", status: http.StatusUnauthorized},
+		{name: "other scheme", header: "Basic " + access, status: http.StatusUnauthorized},
+		{name: "refresh token", header: "Bearer " + refresh, status: http.StatusUnauthorized, description: "Invalid access token"},
+		{name: "expired token is not renewed", header: "Bearer " + stale, status: http.StatusUnauthorized, description: "Access token expired"},
+		{name: "garbage", header: "Bearer garbage", status: http.StatusUnauthorized, description: "Invalid access token"},
+		{name: "other audience", header: "Bearer " + otherAudience, status: http.StatusUnauthorized, description: "Invalid access token"},
+		{name: "dpop bound token as bearer", header: "Bearer " + bound, status: http.StatusUnauthorized, description: "Token binding does not match authorization scheme"},
+		{name: "revoked token", header: "Bearer " + denied, status: http.StatusUnauthorized, description: "Access token revoked"},
+		{name: "revoked session", header: "Bearer " + revoked, status: http.StatusUnauthorized, description: "Session revoked"},
+		{name: "session of another user", header: "Bearer " + foreign, status: http.StatusUnauthorized, description: "Session revoked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(AuthMW(jp, sessions, nil, "api"), tt.header)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			challenge := rec.Header().Get("WWW-Authenticate")
+			if tt.status == http.StatusUnauthorized && !strings.HasPrefix(challenge, "Bearer ") {
+				t.Errorf("no bearer challenge: %q", challenge)
+			}
+
+			if tt.description != "" && !strings.Contains(challenge, `error_description="`+tt.description+`"`) {
+				t.Errorf("challenge = %q, want description %q", challenge, tt.description)
+			}
+		})
+	}
+}
+
+func TestAuthMWDenylistDown(t *testing.T) {
+	sessions := &fakeSessions{sessions: map[string]*model.Session{"session": {ID: "session", User: "user-id"}}}
+	keys := newTestKeys(t)
+	jp := auth.NewJWTProcessor(keys, newTestFormat(t, keys), sessions, time.Minute, time.Hour, "issuer")
+
+	access, err := jp.GenerateAccessToken(testUser, &model.Session{ID: "session", Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions.err = errors.New("redis is down")
+	if rec := serve(AuthMW(jp, sessions, nil, "api"), "Bearer "+access); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestAuthMWAfterRefreshReuse(t *testing.T) {
+	sessions := &fakeSessions{sessions: map[string]*model.Session{}, families: map[string]*model.RefreshFamily{}}
+	keys := newTestKeys(t)
+	jp := auth.NewJWTProcessor(keys, newTestFormat(t, keys), sessions, time.Minute, time.Hour, "issuer")
+	clients := service.NewClients([]config.Client{{ID: "web", Audiences: []string{"api"}}}, fakeUsers{})
+	a := service.NewAuth(fakeUsers{}, sessions, nil, nil, clients, jp)
+	ctx := context.Background()
+
+	_, refresh, err := a.CreateSession(ctx, testUser.Email, &model.Session{Client: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	access, _, err := a.RefreshSession(ctx, refresh, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := serve(AuthMW(jp, sessions, nil, "api"), "Bearer "+access); rec.Code != http.StatusOK {
+		t.Fatalf("access token before reuse: status %d", rec.Code)
+	}
+
+	if _, _, err := a.RefreshSession(ctx, refresh, ""); !errors.Is(err, vars.ErrRefreshTokenReused) {
+		t.Fatalf("reused refresh token: got %v", err)
+	}
+
+	if rec := serve(AuthMW(jp, sessions, nil, "api"), "Bearer "+access); rec.Code != http.StatusUnauthorized {
+		t.Errorf("access token after reuse: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthMWScopes(t *testing.T) {
+	sessions := &fakeSessions{sessions: map[string]*model.Session{"session": {ID: "session", User: testUser.Id}}}
+	keys := newTestKeys(t)
+	jp := auth.NewJWTProcessor(keys, newTestFormat(t, keys), sessions, time.Minute, time.Hour, "issuer")
+
+	access, err := jp.GenerateAccessToken(testUser, &model.Session{ID: "session", Audience: []string{"api"}, Scope: "profile read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := serve(AuthMW(jp, sessions, nil, "api", "read"), "Bearer "+access); rec.Code != http.StatusOK {
+		t.Errorf("granted scope: status %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec := serve(AuthMW(jp, sessions, nil, "api", "read", "write"), "Bearer "+access)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("missing scope: status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	if challenge := rec.Header().Get("WWW-Authenticate"); !strings.Contains(challenge, `error="insufficient_scope"`) {
+		t.Errorf("challenge = %q, want insufficient_scope", challenge)
+	}
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog/log"
+)
+
+// ClientAuthMW authenticates a confidential client with HTTP Basic or
+// client_secret_post credentials and requires it to hold scope.
+func ClientAuthMW(clients service.IClients, scope string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		logger := log.Log().Str("logID", context.GetString("logID"))
+
+		id, secret, ok := context.Request.BasicAuth()
+		if !ok {
+			id, secret = context.PostForm("client_id"), context.PostForm("client_secret")
+		}
+
+		client, err := clients.Authenticate(context.Request.Context(), id, secret)
+		if err != nil {
+			logger.Err(err).Str("client", id).Msg("cannot authenticate client")
+
+			if !errors.Is(err, vars.ErrInvalidClient) {
+				context.AbortWithStatusJSON(http.StatusServiceUnavailable, model.OAuthError{
+					Error: "temporarily_unavailable",
+				})
+				return
+			}
+
+			context.Header(vars.HeaderWWWAuthenticate, `Basic realm="polonium"`)
+			context.AbortWithStatusJSON(http.StatusUnauthorized, model.OAuthError{
+				Error: "invalid_client",
+			})
+			return
+		}
+
+		if scope != "" && !slices.Contains(client.Scopes, scope) {
+			logger.Str("client", id).Msg("client is not allowed to call endpoint")
+			context.AbortWithStatusJSON(http.StatusForbidden, model.OAuthError{
+				Error:            "insufficient_scope",
+				ErrorDescription: "client is missing scope " + scope,
+			})
+			return
+		}
+
+		context.Set(vars.ContextClient, client)
+		context.Next()
+	}
+}
+
+// TokenClientAuthMW is ClientAuthMW for the token endpoints, where public
+// clients that have no secret identify with their client_id only.
+func TokenClientAuthMW(clients service.IClients) gin.HandlerFunc {
+	confidential := ClientAuthMW(clients, "")
+
+	return func(context *gin.Context) {
+		_, _, basic := context.Request.BasicAuth()
+		if basic || context.PostForm("client_secret") != "" {
+			confidential(context)
+			return
+		}
+
+		id := context.PostForm("client_id")
+		client, err := clients.Get(context.Request.Context(), id)
+		if err != nil || client.SecretHash != "" {
+			log.Log().
+				Str("logID", context.GetString("logID")).
+				Str("client", id).
+				Msg("client is not a public client")
+			context.AbortWithStatusJSON(http.StatusUnauthorized, model.OAuthError{
+				Error: "invalid_client",
+			})
+			return
+		}
+
+		context.Set(vars.ContextClient, client)
+		context.Next()
+	}
+}
+
+func Client(context *gin.Context) *model.Client {
+	client, _ := context.MustGet(vars.ContextClient).(*model.Client)
+	return client
+}
@@ -0,0 +1,128 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/config"
+	"github.com/mxmrykov/polonium-auth/internal/service"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/mxmrykov/polonium-auth/pkg/utils"
+)
+
+func TestClientAuthMW(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := utils.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clients := service.NewClients([]config.Client{
+		{ID: "gateway", SecretHash: hash, Scopes: []string{vars.ScopeIntrospect}},
+		{ID: "other", SecretHash: hash},
+	}, fakeUsers{})
+
+	router := gin.New()
+	router.POST("/", ClientAuthMW(clients, vars.ScopeIntrospect), func(c *gin.Context) {
+		c.String(http.StatusOK, Client(c).ID)
+	})
+
+	tests := []struct {
+		name   string
+		basic  []string
+		form   url.Values
+		status int
+	}{
+		{name: "basic", basic: []string{"gateway", "secret"}, status: http.StatusOK},
+		{name: "client_secret_post", form: url.Values{"client_id": {"gateway"}, "client_secret": {"secret"}}, status: http.StatusOK},
+		{name: "wrong secret", basic: []string{"gateway", "wrong"}, status: http.StatusUnauthorized},
+		{name: "unknown client", basic: []string{"unknown", "secret"}, status: http.StatusUnauthorized},
+		{name: "no credentials", status: http.StatusUnauthorized},
+		{name: "missing scope", basic: []string{"other", "secret"}, status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.basic != nil {
+				req.SetBasicAuth(tt.basic[0], tt.basic[1])
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+
+			if tt.status == http.StatusOK && rec.Body.String() != "gateway" {
+				t.Errorf("client = %q, want gateway", rec.Body)
+			}
+
+			if tt.status == http.StatusUnauthorized && rec.Header().Get(vars.HeaderWWWAuthenticate) == "" {
+				t.Error("no WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestTokenClientAuthMW(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := utils.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clients := service.NewClients([]config.Client{
+		{ID: "web"},
+		{ID: "gateway", SecretHash: hash},
+	}, fakeUsers{})
+
+	router := gin.New()
+	router.POST("/", TokenClientAuthMW(clients), func(c *gin.Context) {
+		c.String(http.StatusOK, Client(c).ID)
+	})
+
+	tests := []struct {
+		name   string
+		basic  []string
+		form   url.Values
+		status int
+		client string
+	}{
+		{name: "public client", form: url.Values{"client_id": {"web"}}, status: http.StatusOK, client: "web"},
+		{name: "confidential client", basic: []string{"gateway", "secret"}, status: http.StatusOK, client: "gateway"},
+		{name: "confidential client without secret", form: url.Values{"client_id": {"gateway"}}, status: http.StatusUnauthorized},
+		{name: "confidential client with wrong secret", form: url.Values{"client_id": {"gateway"}, "client_secret": {"wrong"}}, status: http.StatusUnauthorized},
+		{name: "unknown client", form: url.Values{"client_id": {"unknown"}}, status: http.StatusUnauthorized},
+		{name: "no client", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.basic != nil {
+				req.SetBasicAuth(tt.basic[0], tt.basic[1])
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+
+			if tt.client != "" && rec.Body.String() != tt.client {
+				t.Errorf("client = %q, want %q", rec.Body, tt.client)
+			}
+		})
+	}
+}
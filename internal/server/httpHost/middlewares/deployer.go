@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// DeployerMW must run after AuthMW. It only lets through requests whose
+// deployer path parameter matches the deployer claim of the access token.
+func DeployerMW(param string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		claims := Claims(context)
+
+		if claims.Deployer == "" || context.Param(param) != claims.Deployer {
+			log.Log().
+				Str("logID", context.GetString("logID")).
+				Str("user", claims.UserID).
+				Str("deployer", context.Param(param)).
+				Msg("deployer does not match token")
+			context.AbortWithStatusJSON(http.StatusForbidden, model.Response{
+				Error: "Access to deployer denied",
+			})
+			return
+		}
+
+		context.Next()
+	}
+}
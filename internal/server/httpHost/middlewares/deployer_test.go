@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+)
+
+func TestDeployerMW(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sessions := &fakeSessions{sessions: map[string]*model.Session{"session": {ID: "session", User: testUser.Id}}}
+	keys := newTestKeys(t)
+	jp := auth.NewJWTProcessor(keys, newTestFormat(t, keys), sessions, time.Minute, time.Hour, "issuer")
+
+	access, err := jp.GenerateAccessToken(testUser, &model.Session{ID: "session", Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noDeployer, err := jp.GenerateAccessToken(&model.User{Id: testUser.Id}, &model.Session{ID: "session", Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.GET("/deployers/:deployer", AuthMW(jp, sessions, nil, "api"), DeployerMW("deployer"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		token  string
+		path   string
+		status int
+	}{
+		{name: "own deployer", token: access, path: "/deployers/deployer", status: http.StatusOK},
+		{name: "other deployer", token: access, path: "/deployers/other", status: http.StatusForbidden},
+		{name: "no deployer claim", token: noDeployer, path: "/deployers/deployer", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
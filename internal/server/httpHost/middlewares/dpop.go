@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog/log"
+)
+
+// DPoPMW checks an optional DPoP proof on endpoints that issue tokens. The
+// thumbprint of a valid proof key is available through DPoPJKT.
+func DPoPMW(dpop *auth.DPoPVerifier) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		logger := log.Log().Str("logID", context.GetString("logID"))
+
+		proofs := context.Request.Header.Values(vars.HeaderDPoP)
+		if len(proofs) == 0 {
+			context.Next()
+			return
+		}
+
+		if len(proofs) > 1 {
+			logger.Int("proofs", len(proofs)).Msg("more than one dpop proof provided")
+			context.AbortWithStatusJSON(http.StatusBadRequest, model.Response{
+				Error: "invalid_dpop_proof",
+			})
+			return
+		}
+
+		jkt, err := dpop.Verify(proofs[0], context.Request.Method, context.Request.URL.Path, "")
+		if err != nil {
+			logger.Err(err).Msg("cannot verify dpop proof")
+
+			switch {
+			case errors.Is(err, vars.ErrUseDPoPNonce):
+				if setDPoPNonce(context, dpop) {
+					context.AbortWithStatusJSON(http.StatusBadRequest, model.Response{
+						Error: "use_dpop_nonce",
+					})
+				}
+			case errors.Is(err, vars.ErrInvalidDPoPProof), errors.Is(err, vars.ErrDPoPProofReplayed):
+				context.AbortWithStatusJSON(http.StatusBadRequest, model.Response{
+					Error: "invalid_dpop_proof",
+				})
+			default:
+				context.AbortWithStatusJSON(http.StatusServiceUnavailable, model.Response{
+					Error: "cannot check dpop proof",
+				})
+			}
+			return
+		}
+
+		context.Set(vars.ContextDPoPJKT, jkt)
+		context.Next()
+	}
+}
+
+func DPoPJKT(context *gin.Context) string {
+	return context.GetString(vars.ContextDPoPJKT)
+}
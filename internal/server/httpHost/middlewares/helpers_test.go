@@ -0,0 +1,166 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+// testUser is the only user fakeUsers knows.
+var testUser = &model.User{Id: "user-id", Email: "user@example.com", Deployer: "deployer"}
+
+type (
+	// testKeys is a KeyProvider with a single Ed25519 key.
+	testKeys struct {
+		key *auth.Key
+	}
+
+	// fakeSessions is an IAuthRedis that only knows sessions, their
+	// refresh token families and revoked tokens.
+	fakeSessions struct {
+		repository.IAuthRedis
+
+		sessions map[string]*model.Session
+		families map[string]*model.RefreshFamily
+		revoked  map[string]bool
+		err      error
+	}
+
+	// fakeUsers is an IAuthPostgres that only knows testUser and no
+	// registered clients.
+	fakeUsers struct {
+		repository.IAuthPostgres
+	}
+)
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testKeys{key: &auth.Key{Kid: "kid", Method: jwt.SigningMethodEdDSA, Private: private, Public: public}}
+}
+
+func (k *testKeys) Algorithm() string              { return jwt.SigningMethodEdDSA.Alg() }
+func (k *testKeys) SigningKey() (*auth.Key, error) { return k.key, nil }
+func (k *testKeys) PublicKeys() []jose.JSONWebKey  { return nil }
+func (k *testKeys) CacheTTL() time.Duration        { return time.Minute }
+
+func (k *testKeys) VerificationKey(kid string) (*auth.Key, error) {
+	if kid != k.key.Kid {
+		return nil, errors.New("unknown key")
+	}
+	return k.key, nil
+}
+
+func (fakeUsers) GetUser(_ context.Context, email string) (*model.User, error) {
+	if email != testUser.Email {
+		return nil, vars.ErrUserNotFound
+	}
+
+	user := *testUser
+	return &user, nil
+}
+
+func (fakeUsers) GetClient(context.Context, string) (*model.Client, error) {
+	return nil, vars.ErrUnknownClient
+}
+
+func (f *fakeSessions) GetAuthSession(session string) (*model.Session, error) {
+	s, ok := f.sessions[session]
+	if !ok {
+		return nil, vars.ErrSessionNotFound
+	}
+
+	return s, nil
+}
+
+func (f *fakeSessions) NewAuthSession(session *model.Session, _ time.Duration) error {
+	stored := *session
+	f.sessions[session.ID] = &stored
+	return nil
+}
+
+func (f *fakeSessions) DropAuthSession(_, session string) error {
+	delete(f.sessions, session)
+	delete(f.families, session)
+	return nil
+}
+
+func (f *fakeSessions) NewRefreshFamily(session string, family *model.RefreshFamily, _ time.Duration) error {
+	stored := *family
+	f.families[session] = &stored
+	return nil
+}
+
+func (f *fakeSessions) RotateRefreshToken(session, presented, next string, _ time.Duration) (*model.RefreshFamily, error) {
+	family, ok := f.families[session]
+	if !ok {
+		return nil, vars.ErrRefreshFamilyNotFound
+	}
+
+	if family.Current != presented {
+		reused := *family
+		return &reused, vars.ErrRefreshTokenReused
+	}
+
+	family.Current = next
+	rotated := *family
+	return &rotated, nil
+}
+
+func (f *fakeSessions) RevokeRefreshFamily(session string, _ *model.RefreshFamily, _ time.Duration) error {
+	f.families[session].Revoked = true
+	return nil
+}
+
+func (f *fakeSessions) IsTokenRevoked(jti string) (bool, error) {
+	return f.revoked[jti], f.err
+}
+
+// serve runs the middleware in front of a handler that answers 200.
+func serve(mw gin.HandlerFunc, header string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/", mw, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// newTestFormat returns the JWT format over keys.
+func newTestFormat(t *testing.T, keys auth.KeyProvider) auth.TokenFormat {
+	t.Helper()
+
+	format, err := auth.NewTokenFormat(auth.TokenFormatJWT, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return format
+}
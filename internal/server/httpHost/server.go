@@ -17,16 +17,16 @@ type (
 	}
 
 	Server struct {
-		cfg    *config.PAuth
+		cfg    *config.Server
 		server *http.Server
 		router *gin.Engine
 	}
 )
 
-func New(cfg *config.PAuth, TLS ...*tls.Config) IServer {
+func New(cfg *config.Server, TLS ...*tls.Config) IServer {
 	router := gin.New()
 	server := &http.Server{
-		Addr:    cfg.PublicServer.Port,
+		Addr:    cfg.Port,
 		Handler: router,
 	}
 
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
@@ -12,6 +14,7 @@ import (
 	"github.com/mxmrykov/polonium-auth/pkg/utils"
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
+	"github.com/rs/zerolog"
 )
 
 type (
@@ -21,7 +24,14 @@ type (
 		ConfirmCode(user, code string) error
 		SignupUnverified(ctx context.Context, user string, pwd string) error
 		VerifyUser(ctx context.Context, user, pwd string) error
-		CreateSession(user string) (string, string, error)
+		CreateSession(ctx context.Context, email string, session *model.Session) (string, string, error)
+		RefreshSession(ctx context.Context, refresh, dpopJKT string) (string, string, error)
+		ListSessions(user, current string) ([]model.ActiveSession, error)
+		RevokeSession(user, session string) error
+		Logout(user, session string) error
+		LogoutAll(user string) error
+		RevokeToken(client *model.Client, token string) error
+		Introspect(token string) (*model.IntrospectionResponse, error)
 		VerificateUser(ctx context.Context, user string) error
 	}
 
@@ -30,6 +40,7 @@ type (
 		authRdb    repository.IAuthRedis
 		emailer    repository.IEmailer
 		vault      repository.IAuthVault
+		clients    IClients
 		jProcessor *jwtAuth.JWTProcessor
 	}
 )
@@ -39,6 +50,7 @@ func NewAuth(
 	authRdb repository.IAuthRedis,
 	emailer repository.IEmailer,
 	vault repository.IAuthVault,
+	clients IClients,
 	jProcessor *jwtAuth.JWTProcessor,
 ) IAuth {
 	return &auth{
@@ -46,6 +58,7 @@ func NewAuth(
 		authRdb:    authRdb,
 		emailer:    emailer,
 		vault:      vault,
+		clients:    clients,
 		jProcessor: jProcessor,
 	}
 }
@@ -173,18 +186,108 @@ func (a *auth) VerifyUser(ctx context.Context, user, pwd string) error {
 	return nil
 }
 
-func (a *auth) CreateSession(user string) (string, string, error) {
+// CreateSession starts a session for the client in s.Client. The requested
+// s.Scope and s.Audience are narrowed to what the client is allowed.
+func (a *auth) CreateSession(ctx context.Context, email string, s *model.Session) (string, string, error) {
+	client, err := a.clients.Get(ctx, s.Client)
+	if err != nil {
+		return "", "", err
+	}
+
+	if s.Scope, s.Audience, err = narrowGrant(client, s.Scope, s.Audience); err != nil {
+		return "", "", err
+	}
+
+	user, err := a.loadUser(ctx, email)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
 	session := utils.NewSession()
-	if err := a.authRdb.NewAuthSession(user, session); err != nil {
+	s.ID, s.User, s.CreatedAt, s.LastUsedAt = session, user.Id, now, now
+
+	if err := a.authRdb.NewAuthSession(s, a.jProcessor.RefreshTTL()); err != nil {
 		return "", "", fmt.Errorf("cannot register new session: %v", err)
 	}
 
+	newAccess, err := a.jProcessor.GenerateAccessToken(user, s)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot generate access token: %v", err)
+	}
+
+	jti := utils.NewTokenID()
+	newRefresh, err := a.jProcessor.GenerateRefreshToken(user, s, jti)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot generate refresh token: %v", err)
+	}
+
+	if err := a.authRdb.NewRefreshFamily(session, &model.RefreshFamily{
+		User:    user.Id,
+		Current: jti,
+	}, a.jProcessor.RefreshTTL()); err != nil {
+		return "", "", fmt.Errorf("cannot register refresh token family: %v", err)
+	}
+
+	return newAccess, newRefresh, nil
+}
+
+// RefreshSession rotates the refresh token. A session bound to a DPoP key
+// may only be refreshed with a proof signed by that key.
+func (a *auth) RefreshSession(ctx context.Context, refresh, dpopJKT string) (string, string, error) {
+	claims, err := a.jProcessor.TokenVerify(refresh, a.jProcessor.Issuer())
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", vars.ErrInvalidRefreshToken, err)
+	}
+
+	if claims.Subject != "refresh" || claims.ID == "" {
+		return "", "", vars.ErrInvalidRefreshToken
+	}
+
+	session, err := a.authRdb.GetAuthSession(claims.Session)
+	if err != nil {
+		return "", "", err
+	}
+
+	if session.User != claims.UserID {
+		return "", "", vars.ErrSessionNotFound
+	}
+
+	if session.DPoPJKT != "" && session.DPoPJKT != dpopJKT {
+		return "", "", vars.ErrDPoPKeyMismatch
+	}
+
+	jti := utils.NewTokenID()
+	family, err := a.authRdb.RotateRefreshToken(claims.Session, claims.ID, jti, a.jProcessor.RefreshTTL())
+	if err != nil {
+		if errors.Is(err, vars.ErrRefreshTokenReused) {
+			return "", "", a.revokeRefreshFamily(claims, family)
+		}
+
+		return "", "", err
+	}
+
+	// reload the user so that a changed deployer or a ban applies
+	user, err := a.loadUser(ctx, claims.Email)
+	if err != nil {
+		return "", "", err
+	}
+
+	if user.Id != family.User {
+		return "", "", vars.ErrSessionNotFound
+	}
+
+	session.LastUsedAt = time.Now()
+	if err := a.authRdb.NewAuthSession(session, a.jProcessor.RefreshTTL()); err != nil {
+		return "", "", fmt.Errorf("cannot prolong session: %v", err)
+	}
+
 	newAccess, err := a.jProcessor.GenerateAccessToken(user, session)
 	if err != nil {
 		return "", "", fmt.Errorf("cannot generate access token: %v", err)
 	}
 
-	newRefresh, err := a.jProcessor.GenerateRefreshToken(user, session)
+	newRefresh, err := a.jProcessor.GenerateRefreshToken(user, session, jti)
 	if err != nil {
 		return "", "", fmt.Errorf("cannot generate refresh token: %v", err)
 	}
@@ -192,6 +295,212 @@ func (a *auth) CreateSession(user string) (string, string, error) {
 	return newAccess, newRefresh, nil
 }
 
+// revokeRefreshFamily ends the session of a reused refresh token, so that
+// none of its refresh or access tokens are accepted any more.
+func (a *auth) revokeRefreshFamily(claims *jwtAuth.CustomClaims, family *model.RefreshFamily) error {
+	authEvent(zerolog.WarnLevel, vars.EventRefreshTokenReuse, family.User).
+		Str("session", claims.Session).
+		Str("jti", claims.ID).
+		Msg("refresh token reused")
+
+	if err := a.authRdb.RevokeRefreshFamily(claims.Session, family, a.jProcessor.RefreshTTL()); err != nil {
+		return fmt.Errorf("cannot revoke refresh family: %v", err)
+	}
+
+	if err := a.authRdb.DropAuthSession(family.User, claims.Session); err != nil {
+		return fmt.Errorf("cannot drop session: %v", err)
+	}
+
+	authEvent(zerolog.WarnLevel, vars.EventRefreshFamilyRevoked, family.User).
+		Str("session", claims.Session).
+		Msg("refresh token family revoked")
+
+	return vars.ErrRefreshTokenReused
+}
+
+func (a *auth) ListSessions(user, current string) ([]model.ActiveSession, error) {
+	sessions, err := a.authRdb.ListAuthSessions(user)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list sessions: %v", err)
+	}
+
+	active := make([]model.ActiveSession, 0, len(sessions))
+	for _, s := range sessions {
+		active = append(active, model.ActiveSession{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			MFA:        s.MFA,
+			Client:     s.Client,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			Current:    s.ID == current,
+		})
+	}
+
+	return active, nil
+}
+
+func (a *auth) RevokeSession(user, session string) error {
+	s, err := a.authRdb.GetAuthSession(session)
+	if err != nil {
+		return err
+	}
+
+	if s.User != user {
+		return vars.ErrSessionNotFound
+	}
+
+	if err := a.authRdb.DropAuthSession(user, session); err != nil {
+		return fmt.Errorf("cannot drop session: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventSessionRevoked, user).
+		Str("session", session).
+		Msg("session revoked")
+
+	return nil
+}
+
+func (a *auth) Logout(user, session string) error {
+	if err := a.authRdb.DropAuthSession(user, session); err != nil {
+		return fmt.Errorf("cannot drop session: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventLogout, user).
+		Str("session", session).
+		Msg("user logged out")
+
+	return nil
+}
+
+func (a *auth) LogoutAll(user string) error {
+	sessions, err := a.authRdb.DropAuthSessions(user)
+	if err != nil {
+		return fmt.Errorf("cannot drop sessions: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventLogoutAll, user).
+		Strs("sessions", sessions).
+		Msg("user logged out everywhere")
+
+	return nil
+}
+
+func (a *auth) loadUser(ctx context.Context, email string) (*model.User, error) {
+	user, err := a.authPg.GetUser(ctx, email)
+	if err != nil {
+		if errors.Is(err, vars.ErrUserNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("cannot get user from db: %v", err)
+	}
+
+	if user.Banned {
+		return nil, vars.ErrUserBanned
+	}
+
+	return user, nil
+}
+
 func (a *auth) VerificateUser(ctx context.Context, user string) error {
 	return a.authPg.VerificateUser(ctx, user)
 }
+
+// RevokeToken denies the token until its natural expiry. Revoking a refresh
+// token also ends its session. Invalid tokens and tokens issued to another
+// client are ignored as RFC 7009 asks.
+func (a *auth) RevokeToken(client *model.Client, token string) error {
+	claims, err := a.jProcessor.TokenVerify(token, "")
+	if err != nil {
+		if errors.Is(err, vars.ErrRevocationCheckFailed) {
+			return err
+		}
+
+		return nil
+	}
+
+	if claims.ClientID != client.ID {
+		return nil
+	}
+
+	if claims.ID != "" {
+		if err := a.authRdb.RevokeToken(claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+			return fmt.Errorf("cannot revoke token: %v", err)
+		}
+	}
+
+	if claims.Subject == "refresh" {
+		if err := a.authRdb.DropAuthSession(claims.UserID, claims.Session); err != nil {
+			return fmt.Errorf("cannot drop session: %v", err)
+		}
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventTokenRevoked, claims.UserID).
+		Str("session", claims.Session).
+		Str("jti", claims.ID).
+		Str("type", claims.TokenType()).
+		Msg("token revoked")
+
+	return nil
+}
+
+func (a *auth) Introspect(token string) (*model.IntrospectionResponse, error) {
+	claims, err := a.jProcessor.TokenVerify(token, "")
+	if err != nil {
+		if errors.Is(err, vars.ErrRevocationCheckFailed) {
+			return nil, err
+		}
+
+		return &model.IntrospectionResponse{
+			Revoked: errors.Is(err, vars.ErrTokenRevoked),
+		}, nil
+	}
+
+	if claims.Session != "" {
+		session, err := a.authRdb.GetAuthSession(claims.Session)
+		if err != nil && !errors.Is(err, vars.ErrSessionNotFound) {
+			return nil, fmt.Errorf("cannot get session: %v", err)
+		}
+
+		if err != nil || session.User != claims.UserID {
+			return &model.IntrospectionResponse{Revoked: true}, nil
+		}
+	}
+
+	// a refresh token stays valid only while it is the current one of a
+	// family that has not been revoked
+	if claims.Subject == "refresh" {
+		family, err := a.authRdb.GetRefreshFamily(claims.Session)
+		if err != nil && !errors.Is(err, vars.ErrRefreshFamilyNotFound) {
+			return nil, fmt.Errorf("cannot get refresh family: %v", err)
+		}
+
+		if err != nil || family.Revoked || family.Current != claims.ID {
+			return &model.IntrospectionResponse{Revoked: true}, nil
+		}
+	}
+
+	sub := claims.UserID
+	if claims.IsClientToken() {
+		sub = claims.Subject
+	}
+
+	return &model.IntrospectionResponse{
+		Active:    true,
+		Sub:       sub,
+		Session:   claims.Session,
+		Deployer:  claims.Deployer,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Aud:       claims.Audience,
+		Cnf:       claims.Confirmation,
+		Act:       claims.Actor,
+		TokenType: claims.TokenType(),
+		Iss:       claims.Issuer,
+		Jti:       claims.ID,
+		Exp:       claims.ExpiresAt.Unix(),
+		Iat:       claims.IssuedAt.Unix(),
+	}, nil
+}
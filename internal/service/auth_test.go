@@ -0,0 +1,448 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// captureLog collects what the service logs during the test.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	logger := log.Logger
+	log.Logger = zerolog.New(buf)
+	t.Cleanup(func() { log.Logger = logger })
+
+	return buf
+}
+
+func newTestAuth(t *testing.T, rdb *fakeRedis) *auth {
+	t.Helper()
+
+	keys := newTestKeys(t)
+	return &auth{
+		authPg:     newFakePostgres(),
+		authRdb:    rdb,
+		clients:    newTestClients(),
+		jProcessor: jwtAuth.NewJWTProcessor(keys, newTestFormat(t, keys), rdb, time.Minute, time.Hour, "issuer"),
+	}
+}
+
+// login starts a session for user@example.com and returns its id and tokens.
+func login(t *testing.T, a *auth) (string, string, string) {
+	t.Helper()
+
+	s := &model.Session{UserAgent: "test", Client: "web"}
+	access, refresh, err := a.CreateSession(context.Background(), "user@example.com", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s.ID, access, refresh
+}
+
+func TestRefreshSessionRotates(t *testing.T) {
+	a := newTestAuth(t, newFakeRedis())
+	_, _, refresh := login(t, a)
+
+	_, next, err := a.RefreshSession(context.Background(), refresh, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next == refresh {
+		t.Fatal("refresh token was not rotated")
+	}
+
+	if _, _, err := a.RefreshSession(context.Background(), next, ""); err != nil {
+		t.Errorf("rotated refresh token: %v", err)
+	}
+}
+
+func TestRefreshSessionReuse(t *testing.T) {
+	logs := captureLog(t)
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	session, _, refresh := login(t, a)
+
+	_, next, err := a.RefreshSession(context.Background(), refresh, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := a.RefreshSession(context.Background(), refresh, ""); !errors.Is(err, vars.ErrRefreshTokenReused) {
+		t.Fatalf("reused refresh token: got %v, want %v", err, vars.ErrRefreshTokenReused)
+	}
+
+	if family, ok := rdb.families[session]; ok && !family.Revoked {
+		t.Error("refresh family is still active")
+	}
+
+	if _, ok := rdb.sessions[session]; ok {
+		t.Error("session of the reused token is still active")
+	}
+
+	if _, _, err := a.RefreshSession(context.Background(), next, ""); !errors.Is(err, vars.ErrSessionNotFound) {
+		t.Errorf("current refresh token after reuse: got %v, want %v", err, vars.ErrSessionNotFound)
+	}
+
+	for _, event := range []string{vars.EventRefreshTokenReuse, vars.EventRefreshFamilyRevoked} {
+		if !strings.Contains(logs.String(), event) {
+			t.Errorf("%s was not logged", event)
+		}
+	}
+}
+
+func TestRefreshSessionReuseLoggedWhenRevocationFails(t *testing.T) {
+	logs := captureLog(t)
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	_, _, refresh := login(t, a)
+
+	if _, _, err := a.RefreshSession(context.Background(), refresh, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	rdb.revokeErr = errors.New("redis is down")
+	if _, _, err := a.RefreshSession(context.Background(), refresh, ""); err == nil || errors.Is(err, vars.ErrRefreshTokenReused) {
+		t.Fatalf("failed revocation: got %v", err)
+	}
+
+	if !strings.Contains(logs.String(), vars.EventRefreshTokenReuse) {
+		t.Error("reuse was not logged")
+	}
+
+	if strings.Contains(logs.String(), vars.EventRefreshFamilyRevoked) {
+		t.Error("revocation was logged although it failed")
+	}
+}
+
+func TestRefreshSessionRejects(t *testing.T) {
+	a := newTestAuth(t, newFakeRedis())
+	session, access, refresh := login(t, a)
+
+	if _, _, err := a.RefreshSession(context.Background(), access, ""); !errors.Is(err, vars.ErrInvalidRefreshToken) {
+		t.Errorf("access token: got %v, want %v", err, vars.ErrInvalidRefreshToken)
+	}
+
+	if err := a.RevokeSession("user-id", session); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := a.RefreshSession(context.Background(), refresh, ""); !errors.Is(err, vars.ErrSessionNotFound) {
+		t.Errorf("revoked session: got %v, want %v", err, vars.ErrSessionNotFound)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	current, _, _ := login(t, a)
+	other, _, _ := login(t, a)
+
+	if err := rdb.NewAuthSession(&model.Session{ID: "foreign", User: "other-user"}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := a.ListSessions("user-id", current)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sessions) != 2 {
+		t.Fatalf("listed %d sessions, want 2", len(sessions))
+	}
+
+	for _, s := range sessions {
+		if s.Current != (s.ID == current) || s.UserAgent != "test" {
+			t.Errorf("unexpected session: %+v", s)
+		}
+	}
+
+	// a session of another user is reported as missing and kept
+	if err := a.RevokeSession("user-id", "foreign"); !errors.Is(err, vars.ErrSessionNotFound) {
+		t.Errorf("foreign session: got %v, want %v", err, vars.ErrSessionNotFound)
+	}
+
+	if _, ok := rdb.sessions["foreign"]; !ok {
+		t.Error("foreign session was dropped")
+	}
+
+	if err := a.RevokeSession("user-id", other); err != nil {
+		t.Fatal(err)
+	}
+
+	if sessions, _ := a.ListSessions("user-id", current); len(sessions) != 1 || sessions[0].ID != current {
+		t.Errorf("sessions after revocation: %+v", sessions)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	logs := captureLog(t)
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	current, _, refresh := login(t, a)
+	other, _, _ := login(t, a)
+
+	if err := a.Logout("user-id", current); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := a.RefreshSession(context.Background(), refresh, ""); !errors.Is(err, vars.ErrSessionNotFound) {
+		t.Errorf("refresh after logout: got %v, want %v", err, vars.ErrSessionNotFound)
+	}
+
+	if _, ok := rdb.sessions[other]; !ok {
+		t.Error("logout ended another session")
+	}
+
+	if !strings.Contains(logs.String(), `"event":"`+vars.EventLogout+`"`) {
+		t.Error("logout was not logged")
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	logs := captureLog(t)
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	_, _, refresh := login(t, a)
+	login(t, a)
+
+	if err := rdb.NewAuthSession(&model.Session{ID: "foreign", User: "other-user"}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.LogoutAll("user-id"); err != nil {
+		t.Fatal(err)
+	}
+
+	if sessions, _ := a.ListSessions("user-id", ""); len(sessions) != 0 {
+		t.Errorf("%d sessions left after logout everywhere", len(sessions))
+	}
+
+	if _, _, err := a.RefreshSession(context.Background(), refresh, ""); !errors.Is(err, vars.ErrSessionNotFound) {
+		t.Errorf("refresh after logout everywhere: got %v, want %v", err, vars.ErrSessionNotFound)
+	}
+
+	if _, ok := rdb.sessions["foreign"]; !ok {
+		t.Error("logout everywhere ended a session of another user")
+	}
+
+	if !strings.Contains(logs.String(), `"event":"`+vars.EventLogoutAll+`"`) {
+		t.Error("logout everywhere was not logged")
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	session, access, refresh := login(t, a)
+	other, otherAccess, _ := login(t, a)
+
+	web, err := a.clients.Get(context.Background(), "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	foreign, err := a.clients.Get(context.Background(), "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tokens of another client are ignored
+	for _, token := range []string{otherAccess, refresh} {
+		if err := a.RevokeToken(foreign, token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := a.jProcessor.TokenVerify(otherAccess, ""); err != nil {
+		t.Errorf("access token revoked by another client: %v", err)
+	}
+
+	if _, ok := rdb.sessions[session]; !ok {
+		t.Error("refresh token revoked by another client ended its session")
+	}
+
+	if err := a.RevokeToken(web, otherAccess); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.jProcessor.TokenVerify(otherAccess, ""); !errors.Is(err, vars.ErrTokenRevoked) {
+		t.Errorf("revoked access token: got %v, want %v", err, vars.ErrTokenRevoked)
+	}
+
+	// revoking an access token leaves its session alone
+	if _, ok := rdb.sessions[other]; !ok {
+		t.Error("revoking an access token ended its session")
+	}
+
+	if err := a.RevokeToken(web, refresh); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := rdb.sessions[session]; ok {
+		t.Error("revoking a refresh token kept its session")
+	}
+
+	if _, _, err := a.RefreshSession(context.Background(), refresh, ""); err == nil {
+		t.Error("revoked refresh token was accepted")
+	}
+
+	if _, err := a.jProcessor.TokenVerify(access, ""); err != nil {
+		t.Errorf("access token of another request: %v", err)
+	}
+
+	for _, token := range []string{"", "garbage", otherAccess} {
+		if err := a.RevokeToken(web, token); err != nil {
+			t.Errorf("invalid token %q: %v", token, err)
+		}
+	}
+}
+
+func TestIntrospect(t *testing.T) {
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	_, access, refresh := login(t, a)
+
+	for name, token := range map[string]string{"access": access, "refresh": refresh} {
+		resp, err := a.Introspect(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !resp.Active || resp.Sub != "user-id" || resp.TokenType != name {
+			t.Errorf("%s token: got %+v, want active token of user-id", name, resp)
+		}
+	}
+
+	if _, _, err := a.RefreshSession(context.Background(), refresh, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := a.Introspect(refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Active || !resp.Revoked {
+		t.Errorf("rotated refresh token: got %+v, want revoked", resp)
+	}
+
+	resp, err = a.Introspect("garbage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Active || resp.Revoked {
+		t.Errorf("invalid token: got %+v, want inactive", resp)
+	}
+}
+
+func TestIntrospectRevokedFamily(t *testing.T) {
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	session, access, refresh := login(t, a)
+
+	// the session outlives its family only if the family write raced the
+	// session drop, which must not bring the refresh token back
+	rdb.families[session].Revoked = true
+
+	resp, err := a.Introspect(refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Active || !resp.Revoked {
+		t.Errorf("refresh token of a revoked family: got %+v, want revoked", resp)
+	}
+
+	if resp, err := a.Introspect(access); err != nil || !resp.Active {
+		t.Errorf("access token: got %+v, %v, want active", resp, err)
+	}
+
+	delete(rdb.families, session)
+	if resp, err := a.Introspect(refresh); err != nil || resp.Active {
+		t.Errorf("refresh token without family: got %+v, %v, want inactive", resp, err)
+	}
+}
+
+func TestSessionDeployerClaim(t *testing.T) {
+	a := newTestAuth(t, newFakeRedis())
+	pg := a.authPg.(*fakePostgres)
+	_, access, refresh := login(t, a)
+
+	claims, err := a.jProcessor.TokenVerify(access, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.UserID != "user-id" || claims.Email != "user@example.com" || claims.Deployer != "deployer" {
+		t.Errorf("unexpected access token claims: %+v", claims)
+	}
+
+	// a refresh picks up the deployer the user has now
+	pg.users["user@example.com"].Deployer = "moved"
+
+	access, refresh, err = a.RefreshSession(context.Background(), refresh, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims, err = a.jProcessor.TokenVerify(access, ""); err != nil || claims.Deployer != "moved" {
+		t.Errorf("refreshed access token: got %+v, %v, want deployer moved", claims, err)
+	}
+
+	pg.users["user@example.com"].Banned = true
+	if _, _, err := a.RefreshSession(context.Background(), refresh, ""); !errors.Is(err, vars.ErrUserBanned) {
+		t.Errorf("banned user refresh: got %v, want %v", err, vars.ErrUserBanned)
+	}
+
+	if _, _, err := a.CreateSession(context.Background(), "user@example.com", &model.Session{Client: "web"}); !errors.Is(err, vars.ErrUserBanned) {
+		t.Errorf("banned user login: got %v, want %v", err, vars.ErrUserBanned)
+	}
+
+	if _, _, err := a.CreateSession(context.Background(), "unknown@example.com", &model.Session{Client: "web"}); !errors.Is(err, vars.ErrUserNotFound) {
+		t.Errorf("unknown user login: got %v, want %v", err, vars.ErrUserNotFound)
+	}
+}
+
+func TestRefreshSessionDPoPBinding(t *testing.T) {
+	a := newTestAuth(t, newFakeRedis())
+
+	_, refresh, err := a.CreateSession(context.Background(), "user@example.com", &model.Session{Client: "web", DPoPJKT: "jkt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, jkt := range []string{"", "other"} {
+		if _, _, err := a.RefreshSession(context.Background(), refresh, jkt); !errors.Is(err, vars.ErrDPoPKeyMismatch) {
+			t.Errorf("proof key %q: got %v, want %v", jkt, err, vars.ErrDPoPKeyMismatch)
+		}
+	}
+
+	access, _, err := a.RefreshSession(context.Background(), refresh, "jkt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := a.jProcessor.TokenVerify(access, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Confirmation == nil || claims.Confirmation.JKT != "jkt" {
+		t.Errorf("refreshed access token is not bound: %+v", claims.Confirmation)
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mxmrykov/polonium-auth/internal/config"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/mxmrykov/polonium-auth/pkg/utils"
+)
+
+type (
+	IClients interface {
+		Get(ctx context.Context, id string) (*model.Client, error)
+		Authenticate(ctx context.Context, id, secret string) (*model.Client, error)
+	}
+
+	// clients looks up the clients configured in the environment first and
+	// the oauth_clients table after.
+	clients struct {
+		authPg repository.IAuthPostgres
+		byID   map[string]*model.Client
+	}
+)
+
+func NewClients(cfg []config.Client, authPg repository.IAuthPostgres) IClients {
+	byID := make(map[string]*model.Client, len(cfg))
+	for _, c := range cfg {
+		byID[c.ID] = &model.Client{
+			ID:           c.ID,
+			Name:         c.ID,
+			SecretHash:   c.SecretHash,
+			Scopes:       c.Scopes,
+			Audiences:    c.Audiences,
+			GrantTypes:   c.GrantTypes,
+			RedirectURIs: c.RedirectURIs,
+		}
+	}
+
+	return &clients{
+		authPg: authPg,
+		byID:   byID,
+	}
+}
+
+func (c *clients) Get(ctx context.Context, id string) (*model.Client, error) {
+	if client, ok := c.byID[id]; ok {
+		return client, nil
+	}
+
+	if id == "" {
+		return nil, vars.ErrUnknownClient
+	}
+
+	client, err := c.authPg.GetClient(ctx, id)
+	if err != nil {
+		if errors.Is(err, vars.ErrUnknownClient) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("cannot get client from db: %v", err)
+	}
+
+	return client, nil
+}
+
+func (c *clients) Authenticate(ctx context.Context, id, secret string) (*model.Client, error) {
+	client, err := c.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, vars.ErrUnknownClient) {
+			return nil, vars.ErrInvalidClient
+		}
+
+		return nil, err
+	}
+
+	if client.SecretHash == "" || !utils.CheckHash(secret, client.SecretHash) {
+		return nil, vars.ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+// narrowGrant keeps the requested scopes and audiences the client is allowed
+// to ask for. An empty request means everything the client is allowed.
+func narrowGrant(client *model.Client, scope string, audience []string) (string, []string, error) {
+	scopes := client.Scopes
+	if requested := strings.Fields(scope); len(requested) > 0 {
+		scopes = intersect(requested, client.Scopes)
+		if len(scopes) == 0 {
+			return "", nil, vars.ErrInvalidScope
+		}
+	}
+
+	audiences := client.Audiences
+	if len(audience) > 0 {
+		audiences = intersect(audience, client.Audiences)
+		if len(audiences) == 0 {
+			return "", nil, vars.ErrInvalidAudience
+		}
+	}
+
+	if len(audiences) == 0 {
+		return "", nil, vars.ErrInvalidAudience
+	}
+
+	return strings.Join(scopes, " "), audiences, nil
+}
+
+func intersect(requested, allowed []string) []string {
+	result := make([]string, 0, len(requested))
+	for _, r := range requested {
+		if slices.Contains(allowed, r) && !slices.Contains(result, r) {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
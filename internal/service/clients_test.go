@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+func TestNarrowGrant(t *testing.T) {
+	client := &model.Client{
+		ID:        "web",
+		Scopes:    []string{"openid", "profile", "email"},
+		Audiences: []string{"api", "billing"},
+	}
+
+	tests := []struct {
+		name      string
+		client    *model.Client
+		scope     string
+		audience  []string
+		wantScope string
+		wantAud   []string
+		err       error
+	}{
+		{name: "everything allowed", client: client, wantScope: "openid profile email", wantAud: []string{"api", "billing"}},
+		{name: "narrowed", client: client, scope: "email openid", audience: []string{"billing"}, wantScope: "email openid", wantAud: []string{"billing"}},
+		{name: "unknown scopes dropped", client: client, scope: "profile admin profile", wantScope: "profile", wantAud: []string{"api", "billing"}},
+		{name: "unknown audiences dropped", client: client, audience: []string{"api", "vault"}, wantScope: "openid profile email", wantAud: []string{"api"}},
+		{name: "only unknown scopes", client: client, scope: "admin", err: vars.ErrInvalidScope},
+		{name: "only unknown audiences", client: client, audience: []string{"vault"}, err: vars.ErrInvalidAudience},
+		{name: "client without audience", client: &model.Client{ID: "bare", Scopes: []string{"openid"}}, err: vars.ErrInvalidAudience},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, audience, err := narrowGrant(tt.client, tt.scope, tt.audience)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+
+			if scope != tt.wantScope || !slices.Equal(audience, tt.wantAud) {
+				t.Errorf("got %q %v, want %q %v", scope, audience, tt.wantScope, tt.wantAud)
+			}
+		})
+	}
+}
+
+func TestCreateSessionNarrowsGrant(t *testing.T) {
+	a := newTestAuth(t, newFakeRedis())
+
+	s := &model.Session{Client: "web", Scope: "profile admin", Audience: []string{"billing"}}
+	access, _, err := a.CreateSession(t.Context(), "user@example.com", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := a.jProcessor.TokenVerify(access, "billing", "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.ClientID != "web" || claims.Scope != "profile" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	if _, err := a.jProcessor.TokenVerify(access, "api"); err == nil {
+		t.Error("token accepted for an audience it was not issued for")
+	}
+
+	if _, err := a.jProcessor.TokenVerify(access, "billing", "admin"); !errors.Is(err, vars.ErrInsufficientScope) {
+		t.Errorf("scope outside the grant: got %v, want %v", err, vars.ErrInsufficientScope)
+	}
+
+	if _, _, err := a.CreateSession(t.Context(), "user@example.com", &model.Session{Client: "unknown"}); !errors.Is(err, vars.ErrUnknownClient) {
+		t.Errorf("unknown client: got %v, want %v", err, vars.ErrUnknownClient)
+	}
+}
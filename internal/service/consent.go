@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog"
+)
+
+type (
+	IConsent interface {
+		List(ctx context.Context, user string) ([]model.Consent, error)
+		Revoke(ctx context.Context, user, client string) error
+	}
+
+	consent struct {
+		authPg  repository.IAuthPostgres
+		authRdb repository.IAuthRedis
+	}
+)
+
+func NewConsent(authPg repository.IAuthPostgres, authRdb repository.IAuthRedis) IConsent {
+	return &consent{
+		authPg:  authPg,
+		authRdb: authRdb,
+	}
+}
+
+func (c *consent) List(ctx context.Context, user string) ([]model.Consent, error) {
+	consents, err := c.authPg.ListConsents(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list consents: %v", err)
+	}
+
+	return consents, nil
+}
+
+// Revoke removes the consent and the sessions of the user with the client,
+// so that its refresh tokens stop working right away.
+func (c *consent) Revoke(ctx context.Context, user, client string) error {
+	if err := c.authPg.DeleteConsent(ctx, user, client); err != nil {
+		if errors.Is(err, vars.ErrConsentNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("cannot delete consent: %v", err)
+	}
+
+	sessions, err := c.authRdb.ListAuthSessions(user)
+	if err != nil {
+		return fmt.Errorf("cannot list sessions: %v", err)
+	}
+
+	var revoked []string
+	for _, s := range sessions {
+		if s.Client != client {
+			continue
+		}
+
+		if err := c.authRdb.DropAuthSession(user, s.ID); err != nil {
+			return fmt.Errorf("cannot drop session: %v", err)
+		}
+
+		revoked = append(revoked, s.ID)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventConsentRevoked, user).
+		Str("client", client).
+		Strs("sessions", revoked).
+		Msg("consent revoked")
+
+	return nil
+}
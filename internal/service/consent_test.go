@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+type (
+	noDenylist struct{}
+)
+
+var (
+	testUser = &model.User{Email: "user@polonium.ws", Id: "user-id"}
+
+	thirdParty = &model.Client{
+		ID:           "third-party",
+		Name:         "Third party app",
+		SecretHash:   "hash",
+		Scopes:       []string{"openid", "profile", "email"},
+		Audiences:    []string{"third-party"},
+		GrantTypes:   []string{vars.GrantTypeAuthorizationCode, vars.GrantTypeDeviceCode, vars.GrantTypeTokenExchange},
+		RedirectURIs: []string{"https://app.example/callback"},
+		ThirdParty:   true,
+	}
+
+	firstParty = &model.Client{
+		ID:         "service",
+		Name:       "service",
+		SecretHash: "hash",
+		Scopes:     []string{"openid", "profile", "email"},
+		Audiences:  []string{"api"},
+		GrantTypes: []string{vars.GrantTypeDeviceCode, vars.GrantTypeTokenExchange},
+	}
+)
+
+func (noDenylist) IsTokenRevoked(string) (bool, error) { return false, nil }
+
+func newTestJWTProcessor(t *testing.T) *jwtAuth.JWTProcessor {
+	t.Helper()
+
+	keys := newTestKeys(t)
+	return jwtAuth.NewJWTProcessor(keys, newTestFormat(t, keys), noDenylist{}, time.Minute, time.Hour, "https://polonium.ws")
+}
+
+func TestRequestConsent(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   *model.Client
+		scope    string
+		granted  []string
+		want     bool
+		wantAsks []string
+	}{
+		{name: "first-party client", client: testClient, scope: "openid email"},
+		{name: "no consent yet", client: thirdParty, scope: "openid email", want: true, wantAsks: []string{"openid", "email"}},
+		{name: "every scope granted", client: thirdParty, scope: "openid email", granted: []string{"openid", "email", "profile"}},
+		{name: "new scope", client: thirdParty, scope: "openid email", granted: []string{"openid"}, want: true, wantAsks: []string{"openid", "email"}},
+		{name: "all client scopes", client: thirdParty, granted: []string{"openid", "email"}, want: true, wantAsks: thirdParty.Scopes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authPg, rdb := newFakePostgres(testUser), newFakeRedis()
+			if tt.granted != nil {
+				authPg.consents[testUser.Id+"/"+thirdParty.ID] = tt.granted
+			}
+
+			o := newTestOAuth(fakeClients{testClient.ID: testClient, thirdParty.ID: thirdParty}, rdb)
+			o.authPg = authPg
+
+			cr, err := o.RequestConsent(context.Background(), &model.AuthorizationRequest{
+				ResponseType:        vars.ResponseTypeCode,
+				ClientID:            tt.client.ID,
+				RedirectURI:         tt.client.RedirectURIs[0],
+				Scope:               tt.scope,
+				CodeChallenge:       testChallenge,
+				CodeChallengeMethod: vars.CodeChallengeMethodS256,
+			}, testUser.Email, "agent", "127.0.0.1")
+			if err != nil {
+				t.Fatalf("RequestConsent() error = %v", err)
+			}
+
+			if (cr != nil) != tt.want {
+				t.Fatalf("RequestConsent() = %+v, want a consent request %t", cr, tt.want)
+			}
+
+			if cr != nil && (!slices.Equal(cr.Scopes, tt.wantAsks) || rdb.consents[cr.ID] != cr || cr.ClientName != thirdParty.Name) {
+				t.Errorf("consent request = %+v, want scopes %v", cr, tt.wantAsks)
+			}
+		})
+	}
+}
+
+func TestDecideConsent(t *testing.T) {
+	tests := []struct {
+		name     string
+		approved bool
+		wantErr  error
+	}{
+		{name: "approved", approved: true},
+		{name: "denied", wantErr: vars.ErrAccessDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authPg, rdb := newFakePostgres(testUser), newFakeRedis()
+			rdb.consents["consent"] = &model.ConsentRequest{
+				ID: "consent",
+				Request: &model.AuthorizationRequest{
+					ResponseType:        vars.ResponseTypeCode,
+					ClientID:            thirdParty.ID,
+					RedirectURI:         thirdParty.RedirectURIs[0],
+					Scope:               "openid email",
+					CodeChallenge:       testChallenge,
+					CodeChallengeMethod: vars.CodeChallengeMethodS256,
+				},
+				Scopes: []string{"openid", "email"},
+				UserID: testUser.Id,
+				Email:  testUser.Email,
+			}
+
+			o := newTestOAuth(fakeClients{thirdParty.ID: thirdParty}, rdb)
+			o.authPg = authPg
+
+			cr, code, err := o.DecideConsent(context.Background(), "consent", tt.approved)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("DecideConsent() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if cr == nil || cr.ID != "consent" {
+				t.Fatalf("DecideConsent() = %+v, want the consent request back", cr)
+			}
+
+			granted, found := authPg.consents[testUser.Id+"/"+thirdParty.ID]
+			if found != tt.approved || (tt.approved && !slices.Equal(granted, []string{"openid", "email"})) {
+				t.Errorf("saved consent = %v", granted)
+			}
+
+			if (code != "") != tt.approved || (code != "" && rdb.codes[code] == nil) {
+				t.Errorf("authorization code = %q", code)
+			}
+
+			// a consent request is answered once
+			if _, _, err := o.DecideConsent(context.Background(), "consent", tt.approved); !errors.Is(err, vars.ErrConsentRequestNotFound) {
+				t.Errorf("second DecideConsent() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDecideDeviceConsent(t *testing.T) {
+	tests := []struct {
+		name        string
+		client      *model.Client
+		approved    bool
+		wantConsent bool
+	}{
+		{name: "third-party approved", client: thirdParty, approved: true, wantConsent: true},
+		{name: "third-party denied", client: thirdParty},
+		{name: "first-party approved", client: firstParty, approved: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authPg, rdb := newFakePostgres(testUser), newFakeRedis()
+			rdb.userCodes["ABCD-EFGH"] = "device"
+			rdb.devices["device"] = &model.DeviceAuthorization{
+				ClientID: tt.client.ID,
+				UserCode: "ABCD-EFGH",
+				Scope:    "openid email",
+				Status:   vars.DeviceAuthorizationPending,
+				Email:    testUser.Email,
+				Ticket:   "ticket",
+			}
+
+			o := newTestOAuth(fakeClients{thirdParty.ID: thirdParty, firstParty.ID: firstParty}, rdb)
+			o.authPg = authPg
+
+			da, err := o.DecideDevice(context.Background(), "ABCD-EFGH", "ticket", tt.approved)
+			if err != nil {
+				t.Fatalf("DecideDevice() error = %v", err)
+			}
+
+			wantStatus := vars.DeviceAuthorizationDenied
+			if tt.approved {
+				wantStatus = vars.DeviceAuthorizationApproved
+			}
+			if da.Status != wantStatus || da.Email != testUser.Email {
+				t.Errorf("device authorization = %+v", da)
+			}
+
+			granted, found := authPg.consents[testUser.Id+"/"+tt.client.ID]
+			if found != tt.wantConsent || (found && !slices.Equal(granted, []string{"openid", "email"})) {
+				t.Errorf("saved consent = %v, want %t", granted, tt.wantConsent)
+			}
+		})
+	}
+}
+
+func TestExchangeTokenConsent(t *testing.T) {
+	jp := newTestJWTProcessor(t)
+
+	subject := func(t *testing.T, audience []string, dpopJKT string) string {
+		t.Helper()
+
+		token, err := jp.GenerateAccessToken(testUser, &model.Session{
+			ID:       "session",
+			Client:   "web",
+			Scope:    "openid email",
+			Audience: audience,
+			DPoPJKT:  dpopJKT,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name          string
+		client        *model.Client
+		subjectToken  string
+		granted       []string
+		wantScope     string
+		wantTokenType string
+		wantErr       error
+	}{
+		{
+			name:          "first-party client",
+			client:        firstParty,
+			subjectToken:  subject(t, []string{"service"}, ""),
+			wantScope:     "openid email",
+			wantTokenType: vars.TokenTypeBearer,
+		},
+		{
+			name:          "dpop bound subject token",
+			client:        firstParty,
+			subjectToken:  subject(t, []string{"service"}, "jkt"),
+			wantScope:     "openid email",
+			wantTokenType: vars.TokenTypeDPoP,
+		},
+		{
+			name:         "subject token of another audience",
+			client:       firstParty,
+			subjectToken: subject(t, []string{"other"}, ""),
+			wantErr:      vars.ErrInvalidGrant,
+		},
+		{
+			name:         "third-party client without consent",
+			client:       thirdParty,
+			subjectToken: subject(t, []string{"third-party"}, ""),
+			wantErr:      vars.ErrInvalidGrant,
+		},
+		{
+			name:         "third-party client with consent to other scopes",
+			client:       thirdParty,
+			subjectToken: subject(t, []string{"third-party"}, ""),
+			granted:      []string{"profile"},
+			wantErr:      vars.ErrInvalidGrant,
+		},
+		{
+			name:          "third-party client with consent",
+			client:        thirdParty,
+			subjectToken:  subject(t, []string{"third-party"}, ""),
+			granted:       []string{"openid"},
+			wantScope:     "openid",
+			wantTokenType: vars.TokenTypeBearer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authPg, rdb := newFakePostgres(testUser), newFakeRedis()
+			rdb.sessions["session"] = &model.Session{ID: "session", User: testUser.Id}
+			if tt.granted != nil {
+				authPg.consents[testUser.Id+"/"+thirdParty.ID] = tt.granted
+			}
+
+			o := newTestOAuth(fakeClients{}, rdb)
+			o.authPg = authPg
+			o.jProcessor = jp
+
+			response, err := o.ExchangeToken(context.Background(), tt.client, &model.TokenExchangeRequest{
+				SubjectToken:     tt.subjectToken,
+				SubjectTokenType: vars.TokenTypeAccessToken,
+			})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("ExchangeToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if response.Scope != tt.wantScope || response.TokenType != tt.wantTokenType {
+				t.Errorf("ExchangeToken() = scope %q type %s, want %q %s",
+					response.Scope, response.TokenType, tt.wantScope, tt.wantTokenType)
+			}
+
+			claims, err := jp.TokenVerify(response.AccessToken, tt.client.Audiences[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if claims.Actor == nil || claims.Actor.Sub != tt.client.ID || (claims.Confirmation != nil) != (tt.wantTokenType == vars.TokenTypeDPoP) {
+				t.Errorf("exchanged token claims = %+v", claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func authEvent(level zerolog.Level, event, user string) *zerolog.Event {
+	return log.WithLevel(level).Str("event", event).Str("user", user)
+}
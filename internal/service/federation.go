@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/config"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/mxmrykov/polonium-auth/pkg/utils"
+	"github.com/rs/zerolog"
+)
+
+type (
+	// IFederation logs users in with upstream OpenID Connect providers. An
+	// upstream account is linked to a user on its first login: to the user
+	// with its verified email when the provider is limited to its domains,
+	// or to a new user created for it.
+	IFederation interface {
+		Providers() []model.FederationProvider
+		Begin(ctx context.Context, provider string) (string, error)
+		Complete(ctx context.Context, provider, state, code string, session *model.Session) (string, string, error)
+	}
+
+	federation struct {
+		auth          IAuth
+		authPg        repository.IAuthPostgres
+		authRdb       repository.IAuthRedis
+		providers     map[string]*upstreamProvider
+		order         []string
+		defaultClient string
+		stateTTL      time.Duration
+	}
+
+	upstreamProvider struct {
+		cfg      config.FederationProvider
+		upstream *jwtAuth.Upstream
+	}
+)
+
+func NewFederation(
+	auth IAuth,
+	authPg repository.IAuthPostgres,
+	authRdb repository.IAuthRedis,
+	cfg config.Federation,
+	baseURL, defaultClient string,
+) IFederation {
+	providers := make(map[string]*upstreamProvider, len(cfg.Providers))
+	order := make([]string, 0, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		redirectURI := strings.TrimRight(baseURL, "/") + fmt.Sprintf(vars.PathFederationCallback, p.ID)
+		providers[p.ID] = &upstreamProvider{
+			cfg:      p,
+			upstream: jwtAuth.NewUpstream(p.Issuer, p.ClientID, p.ClientSecret, redirectURI, p.Scopes),
+		}
+		order = append(order, p.ID)
+	}
+
+	return &federation{
+		auth:          auth,
+		authPg:        authPg,
+		authRdb:       authRdb,
+		providers:     providers,
+		order:         order,
+		defaultClient: defaultClient,
+		stateTTL:      cfg.StateTTL,
+	}
+}
+
+func (f *federation) Providers() []model.FederationProvider {
+	providers := make([]model.FederationProvider, 0, len(f.order))
+	for _, id := range f.order {
+		providers = append(providers, model.FederationProvider{
+			ID:       id,
+			Name:     f.providers[id].cfg.Name,
+			LoginURL: fmt.Sprintf(vars.PathFederationLogin, id),
+		})
+	}
+
+	return providers
+}
+
+// Begin returns the upstream url to send the browser to.
+func (f *federation) Begin(ctx context.Context, provider string) (string, error) {
+	p, ok := f.providers[provider]
+	if !ok {
+		return "", vars.ErrUnknownFederationProvider
+	}
+
+	state, err := utils.NewSecret()
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := utils.NewSecret()
+	if err != nil {
+		return "", err
+	}
+
+	verifier, err := utils.NewSecret()
+	if err != nil {
+		return "", err
+	}
+
+	fs := &model.FederationState{
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+	}
+
+	if err := f.authRdb.NewFederationState(state, fs, f.stateTTL); err != nil {
+		return "", fmt.Errorf("cannot save federation state: %v", err)
+	}
+
+	target, err := p.upstream.AuthCodeURL(ctx, state, fs.Nonce, fs.CodeVerifier)
+	if err != nil {
+		return "", fmt.Errorf("cannot build upstream login url: %v", err)
+	}
+
+	return target, nil
+}
+
+// Complete redeems the upstream code and starts a session for the linked
+// user with the default client.
+func (f *federation) Complete(
+	ctx context.Context,
+	provider, state, code string,
+	session *model.Session,
+) (string, string, error) {
+	p, ok := f.providers[provider]
+	if !ok {
+		return "", "", vars.ErrUnknownFederationProvider
+	}
+
+	fs, err := f.authRdb.UseFederationState(state)
+	if err != nil {
+		if errors.Is(err, vars.ErrFederationStateNotFound) {
+			return "", "", err
+		}
+
+		return "", "", fmt.Errorf("cannot get federation state: %v", err)
+	}
+
+	if fs.Provider != provider {
+		return "", "", vars.ErrFederationStateNotFound
+	}
+
+	identity, err := p.upstream.Exchange(ctx, code, fs.CodeVerifier, fs.Nonce)
+	if err != nil {
+		return "", "", err
+	}
+
+	email, err := f.link(ctx, p, identity)
+	if err != nil {
+		return "", "", err
+	}
+
+	session.Client, session.MFA = f.defaultClient, vars.MFAMethodFederated
+	access, refresh, err := f.auth.CreateSession(ctx, email, session)
+	if err != nil {
+		return "", "", err
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventFederatedLogin, session.User).
+		Str("provider", provider).
+		Str("subject", identity.Subject).
+		Str("session", session.ID).
+		Str("ip", session.IP).
+		Msg("user logged in with upstream provider")
+
+	return access, refresh, nil
+}
+
+// link returns the email of the user the upstream account belongs to. An
+// unlinked account is linked by its verified email, and a user is created
+// when there is none with that email. Only providers limited to their own
+// domains may link an existing user, since the link skips its password and
+// TOTP code.
+func (f *federation) link(ctx context.Context, p *upstreamProvider, identity *jwtAuth.UpstreamIdentity) (string, error) {
+	linked, err := f.authPg.GetFederatedIdentity(ctx, p.cfg.ID, identity.Subject)
+	if err == nil {
+		if err := f.authPg.TouchFederatedIdentity(ctx, p.cfg.ID, identity.Subject, identity.Email); err != nil {
+			return "", fmt.Errorf("cannot update federated identity: %v", err)
+		}
+
+		return linked.UserEmail, nil
+	}
+
+	if !errors.Is(err, vars.ErrFederatedIdentityNotFound) {
+		return "", fmt.Errorf("cannot get federated identity from db: %v", err)
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return "", vars.ErrFederatedEmailUnverified
+	}
+
+	if len(p.cfg.Domains) > 0 {
+		_, domain, _ := strings.Cut(identity.Email, "@")
+		if !slices.Contains(p.cfg.Domains, domain) {
+			return "", vars.ErrFederatedDomainNotAllowed
+		}
+	}
+
+	exists, err := f.authPg.IsUserExists(ctx, identity.Email)
+	if err != nil {
+		return "", fmt.Errorf("cannot check user existance in db: %v", err)
+	}
+
+	if exists && len(p.cfg.Domains) == 0 {
+		return "", vars.ErrFederatedAccountExists
+	}
+
+	if !exists {
+		if err := f.signup(ctx, p.cfg.ID, identity.Email); err != nil {
+			return "", err
+		}
+	}
+
+	if err := f.authPg.LinkFederatedIdentity(ctx, &model.FederatedIdentity{
+		Provider:  p.cfg.ID,
+		Subject:   identity.Subject,
+		UserEmail: identity.Email,
+		Email:     identity.Email,
+	}); err != nil {
+		return "", fmt.Errorf("cannot link federated identity: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventFederatedLinked, identity.Email).
+		Str("provider", p.cfg.ID).
+		Str("subject", identity.Subject).
+		Bool("created", !exists).
+		Msg("upstream account linked")
+
+	return identity.Email, nil
+}
+
+// signup creates a verified user without a password. The upstream provider
+// has verified the email, and the user logs in through it.
+func (f *federation) signup(ctx context.Context, provider, email string) error {
+	user := &model.User{
+		Email:    email,
+		Id:       uuid.New().String(),
+		SshSign:  utils.NewCert(),
+		Deployer: uuid.New().String(),
+	}
+
+	if err := f.authPg.Signup(ctx, user); err != nil {
+		return fmt.Errorf("cannot signup user in pg: %v", err)
+	}
+
+	if err := f.authPg.VerificateUser(ctx, email); err != nil {
+		return fmt.Errorf("cannot verificate user: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventFederatedSignup, email).
+		Str("provider", provider).
+		Msg("user signed up with upstream provider")
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/config"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+func TestFederationLink(t *testing.T) {
+	open := &upstreamProvider{cfg: config.FederationProvider{ID: "open"}}
+	corporate := &upstreamProvider{cfg: config.FederationProvider{ID: "corporate", Domains: []string{"polonium.ws"}}}
+
+	identity := func(subject, email string, verified bool) *jwtAuth.UpstreamIdentity {
+		return &jwtAuth.UpstreamIdentity{Subject: subject, Email: email, EmailVerified: verified}
+	}
+
+	tests := []struct {
+		name        string
+		provider    *upstreamProvider
+		identity    *jwtAuth.UpstreamIdentity
+		wantEmail   string
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name:      "linked account",
+			provider:  open,
+			identity:  identity("linked", "new@mail.example", false),
+			wantEmail: testUser.Email,
+		},
+		{
+			name:        "new user",
+			provider:    open,
+			identity:    identity("new", "new@mail.example", true),
+			wantEmail:   "new@mail.example",
+			wantCreated: true,
+		},
+		{
+			name:      "existing user on a provider of its domain",
+			provider:  corporate,
+			identity:  identity("new", testUser.Email, true),
+			wantEmail: testUser.Email,
+		},
+		{
+			name:     "existing user on a provider of any domain",
+			provider: open,
+			identity: identity("new", testUser.Email, true),
+			wantErr:  vars.ErrFederatedAccountExists,
+		},
+		{
+			name:     "unverified email",
+			provider: corporate,
+			identity: identity("new", testUser.Email, false),
+			wantErr:  vars.ErrFederatedEmailUnverified,
+		},
+		{
+			name:     "no email",
+			provider: open,
+			identity: identity("new", "", true),
+			wantErr:  vars.ErrFederatedEmailUnverified,
+		},
+		{
+			name:     "other domain",
+			provider: corporate,
+			identity: identity("new", "user@mail.example", true),
+			wantErr:  vars.ErrFederatedDomainNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := *testUser
+			authPg := newFakePostgres(&user)
+			authPg.linked["open/linked"] = &model.FederatedIdentity{Provider: "open", Subject: "linked", UserEmail: testUser.Email}
+
+			f := &federation{authPg: authPg}
+			email, err := f.link(context.Background(), tt.provider, tt.identity)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("link() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if email != tt.wantEmail {
+				t.Errorf("link() = %q, want %q", email, tt.wantEmail)
+			}
+
+			created := authPg.users[tt.identity.Email]
+			if tt.wantCreated && (created == nil || !created.Verified || created.SshSign == "") {
+				t.Errorf("created user = %+v", created)
+			}
+
+			linked, err := authPg.GetFederatedIdentity(context.Background(), tt.provider.cfg.ID, tt.identity.Subject)
+			if (err == nil) != (tt.wantErr == nil) || (linked != nil && linked.UserEmail != tt.wantEmail) {
+				t.Errorf("federated identity = %+v, error %v", linked, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/config"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+type (
+	// testKeys is a KeyProvider with a single Ed25519 key.
+	testKeys struct {
+		key *jwtAuth.Key
+	}
+
+	// fakePostgres keeps users by email, registered clients by id,
+	// consents by user and client and federated identities by provider and
+	// subject.
+	fakePostgres struct {
+		repository.IAuthPostgres
+
+		users    map[string]*model.User
+		clients  map[string]*model.RegisteredClient
+		consents map[string][]string
+		linked   map[string]*model.FederatedIdentity
+	}
+
+	// fakeClients is an IClients over a fixed set of clients.
+	fakeClients map[string]*model.Client
+
+	// fakeRedis keeps sessions, refresh token families, revoked tokens,
+	// authorization codes, consent requests and device authorizations in
+	// maps. Expiry is not modelled, polls keeps the interval a device code
+	// was polled with.
+	// Methods a test does not expect panic through the embedded nil
+	// interface.
+	fakeRedis struct {
+		repository.IAuthRedis
+
+		sessions  map[string]*model.Session
+		families  map[string]*model.RefreshFamily
+		revoked   map[string]bool
+		codes     map[string]*model.AuthorizationCode
+		consents  map[string]*model.ConsentRequest
+		userCodes map[string]string
+		devices   map[string]*model.DeviceAuthorization
+		polls     map[string]time.Duration
+		failures  map[string]int
+		revokeErr error
+	}
+
+	// fakeVault keeps the SSH CA key.
+	fakeVault struct {
+		repository.IAuthVault
+
+		sshCAKey string
+	}
+)
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testKeys{key: &jwtAuth.Key{Kid: "kid", Method: jwt.SigningMethodEdDSA, Private: private, Public: public}}
+}
+
+func (k *testKeys) Algorithm() string                 { return jwt.SigningMethodEdDSA.Alg() }
+func (k *testKeys) SigningKey() (*jwtAuth.Key, error) { return k.key, nil }
+func (k *testKeys) PublicKeys() []jose.JSONWebKey     { return nil }
+func (k *testKeys) CacheTTL() time.Duration           { return time.Minute }
+
+func (k *testKeys) VerificationKey(kid string) (*jwtAuth.Key, error) {
+	if kid != k.key.Kid {
+		return nil, errors.New("unknown key")
+	}
+	return k.key, nil
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		sessions:  map[string]*model.Session{},
+		families:  map[string]*model.RefreshFamily{},
+		revoked:   map[string]bool{},
+		codes:     map[string]*model.AuthorizationCode{},
+		consents:  map[string]*model.ConsentRequest{},
+		userCodes: map[string]string{},
+		devices:   map[string]*model.DeviceAuthorization{},
+		polls:     map[string]time.Duration{},
+		failures:  map[string]int{},
+	}
+}
+
+func (f fakeClients) Get(_ context.Context, id string) (*model.Client, error) {
+	client, ok := f[id]
+	if !ok {
+		return nil, vars.ErrUnknownClient
+	}
+
+	return client, nil
+}
+
+func (f fakeClients) Authenticate(ctx context.Context, id, _ string) (*model.Client, error) {
+	return f.Get(ctx, id)
+}
+
+// newTestClients knows the public client web and the confidential clients
+// other and gateway, of which only gateway may exchange tokens.
+func newTestClients() IClients {
+	return NewClients([]config.Client{
+		{ID: "web", Scopes: []string{"openid", "profile"}, Audiences: []string{"api", "billing", "gateway"}},
+		{ID: "other", SecretHash: "hash", Scopes: []string{"profile"}, Audiences: []string{"other-api"}},
+		{
+			ID:         "gateway",
+			SecretHash: "hash",
+			Scopes:     []string{"profile", "email"},
+			Audiences:  []string{"api", "billing"},
+			GrantTypes: []string{vars.GrantTypeTokenExchange},
+		},
+	}, newFakePostgres())
+}
+
+// newFakePostgres knows user@example.com and the given users.
+func newFakePostgres(users ...*model.User) *fakePostgres {
+	f := &fakePostgres{
+		users: map[string]*model.User{
+			"user@example.com": {Id: "user-id", Email: "user@example.com", Deployer: "deployer"},
+		},
+		clients:  map[string]*model.RegisteredClient{},
+		consents: map[string][]string{},
+		linked:   map[string]*model.FederatedIdentity{},
+	}
+
+	for _, user := range users {
+		f.users[user.Email] = user
+	}
+
+	return f
+}
+
+func (f *fakePostgres) GetUser(_ context.Context, email string) (*model.User, error) {
+	user, ok := f.users[email]
+	if !ok {
+		return nil, vars.ErrUserNotFound
+	}
+
+	stored := *user
+	return &stored, nil
+}
+
+func (f *fakePostgres) GetClient(_ context.Context, id string) (*model.Client, error) {
+	client, ok := f.clients[id]
+	if !ok || client.Status != vars.ClientStatusApproved {
+		return nil, vars.ErrUnknownClient
+	}
+
+	stored := client.Client
+	return &stored, nil
+}
+
+func (f *fakePostgres) IsUserExists(_ context.Context, email string) (bool, error) {
+	_, ok := f.users[email]
+	return ok, nil
+}
+
+func (f *fakePostgres) Signup(_ context.Context, user *model.User) error {
+	f.users[user.Email] = user
+	return nil
+}
+
+func (f *fakePostgres) VerificateUser(_ context.Context, email string) error {
+	f.users[email].Verified = true
+	return nil
+}
+
+func (f *fakePostgres) GetFederatedIdentity(_ context.Context, provider, subject string) (*model.FederatedIdentity, error) {
+	identity, ok := f.linked[provider+"/"+subject]
+	if !ok {
+		return nil, vars.ErrFederatedIdentityNotFound
+	}
+
+	return identity, nil
+}
+
+func (f *fakePostgres) LinkFederatedIdentity(_ context.Context, identity *model.FederatedIdentity) error {
+	f.linked[identity.Provider+"/"+identity.Subject] = identity
+	return nil
+}
+
+func (f *fakePostgres) TouchFederatedIdentity(_ context.Context, provider, subject, email string) error {
+	f.linked[provider+"/"+subject].Email = email
+	return nil
+}
+
+func (f *fakePostgres) RegisterClient(_ context.Context, client *model.RegisteredClient) error {
+	f.clients[client.ID] = client
+	return nil
+}
+
+func (f *fakePostgres) GetConsent(_ context.Context, user, client string) ([]string, bool, error) {
+	scopes, ok := f.consents[user+"/"+client]
+	return scopes, ok, nil
+}
+
+func (f *fakePostgres) SaveConsent(_ context.Context, user, client string, scopes []string) error {
+	key := user + "/" + client
+	for _, scope := range scopes {
+		if !slices.Contains(f.consents[key], scope) {
+			f.consents[key] = append(f.consents[key], scope)
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeVault) GetSSHCAKey(context.Context) (string, error) {
+	return f.sshCAKey, nil
+}
+
+func (f *fakeVault) PutSSHCAKey(_ context.Context, key string) error {
+	f.sshCAKey = key
+	return nil
+}
+
+func (f *fakeRedis) NewAuthSession(session *model.Session, _ time.Duration) error {
+	stored := *session
+	f.sessions[session.ID] = &stored
+	return nil
+}
+
+func (f *fakeRedis) GetAuthSession(session string) (*model.Session, error) {
+	s, ok := f.sessions[session]
+	if !ok {
+		return nil, vars.ErrSessionNotFound
+	}
+
+	return s, nil
+}
+
+func (f *fakeRedis) ListAuthSessions(user string) ([]*model.Session, error) {
+	var sessions []*model.Session
+	for _, s := range f.sessions {
+		if s.User == user {
+			sessions = append(sessions, s)
+		}
+	}
+
+	return sessions, nil
+}
+
+func (f *fakeRedis) DropAuthSession(_, session string) error {
+	delete(f.sessions, session)
+	delete(f.families, session)
+	return nil
+}
+
+func (f *fakeRedis) DropAuthSessions(user string) ([]string, error) {
+	var dropped []string
+	for id, s := range f.sessions {
+		if s.User == user {
+			dropped = append(dropped, id)
+			_ = f.DropAuthSession(user, id)
+		}
+	}
+
+	return dropped, nil
+}
+
+func (f *fakeRedis) NewRefreshFamily(session string, family *model.RefreshFamily, _ time.Duration) error {
+	stored := *family
+	f.families[session] = &stored
+	return nil
+}
+
+func (f *fakeRedis) GetRefreshFamily(session string) (*model.RefreshFamily, error) {
+	family, ok := f.families[session]
+	if !ok {
+		return nil, vars.ErrRefreshFamilyNotFound
+	}
+
+	stored := *family
+	return &stored, nil
+}
+
+func (f *fakeRedis) RotateRefreshToken(session, presented, next string, _ time.Duration) (*model.RefreshFamily, error) {
+	family, ok := f.families[session]
+	if !ok {
+		return nil, vars.ErrRefreshFamilyNotFound
+	}
+
+	current := *family
+	if family.Revoked {
+		return &current, vars.ErrRefreshFamilyRevoked
+	}
+
+	if family.Current != presented {
+		return &current, vars.ErrRefreshTokenReused
+	}
+
+	family.Current = next
+	rotated := *family
+	return &rotated, nil
+}
+
+func (f *fakeRedis) RevokeRefreshFamily(session string, _ *model.RefreshFamily, _ time.Duration) error {
+	if f.revokeErr != nil {
+		return f.revokeErr
+	}
+
+	f.families[session].Revoked = true
+	return nil
+}
+
+func (f *fakeRedis) RevokeToken(jti string, _ time.Duration) error {
+	f.revoked[jti] = true
+	return nil
+}
+
+func (f *fakeRedis) IsTokenRevoked(jti string) (bool, error) {
+	return f.revoked[jti], nil
+}
+
+func (f *fakeRedis) NewConsentRequest(id string, cr *model.ConsentRequest, _ time.Duration) error {
+	f.consents[id] = cr
+	return nil
+}
+
+func (f *fakeRedis) UseConsentRequest(id string) (*model.ConsentRequest, error) {
+	cr, ok := f.consents[id]
+	if !ok {
+		return nil, vars.ErrConsentRequestNotFound
+	}
+
+	delete(f.consents, id)
+	return cr, nil
+}
+
+func (f *fakeRedis) NewAuthorizationCode(code string, ac *model.AuthorizationCode, _ time.Duration) error {
+	f.codes[code] = ac
+	return nil
+}
+
+func (f *fakeRedis) UseAuthorizationCode(code string) (*model.AuthorizationCode, error) {
+	ac, ok := f.codes[code]
+	if !ok {
+		return nil, vars.ErrAuthCodeNotFound
+	}
+
+	delete(f.codes, code)
+	return ac, nil
+}
+
+func (f *fakeRedis) NewDeviceAuthorization(deviceCode string, da *model.DeviceAuthorization, _ time.Duration) (bool, error) {
+	if _, ok := f.userCodes[da.UserCode]; ok {
+		return false, nil
+	}
+
+	stored := *da
+	f.userCodes[da.UserCode] = deviceCode
+	f.devices[deviceCode] = &stored
+	return true, nil
+}
+
+func (f *fakeRedis) GetDeviceAuthorization(deviceCode string) (*model.DeviceAuthorization, error) {
+	da, ok := f.devices[deviceCode]
+	if !ok {
+		return nil, vars.ErrDeviceCodeNotFound
+	}
+
+	stored := *da
+	return &stored, nil
+}
+
+func (f *fakeRedis) UpdateDeviceAuthorization(deviceCode string, da *model.DeviceAuthorization) error {
+	current, ok := f.devices[deviceCode]
+	if !ok || current.Status != vars.DeviceAuthorizationPending {
+		return vars.ErrDeviceCodeNotFound
+	}
+
+	stored := *da
+	f.devices[deviceCode] = &stored
+	return nil
+}
+
+func (f *fakeRedis) PollDeviceAuthorization(deviceCode string, interval time.Duration) (bool, error) {
+	if _, ok := f.polls[deviceCode]; ok {
+		return false, nil
+	}
+
+	f.polls[deviceCode] = interval
+	return true, nil
+}
+
+func (f *fakeRedis) UseDeviceAuthorization(deviceCode string) (*model.DeviceAuthorization, error) {
+	da, err := f.GetDeviceAuthorization(deviceCode)
+	delete(f.devices, deviceCode)
+	return da, err
+}
+
+func (f *fakeRedis) GetUserCode(userCode string) (string, error) {
+	deviceCode, ok := f.userCodes[userCode]
+	if !ok {
+		return "", vars.ErrUserCodeNotFound
+	}
+
+	return deviceCode, nil
+}
+
+func (f *fakeRedis) UseUserCode(userCode string) (string, error) {
+	deviceCode, err := f.GetUserCode(userCode)
+	delete(f.userCodes, userCode)
+	return deviceCode, err
+}
+
+func (f *fakeRedis) UserCodeFailures(ip string) (int, error) {
+	return f.failures[ip], nil
+}
+
+func (f *fakeRedis) CountUserCodeFailure(ip string, _ time.Duration) error {
+	f.failures[ip]++
+	return nil
+}
+
+// newTestFormat returns the JWT format over keys.
+func newTestFormat(t *testing.T, keys jwtAuth.KeyProvider) jwtAuth.TokenFormat {
+	t.Helper()
+
+	format, err := jwtAuth.NewTokenFormat(jwtAuth.TokenFormatJWT, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return format
+}
@@ -0,0 +1,822 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/mxmrykov/polonium-auth/pkg/utils"
+	"github.com/rs/zerolog"
+)
+
+// slowDownStep is added to the poll interval of a device each time it polls
+// too often (RFC 8628, section 3.5).
+const slowDownStep = 5 * time.Second
+
+type (
+	IOAuth interface {
+		ValidateAuthorization(ctx context.Context, r *model.AuthorizationRequest) (*model.Client, error)
+		IssueAuthorizationCode(ctx context.Context, r *model.AuthorizationRequest, email, userAgent, ip string) (string, error)
+		RequestConsent(ctx context.Context, r *model.AuthorizationRequest, email, userAgent, ip string) (*model.ConsentRequest, error)
+		DecideConsent(ctx context.Context, id string, approved bool) (*model.ConsentRequest, string, error)
+		RedeemAuthorizationCode(ctx context.Context, client *model.Client, r *model.AuthorizationCodeRequest) (*model.TokenResponse, error)
+		AuthorizeDevice(ctx context.Context, client *model.Client, scope string, audience []string, userAgent, ip string) (*model.DeviceAuthorizationResponse, error)
+		PendingDevice(ctx context.Context, userCode, email, ip string) (*model.DeviceAuthorization, *model.Client, error)
+		DecideDevice(ctx context.Context, userCode, ticket string, approved bool) (*model.DeviceAuthorization, error)
+		RedeemDeviceCode(ctx context.Context, client *model.Client, deviceCode, dpopJKT string) (*model.TokenResponse, error)
+		ClientCredentials(ctx context.Context, client *model.Client, scope string, audience []string, dpopJKT string) (*model.TokenResponse, error)
+		UserInfo(ctx context.Context, email, scope string) (*model.UserInfo, error)
+		ExchangeToken(ctx context.Context, client *model.Client, r *model.TokenExchangeRequest) (*model.TokenResponse, error)
+	}
+
+	oauth struct {
+		auth       IAuth
+		clients    IClients
+		authPg     repository.IAuthPostgres
+		authRdb    repository.IAuthRedis
+		jProcessor *jwtAuth.JWTProcessor
+		codeTTL    time.Duration
+		deviceTTL  time.Duration
+		interval   time.Duration
+		consentTTL time.Duration
+		// userCodeAttempts is how many unknown user codes an ip may enter
+		// within the lifetime of a device code
+		userCodeAttempts int
+	}
+)
+
+func NewOAuth(
+	auth IAuth,
+	clients IClients,
+	authPg repository.IAuthPostgres,
+	authRdb repository.IAuthRedis,
+	jProcessor *jwtAuth.JWTProcessor,
+	codeTTL, deviceTTL, deviceInterval, consentTTL time.Duration,
+	userCodeAttempts int,
+) IOAuth {
+	return &oauth{
+		auth:       auth,
+		clients:    clients,
+		authPg:     authPg,
+		authRdb:    authRdb,
+		jProcessor: jProcessor,
+		codeTTL:    codeTTL,
+		deviceTTL:  deviceTTL,
+		interval:   deviceInterval,
+		consentTTL: consentTTL,
+
+		userCodeAttempts: userCodeAttempts,
+	}
+}
+
+// ValidateAuthorization checks an authorization request before the user
+// logs in. ErrUnknownClient and ErrInvalidRedirectURI must be shown to the
+// user, every other error is sent back to the redirect uri. A missing
+// redirect uri is filled in when the client has registered only one.
+func (o *oauth) ValidateAuthorization(ctx context.Context, r *model.AuthorizationRequest) (*model.Client, error) {
+	client, err := o.clients.Get(ctx, r.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.RedirectURI == "" && len(client.RedirectURIs) == 1 {
+		r.RedirectURI = client.RedirectURIs[0]
+	}
+
+	if !slices.Contains(client.RedirectURIs, r.RedirectURI) {
+		return nil, vars.ErrInvalidRedirectURI
+	}
+
+	if r.ResponseType != vars.ResponseTypeCode {
+		return nil, fmt.Errorf("%w: %s", vars.ErrUnsupportedResponseType, r.ResponseType)
+	}
+
+	if !slices.Contains(client.GrantTypes, vars.GrantTypeAuthorizationCode) {
+		return nil, vars.ErrUnauthorizedClient
+	}
+
+	if r.CodeChallengeMethod != vars.CodeChallengeMethodS256 || !jwtAuth.IsCodeChallengeValid(r.CodeChallenge) {
+		return nil, fmt.Errorf("%w: an S256 code challenge is required", vars.ErrInvalidRequest)
+	}
+
+	if r.DPoPJKT != "" && !jwtAuth.IsCodeChallengeValid(r.DPoPJKT) {
+		return nil, fmt.Errorf("%w: malformed dpop_jkt", vars.ErrInvalidRequest)
+	}
+
+	if _, _, err := narrowGrant(client, r.Scope, r.Audience); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// IssueAuthorizationCode is called once the user has logged in. The request
+// is validated again since it comes back through the login form.
+func (o *oauth) IssueAuthorizationCode(
+	ctx context.Context,
+	r *model.AuthorizationRequest,
+	email, userAgent, ip string,
+) (string, error) {
+	client, err := o.ValidateAuthorization(ctx, r)
+	if err != nil {
+		return "", err
+	}
+
+	scope, audience, err := narrowGrant(client, r.Scope, r.Audience)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := utils.NewSecret()
+	if err != nil {
+		return "", err
+	}
+
+	if err := o.authRdb.NewAuthorizationCode(code, &model.AuthorizationCode{
+		ClientID:      client.ID,
+		RedirectURI:   r.RedirectURI,
+		Email:         email,
+		Scope:         scope,
+		Audience:      audience,
+		CodeChallenge: r.CodeChallenge,
+		DPoPJKT:       r.DPoPJKT,
+		Nonce:         r.Nonce,
+		AMR:           []string{vars.AMRPassword, vars.AMROTP, vars.AMRMFA},
+		UserAgent:     userAgent,
+		IP:            ip,
+		MFA:           vars.MFAMethodTOTP,
+		CreatedAt:     time.Now(),
+	}, o.codeTTL); err != nil {
+		return "", fmt.Errorf("cannot store authorization code: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventAuthCodeIssued, email).
+		Str("client", client.ID).
+		Str("scope", scope).
+		Msg("authorization code issued")
+
+	return code, nil
+}
+
+// RequestConsent is called once the user has logged in. It returns nil when
+// the client is first-party or the user has already granted every requested
+// scope, otherwise the consent request to show to the user.
+func (o *oauth) RequestConsent(
+	ctx context.Context,
+	r *model.AuthorizationRequest,
+	email, userAgent, ip string,
+) (*model.ConsentRequest, error) {
+	client, err := o.ValidateAuthorization(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !client.ThirdParty {
+		return nil, nil
+	}
+
+	scope, _, err := narrowGrant(client, r.Scope, r.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := o.authPg.GetUser(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get user from db: %v", err)
+	}
+
+	granted, found, err := o.authPg.GetConsent(ctx, user.Id, client.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get consent from db: %v", err)
+	}
+
+	scopes := strings.Fields(scope)
+	if found && len(intersect(scopes, granted)) == len(scopes) {
+		return nil, nil
+	}
+
+	id, err := utils.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	cr := &model.ConsentRequest{
+		ID:         id,
+		Request:    r,
+		ClientName: client.Name,
+		Scopes:     scopes,
+		UserID:     user.Id,
+		Email:      email,
+		UserAgent:  userAgent,
+		IP:         ip,
+	}
+
+	if err := o.authRdb.NewConsentRequest(cr.ID, cr, o.consentTTL); err != nil {
+		return nil, fmt.Errorf("cannot store consent request: %v", err)
+	}
+
+	return cr, nil
+}
+
+// DecideConsent records the answer of the user. The consent request is
+// returned in any case so that the user can be sent back to the client,
+// with an authorization code when the user has approved it.
+func (o *oauth) DecideConsent(ctx context.Context, id string, approved bool) (*model.ConsentRequest, string, error) {
+	cr, err := o.authRdb.UseConsentRequest(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !approved {
+		authEvent(zerolog.InfoLevel, vars.EventConsentDenied, cr.Email).
+			Str("client", cr.Request.ClientID).
+			Strs("scopes", cr.Scopes).
+			Msg("consent denied")
+
+		return cr, "", vars.ErrAccessDenied
+	}
+
+	if err := o.authPg.SaveConsent(ctx, cr.UserID, cr.Request.ClientID, cr.Scopes); err != nil {
+		return cr, "", fmt.Errorf("cannot save consent: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventConsentGranted, cr.Email).
+		Str("client", cr.Request.ClientID).
+		Strs("scopes", cr.Scopes).
+		Msg("consent granted")
+
+	code, err := o.IssueAuthorizationCode(ctx, cr.Request, cr.Email, cr.UserAgent, cr.IP)
+	if err != nil {
+		return cr, "", err
+	}
+
+	return cr, code, nil
+}
+
+// RedeemAuthorizationCode exchanges a code for a new session. The code is
+// removed before any check, so a failed attempt also burns it.
+func (o *oauth) RedeemAuthorizationCode(
+	ctx context.Context,
+	client *model.Client,
+	r *model.AuthorizationCodeRequest,
+) (*model.TokenResponse, error) {
+	if !slices.Contains(client.GrantTypes, vars.GrantTypeAuthorizationCode) {
+		return nil, vars.ErrUnauthorizedClient
+	}
+
+	ac, err := o.authRdb.UseAuthorizationCode(r.Code)
+	if err != nil {
+		if errors.Is(err, vars.ErrAuthCodeNotFound) {
+			return nil, fmt.Errorf("%w: %v", vars.ErrInvalidGrant, err)
+		}
+
+		return nil, fmt.Errorf("cannot get authorization code: %v", err)
+	}
+
+	switch {
+	case ac.ClientID != client.ID:
+		return nil, fmt.Errorf("%w: code was issued to another client", vars.ErrInvalidGrant)
+	case ac.RedirectURI != r.RedirectURI:
+		return nil, fmt.Errorf("%w: redirect uri does not match", vars.ErrInvalidGrant)
+	case !jwtAuth.VerifyCodeVerifier(ac.CodeChallenge, r.CodeVerifier):
+		return nil, fmt.Errorf("%w: code verifier does not match", vars.ErrInvalidGrant)
+	case ac.DPoPJKT != "" && ac.DPoPJKT != r.DPoPJKT:
+		return nil, fmt.Errorf("%w: %v", vars.ErrInvalidGrant, vars.ErrDPoPKeyMismatch)
+	}
+
+	session := &model.Session{
+		UserAgent: ac.UserAgent,
+		IP:        ac.IP,
+		MFA:       ac.MFA,
+		Client:    ac.ClientID,
+		Scope:     ac.Scope,
+		Audience:  ac.Audience,
+		DPoPJKT:   r.DPoPJKT,
+	}
+
+	return o.grantSession(ctx, ac.Email, session, &jwtAuth.IDTokenParams{
+		ClientID: ac.ClientID,
+		Nonce:    ac.Nonce,
+		AuthTime: ac.CreatedAt,
+		AMR:      ac.AMR,
+		ACR:      vars.ACRMFA,
+	})
+}
+
+// AuthorizeDevice starts the device flow (RFC 8628). The device shows the
+// user code and polls the token endpoint while the user approves it.
+func (o *oauth) AuthorizeDevice(
+	_ context.Context,
+	client *model.Client,
+	scope string,
+	audience []string,
+	userAgent, ip string,
+) (*model.DeviceAuthorizationResponse, error) {
+	if !slices.Contains(client.GrantTypes, vars.GrantTypeDeviceCode) {
+		return nil, vars.ErrUnauthorizedClient
+	}
+
+	scope, audience, err := narrowGrant(client, scope, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceCode, err := utils.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		userCode, err := utils.NewUserCode()
+		if err != nil {
+			return nil, err
+		}
+
+		stored, err := o.authRdb.NewDeviceAuthorization(deviceCode, &model.DeviceAuthorization{
+			ClientID:  client.ID,
+			UserCode:  userCode,
+			Scope:     scope,
+			Audience:  audience,
+			Status:    vars.DeviceAuthorizationPending,
+			Interval:  o.interval,
+			UserAgent: userAgent,
+			IP:        ip,
+			ExpiresAt: time.Now().Add(o.deviceTTL),
+		}, o.deviceTTL)
+		if err != nil {
+			return nil, fmt.Errorf("cannot store device authorization: %v", err)
+		}
+
+		if stored {
+			return &model.DeviceAuthorizationResponse{
+				DeviceCode: deviceCode,
+				UserCode:   userCode,
+				ExpiresIn:  int64(o.deviceTTL.Seconds()),
+				Interval:   int64(o.interval.Seconds()),
+			}, nil
+		}
+	}
+
+	return nil, errors.New("cannot generate a free user code")
+}
+
+// PendingDevice shows the device authorization waiting for the user code to
+// the logged in user, so that the user knows what is asking for access
+// before approving it (RFC 8628, section 5.4). The returned ticket lets the
+// user decide without logging in again. Unknown user codes are counted per
+// ip and an ip that entered too many is refused until the window ends.
+func (o *oauth) PendingDevice(ctx context.Context, userCode, email, ip string) (*model.DeviceAuthorization, *model.Client, error) {
+	failures, err := o.authRdb.UserCodeFailures(ip)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get user code failures: %v", err)
+	}
+
+	if failures >= o.userCodeAttempts {
+		return nil, nil, vars.ErrTooManyUserCodes
+	}
+
+	deviceCode, da, err := o.pendingDevice(userCode)
+	if err != nil {
+		if !errors.Is(err, vars.ErrUserCodeNotFound) {
+			return nil, nil, err
+		}
+
+		if err := o.authRdb.CountUserCodeFailure(ip, o.deviceTTL); err != nil {
+			return nil, nil, fmt.Errorf("cannot count user code failure: %v", err)
+		}
+
+		return nil, nil, err
+	}
+
+	client, err := o.clients.Get(ctx, da.ClientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if da.Ticket, err = utils.NewSecret(); err != nil {
+		return nil, nil, err
+	}
+
+	da.Email = email
+	if err := o.authRdb.UpdateDeviceAuthorization(deviceCode, da); err != nil {
+		if errors.Is(err, vars.ErrDeviceCodeNotFound) {
+			return nil, nil, vars.ErrUserCodeNotFound
+		}
+
+		return nil, nil, err
+	}
+
+	return da, client, nil
+}
+
+// DecideDevice records the decision of the user who was shown the device
+// authorization with the ticket. The user has seen the client and scopes,
+// so an approval is also the consent of the user to a third-party client.
+func (o *oauth) DecideDevice(ctx context.Context, userCode, ticket string, approved bool) (*model.DeviceAuthorization, error) {
+	deviceCode, da, err := o.pendingDevice(userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if da.Ticket == "" || subtle.ConstantTimeCompare([]byte(da.Ticket), []byte(ticket)) != 1 {
+		return nil, vars.ErrUserCodeNotFound
+	}
+
+	// the user code can only be entered once
+	if _, err := o.authRdb.UseUserCode(userCode); err != nil {
+		return nil, err
+	}
+
+	if approved {
+		if err := o.consentDevice(ctx, da, da.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	da.Ticket = ""
+	da.Status = vars.DeviceAuthorizationDenied
+	if approved {
+		da.Status = vars.DeviceAuthorizationApproved
+		da.AMR = []string{vars.AMRPassword, vars.AMROTP, vars.AMRMFA}
+		da.MFA = vars.MFAMethodTOTP
+		da.AuthTime = time.Now()
+	}
+
+	if err := o.authRdb.UpdateDeviceAuthorization(deviceCode, da); err != nil {
+		if errors.Is(err, vars.ErrDeviceCodeNotFound) {
+			return nil, vars.ErrUserCodeNotFound
+		}
+
+		return nil, err
+	}
+
+	event, msg := vars.EventDeviceDenied, "device authorization denied"
+	if approved {
+		event, msg = vars.EventDeviceApproved, "device authorization approved"
+	}
+
+	authEvent(zerolog.InfoLevel, event, da.Email).
+		Str("client", da.ClientID).
+		Str("scope", da.Scope).
+		Str("ip", da.IP).
+		Msg(msg)
+
+	return da, nil
+}
+
+// pendingDevice returns the device code and the authorization of a user
+// code that is still waiting for the user.
+func (o *oauth) pendingDevice(userCode string) (string, *model.DeviceAuthorization, error) {
+	deviceCode, err := o.authRdb.GetUserCode(userCode)
+	if err != nil {
+		return "", nil, err
+	}
+
+	da, err := o.authRdb.GetDeviceAuthorization(deviceCode)
+	if err != nil {
+		if errors.Is(err, vars.ErrDeviceCodeNotFound) {
+			return "", nil, vars.ErrUserCodeNotFound
+		}
+
+		return "", nil, fmt.Errorf("cannot get device authorization: %v", err)
+	}
+
+	if da.Status != vars.DeviceAuthorizationPending {
+		return "", nil, vars.ErrUserCodeNotFound
+	}
+
+	return deviceCode, da, nil
+}
+
+// consentDevice saves the consent of the user to the third-party client of
+// an approved device authorization.
+func (o *oauth) consentDevice(ctx context.Context, da *model.DeviceAuthorization, email string) error {
+	client, err := o.clients.Get(ctx, da.ClientID)
+	if err != nil {
+		return err
+	}
+
+	if !client.ThirdParty {
+		return nil
+	}
+
+	user, err := o.authPg.GetUser(ctx, email)
+	if err != nil {
+		return fmt.Errorf("cannot get user from db: %v", err)
+	}
+
+	scopes := strings.Fields(da.Scope)
+	if err := o.authPg.SaveConsent(ctx, user.Id, client.ID, scopes); err != nil {
+		return fmt.Errorf("cannot save consent: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventConsentGranted, email).
+		Str("client", client.ID).
+		Strs("scopes", scopes).
+		Msg("consent granted")
+
+	return nil
+}
+
+// RedeemDeviceCode answers the polling device. The device code is removed
+// once the user has decided, so the tokens are issued only once.
+func (o *oauth) RedeemDeviceCode(
+	ctx context.Context,
+	client *model.Client,
+	deviceCode, dpopJKT string,
+) (*model.TokenResponse, error) {
+	if !slices.Contains(client.GrantTypes, vars.GrantTypeDeviceCode) {
+		return nil, vars.ErrUnauthorizedClient
+	}
+
+	da, err := o.authRdb.GetDeviceAuthorization(deviceCode)
+	if err != nil {
+		if errors.Is(err, vars.ErrDeviceCodeNotFound) {
+			return nil, vars.ErrExpiredToken
+		}
+
+		return nil, fmt.Errorf("cannot get device authorization: %v", err)
+	}
+
+	if da.ClientID != client.ID {
+		return nil, fmt.Errorf("%w: device code was issued to another client", vars.ErrInvalidGrant)
+	}
+
+	if da.Status == vars.DeviceAuthorizationPending {
+		inTime, err := o.authRdb.PollDeviceAuthorization(deviceCode, max(da.Interval, o.interval))
+		if err != nil {
+			return nil, fmt.Errorf("cannot check device poll interval: %v", err)
+		}
+
+		if !inTime {
+			// a decision that lands meanwhile wins over the longer interval
+			da.Interval = max(da.Interval, o.interval) + slowDownStep
+			if err := o.authRdb.UpdateDeviceAuthorization(deviceCode, da); err != nil && !errors.Is(err, vars.ErrDeviceCodeNotFound) {
+				return nil, fmt.Errorf("cannot slow down device: %v", err)
+			}
+
+			return nil, vars.ErrSlowDown
+		}
+
+		return nil, vars.ErrAuthorizationPending
+	}
+
+	if da, err = o.authRdb.UseDeviceAuthorization(deviceCode); err != nil {
+		if errors.Is(err, vars.ErrDeviceCodeNotFound) {
+			return nil, fmt.Errorf("%w: %v", vars.ErrInvalidGrant, err)
+		}
+
+		return nil, fmt.Errorf("cannot get device authorization: %v", err)
+	}
+
+	if da.Status != vars.DeviceAuthorizationApproved {
+		return nil, vars.ErrAccessDenied
+	}
+
+	return o.grantSession(ctx, da.Email, &model.Session{
+		UserAgent: da.UserAgent,
+		IP:        da.IP,
+		MFA:       da.MFA,
+		Client:    da.ClientID,
+		Scope:     da.Scope,
+		Audience:  da.Audience,
+		DPoPJKT:   dpopJKT,
+	}, &jwtAuth.IDTokenParams{
+		ClientID: da.ClientID,
+		AuthTime: da.AuthTime,
+		AMR:      da.AMR,
+		ACR:      vars.ACRMFA,
+	})
+}
+
+// ClientCredentials issues a token to a confidential client for itself.
+// No refresh token is issued, the client simply asks again.
+func (o *oauth) ClientCredentials(
+	_ context.Context,
+	client *model.Client,
+	scope string,
+	audience []string,
+	dpopJKT string,
+) (*model.TokenResponse, error) {
+	if client.SecretHash == "" || !slices.Contains(client.GrantTypes, vars.GrantTypeClientCredentials) {
+		return nil, vars.ErrUnauthorizedClient
+	}
+
+	scope, audience, err := narrowGrant(client, scope, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := o.jProcessor.GenerateClientToken(client.ID, scope, audience, dpopJKT)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate access token: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventClientTokenIssued, "").
+		Str("client", client.ID).
+		Strs("audience", audience).
+		Str("scope", scope).
+		Msg("client token issued")
+
+	return &model.TokenResponse{
+		AccessToken: token,
+		TokenType:   tokenType(dpopJKT),
+		ExpiresIn:   int64(o.jProcessor.AccessTTL().Seconds()),
+		Scope:       scope,
+	}, nil
+}
+
+// UserInfo serves the claims granted by the access token scope. The user is
+// read from the database so that the claims are always fresh.
+func (o *oauth) UserInfo(ctx context.Context, email, scope string) (*model.UserInfo, error) {
+	user, err := o.authPg.GetUser(ctx, email)
+	if err != nil {
+		if errors.Is(err, vars.ErrUserNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("cannot get user from db: %v", err)
+	}
+
+	if user.Banned {
+		return nil, vars.ErrUserBanned
+	}
+
+	return userInfo(user, scope), nil
+}
+
+// ExchangeToken implements RFC 8693. The client trades a user access token
+// whose audience names it for a token limited to the requested audience and
+// to scopes held by both the subject token and the client, and consented to
+// by the user for third-party clients. A DPoP bound subject token stays bound
+// to the same key.
+func (o *oauth) ExchangeToken(
+	ctx context.Context,
+	client *model.Client,
+	r *model.TokenExchangeRequest,
+) (*model.TokenResponse, error) {
+	if client.SecretHash == "" || !slices.Contains(client.GrantTypes, vars.GrantTypeTokenExchange) {
+		return nil, vars.ErrUnauthorizedClient
+	}
+
+	if r.SubjectTokenType != vars.TokenTypeAccessToken ||
+		(r.RequestedTokenType != "" && r.RequestedTokenType != vars.TokenTypeAccessToken) {
+		return nil, fmt.Errorf("%w: only access tokens can be exchanged", vars.ErrInvalidGrant)
+	}
+
+	subject, err := o.jProcessor.TokenVerify(r.SubjectToken, "")
+	if err != nil {
+		if errors.Is(err, vars.ErrRevocationCheckFailed) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: %v", vars.ErrInvalidGrant, err)
+	}
+
+	if subject.Subject != "access" {
+		return nil, fmt.Errorf("%w: subject token is not an access token", vars.ErrInvalidGrant)
+	}
+
+	// a client may only exchange tokens meant for itself, not tokens meant
+	// for an audience it happens to be allowed to request
+	if !slices.Contains(subject.Audience, client.ID) {
+		return nil, fmt.Errorf("%w: subject token is not meant for the client", vars.ErrInvalidGrant)
+	}
+
+	session, err := o.authRdb.GetAuthSession(subject.Session)
+	if err != nil {
+		if errors.Is(err, vars.ErrSessionNotFound) {
+			return nil, fmt.Errorf("%w: %v", vars.ErrInvalidGrant, err)
+		}
+
+		return nil, fmt.Errorf("cannot get session: %v", err)
+	}
+
+	if session.User != subject.UserID {
+		return nil, fmt.Errorf("%w: session is revoked", vars.ErrInvalidGrant)
+	}
+
+	scope, audience, err := narrowGrant(client, r.Scope, r.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	// a delegated token never carries more than the user granted
+	scopes := intersect(strings.Fields(scope), strings.Fields(subject.Scope))
+	if len(scopes) == 0 {
+		return nil, vars.ErrInvalidScope
+	}
+
+	// the user is not asked here, a third-party client only gets what the
+	// user has already consented to
+	if client.ThirdParty {
+		granted, found, err := o.authPg.GetConsent(ctx, subject.UserID, client.ID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get consent from db: %v", err)
+		}
+
+		if scopes = intersect(scopes, granted); !found || len(scopes) == 0 {
+			return nil, fmt.Errorf("%w: the user has not consented to the client", vars.ErrInvalidGrant)
+		}
+	}
+	scope = strings.Join(scopes, " ")
+
+	token, expiresAt, err := o.jProcessor.GenerateExchangedToken(subject, client.ID, scope, audience)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate access token: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventTokenExchanged, subject.UserID).
+		Str("session", subject.Session).
+		Str("client", client.ID).
+		Strs("audience", audience).
+		Str("scope", scope).
+		Msg("token exchanged")
+
+	var dpopJKT string
+	if subject.Confirmation != nil {
+		dpopJKT = subject.Confirmation.JKT
+	}
+
+	return &model.TokenResponse{
+		AccessToken:     token,
+		IssuedTokenType: vars.TokenTypeAccessToken,
+		TokenType:       tokenType(dpopJKT),
+		ExpiresIn:       int64(time.Until(expiresAt).Seconds()),
+		Scope:           scope,
+	}, nil
+}
+
+// grantSession creates the session of a user facing grant and returns its
+// tokens, with an ID token when the openid scope was granted.
+func (o *oauth) grantSession(
+	ctx context.Context,
+	email string,
+	session *model.Session,
+	id *jwtAuth.IDTokenParams,
+) (*model.TokenResponse, error) {
+	access, refresh, err := o.auth.CreateSession(ctx, email, session)
+	if err != nil {
+		if errors.Is(err, vars.ErrUserNotFound) || errors.Is(err, vars.ErrUserBanned) {
+			return nil, fmt.Errorf("%w: %v", vars.ErrInvalidGrant, err)
+		}
+
+		return nil, err
+	}
+
+	response := &model.TokenResponse{
+		AccessToken:  access,
+		TokenType:    tokenType(session.DPoPJKT),
+		ExpiresIn:    int64(o.jProcessor.AccessTTL().Seconds()),
+		RefreshToken: refresh,
+		Scope:        session.Scope,
+	}
+
+	if slices.Contains(strings.Fields(session.Scope), vars.ScopeOpenID) {
+		user, err := o.authPg.GetUser(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get user from db: %v", err)
+		}
+
+		if response.IDToken, err = o.jProcessor.GenerateIDToken(userInfo(user, session.Scope), id); err != nil {
+			return nil, fmt.Errorf("cannot generate id token: %v", err)
+		}
+	}
+
+	return response, nil
+}
+
+// userInfo filters the user claims by the openid, email and profile scopes.
+func userInfo(user *model.User, scope string) *model.UserInfo {
+	scopes := strings.Fields(scope)
+	info := &model.UserInfo{
+		Sub: user.Id,
+	}
+
+	if slices.Contains(scopes, vars.ScopeEmail) {
+		info.Email = user.Email
+		info.EmailVerified = &user.Verified
+	}
+
+	if slices.Contains(scopes, vars.ScopeProfile) {
+		info.Deployer = user.Deployer
+	}
+
+	return info
+}
+
+func tokenType(dpopJKT string) string {
+	if dpopJKT != "" {
+		return vars.TokenTypeDPoP
+	}
+
+	return vars.TokenTypeBearer
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+// loginFor starts a session of the web client whose access token is meant
+// for audience.
+func loginFor(t *testing.T, a *auth, audience, dpopJKT string) (string, string) {
+	t.Helper()
+
+	s := &model.Session{Client: "web", Audience: []string{audience}, DPoPJKT: dpopJKT}
+	access, _, err := a.CreateSession(context.Background(), "user@example.com", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s.ID, access
+}
+
+func TestExchangeToken(t *testing.T) {
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	o := &oauth{authRdb: rdb, jProcessor: a.jProcessor}
+
+	gateway, err := a.clients.Get(context.Background(), "gateway")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := a.clients.Get(context.Background(), "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, meant := loginFor(t, a, "gateway", "")
+	_, forAPI := loginFor(t, a, "api", "")
+	revokedSession, revoked := loginFor(t, a, "gateway", "")
+	delete(rdb.sessions, revokedSession)
+	_, _, refresh := login(t, a)
+
+	request := func(token string) *model.TokenExchangeRequest {
+		return &model.TokenExchangeRequest{
+			SubjectToken:     token,
+			SubjectTokenType: vars.TokenTypeAccessToken,
+			Audience:         []string{"billing"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		client *model.Client
+		r      *model.TokenExchangeRequest
+		err    error
+	}{
+		{name: "token meant for the client", client: gateway, r: request(meant)},
+		{name: "client without the grant", client: other, r: request(meant), err: vars.ErrUnauthorizedClient},
+		// the client may request the api audience, but the token is not meant for it
+		{name: "token meant for an audience of the client", client: gateway, r: request(forAPI), err: vars.ErrInvalidGrant},
+		{name: "revoked session", client: gateway, r: request(revoked), err: vars.ErrInvalidGrant},
+		{name: "refresh token", client: gateway, r: request(refresh), err: vars.ErrInvalidGrant},
+		{name: "garbage", client: gateway, r: request("garbage"), err: vars.ErrInvalidGrant},
+		{name: "other token type", client: gateway, r: &model.TokenExchangeRequest{
+			SubjectToken:     meant,
+			SubjectTokenType: "urn:ietf:params:oauth:token-type:id_token",
+		}, err: vars.ErrInvalidGrant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := o.ExchangeToken(context.Background(), tt.client, tt.r)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ExchangeToken() error = %v, want %v", err, tt.err)
+			}
+
+			if tt.err != nil {
+				return
+			}
+
+			claims, err := a.jProcessor.TokenVerify(resp.AccessToken, "billing")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// only the scopes held by both the user token and the client
+			if claims.Scope != "profile" || resp.Scope != "profile" {
+				t.Errorf("scope = %q, want profile", claims.Scope)
+			}
+
+			if claims.Actor == nil || claims.Actor.Sub != "gateway" || claims.UserID != "user-id" {
+				t.Errorf("unexpected exchanged claims: %+v", claims)
+			}
+
+			if resp.TokenType != vars.TokenTypeBearer || resp.IssuedTokenType != vars.TokenTypeAccessToken {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestExchangeTokenKeepsDPoPBinding(t *testing.T) {
+	rdb := newFakeRedis()
+	a := newTestAuth(t, rdb)
+	o := &oauth{authRdb: rdb, jProcessor: a.jProcessor}
+
+	gateway, err := a.clients.Get(context.Background(), "gateway")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, bound := loginFor(t, a, "gateway", "jkt")
+	resp, err := o.ExchangeToken(context.Background(), gateway, &model.TokenExchangeRequest{
+		SubjectToken:     bound,
+		SubjectTokenType: vars.TokenTypeAccessToken,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := a.jProcessor.TokenVerify(resp.AccessToken, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Confirmation == nil || claims.Confirmation.JKT != "jkt" || resp.TokenType != vars.TokenTypeDPoP {
+		t.Errorf("exchanged token lost its binding: %+v, %s", claims.Confirmation, resp.TokenType)
+	}
+
+	if !slices.Equal(claims.Audience, []string{"api", "billing"}) {
+		t.Errorf("audience = %v, want every audience of the client", claims.Audience)
+	}
+}
+
+const (
+	testVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+var testClient = &model.Client{
+	ID:           "web",
+	Scopes:       []string{"openid", "profile", "email"},
+	Audiences:    []string{"api"},
+	GrantTypes:   []string{vars.GrantTypeAuthorizationCode},
+	RedirectURIs: []string{"https://polonium.ws/callback"},
+}
+
+var testDeviceClient = &model.Client{
+	ID:         "cli",
+	Scopes:     []string{"openid", "profile"},
+	Audiences:  []string{"api"},
+	GrantTypes: []string{vars.GrantTypeDeviceCode},
+}
+
+func newTestOAuth(clients fakeClients, rdb *fakeRedis) *oauth {
+	return &oauth{
+		clients:   clients,
+		authRdb:   rdb,
+		codeTTL:   time.Minute,
+		deviceTTL: 10 * time.Minute,
+		interval:  5 * time.Second,
+
+		userCodeAttempts: 3,
+	}
+}
+
+// newTestDevice starts the device flow of testDeviceClient.
+func newTestDevice(t *testing.T, o *oauth) *model.DeviceAuthorizationResponse {
+	t.Helper()
+
+	response, err := o.AuthorizeDevice(context.Background(), testDeviceClient, "openid", nil, "curl", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return response
+}
+
+func TestValidateAuthorization(t *testing.T) {
+	gateway := *testClient
+	gateway.ID = "gateway"
+	gateway.GrantTypes = []string{vars.GrantTypeTokenExchange}
+
+	twoRedirects := *testClient
+	twoRedirects.ID = "two-redirects"
+	twoRedirects.RedirectURIs = []string{"https://polonium.ws/a", "https://polonium.ws/b"}
+
+	o := newTestOAuth(fakeClients{
+		testClient.ID:   testClient,
+		gateway.ID:      &gateway,
+		twoRedirects.ID: &twoRedirects,
+	}, newFakeRedis())
+
+	valid := func(change func(r *model.AuthorizationRequest)) *model.AuthorizationRequest {
+		r := &model.AuthorizationRequest{
+			ResponseType:        vars.ResponseTypeCode,
+			ClientID:            testClient.ID,
+			RedirectURI:         "https://polonium.ws/callback",
+			Scope:               "openid email",
+			CodeChallenge:       testChallenge,
+			CodeChallengeMethod: vars.CodeChallengeMethodS256,
+		}
+		if change != nil {
+			change(r)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		request *model.AuthorizationRequest
+		wantErr error
+	}{
+		{name: "valid", request: valid(nil)},
+		{name: "only redirect uri filled in", request: valid(func(r *model.AuthorizationRequest) { r.RedirectURI = "" })},
+		{name: "dpop_jkt", request: valid(func(r *model.AuthorizationRequest) { r.DPoPJKT = testChallenge })},
+		{name: "unknown client", request: valid(func(r *model.AuthorizationRequest) { r.ClientID = "other" }), wantErr: vars.ErrUnknownClient},
+		{
+			name:    "unregistered redirect uri",
+			request: valid(func(r *model.AuthorizationRequest) { r.RedirectURI = "https://evil.example/callback" }),
+			wantErr: vars.ErrInvalidRedirectURI,
+		},
+		{
+			name: "redirect uri missing with several registered",
+			request: valid(func(r *model.AuthorizationRequest) {
+				r.ClientID, r.RedirectURI = twoRedirects.ID, ""
+			}),
+			wantErr: vars.ErrInvalidRedirectURI,
+		},
+		{name: "token response type", request: valid(func(r *model.AuthorizationRequest) { r.ResponseType = "token" }), wantErr: vars.ErrUnsupportedResponseType},
+		{
+			name:    "client without the grant",
+			request: valid(func(r *model.AuthorizationRequest) { r.ClientID = gateway.ID }),
+			wantErr: vars.ErrUnauthorizedClient,
+		},
+		{name: "no code challenge", request: valid(func(r *model.AuthorizationRequest) { r.CodeChallenge = "" }), wantErr: vars.ErrInvalidRequest},
+		{
+			name: "plain code challenge",
+			request: valid(func(r *model.AuthorizationRequest) {
+				r.CodeChallenge, r.CodeChallengeMethod = testVerifier, "plain"
+			}),
+			wantErr: vars.ErrInvalidRequest,
+		},
+		{name: "malformed dpop_jkt", request: valid(func(r *model.AuthorizationRequest) { r.DPoPJKT = "jkt" }), wantErr: vars.ErrInvalidRequest},
+		{name: "unknown scope", request: valid(func(r *model.AuthorizationRequest) { r.Scope = "admin" }), wantErr: vars.ErrInvalidScope},
+		{
+			name:    "unknown audience",
+			request: valid(func(r *model.AuthorizationRequest) { r.Audience = []string{"other"} }),
+			wantErr: vars.ErrInvalidAudience,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := o.ValidateAuthorization(context.Background(), tt.request)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("ValidateAuthorization() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && tt.request.RedirectURI != "https://polonium.ws/callback" {
+				t.Errorf("redirect uri = %s", tt.request.RedirectURI)
+			}
+		})
+	}
+}
+
+func TestRedeemAuthorizationCodeRejects(t *testing.T) {
+	other := *testClient
+	other.ID = "other"
+
+	gateway := *testClient
+	gateway.GrantTypes = []string{vars.GrantTypeTokenExchange}
+
+	tests := []struct {
+		name    string
+		client  *model.Client
+		request *model.AuthorizationCodeRequest
+		wantErr error
+	}{
+		{
+			name:    "client without the grant",
+			client:  &gateway,
+			request: &model.AuthorizationCodeRequest{Code: "code", RedirectURI: "https://polonium.ws/callback", CodeVerifier: testVerifier},
+			wantErr: vars.ErrUnauthorizedClient,
+		},
+		{
+			name:    "unknown code",
+			client:  testClient,
+			request: &model.AuthorizationCodeRequest{Code: "other", RedirectURI: "https://polonium.ws/callback", CodeVerifier: testVerifier},
+			wantErr: vars.ErrInvalidGrant,
+		},
+		{
+			name:    "other client",
+			client:  &other,
+			request: &model.AuthorizationCodeRequest{Code: "code", RedirectURI: "https://polonium.ws/callback", CodeVerifier: testVerifier},
+			wantErr: vars.ErrInvalidGrant,
+		},
+		{
+			name:    "other redirect uri",
+			client:  testClient,
+			request: &model.AuthorizationCodeRequest{Code: "code", RedirectURI: "https://polonium.ws/other", CodeVerifier: testVerifier},
+			wantErr: vars.ErrInvalidGrant,
+		},
+		{
+			name:    "wrong code verifier",
+			client:  testClient,
+			request: &model.AuthorizationCodeRequest{Code: "code", RedirectURI: "https://polonium.ws/callback", CodeVerifier: testChallenge},
+			wantErr: vars.ErrInvalidGrant,
+		},
+		{
+			name:    "no code verifier",
+			client:  testClient,
+			request: &model.AuthorizationCodeRequest{Code: "code", RedirectURI: "https://polonium.ws/callback"},
+			wantErr: vars.ErrInvalidGrant,
+		},
+		{
+			name:   "other dpop key",
+			client: testClient,
+			request: &model.AuthorizationCodeRequest{
+				Code: "bound", RedirectURI: "https://polonium.ws/callback", CodeVerifier: testVerifier, DPoPJKT: "other",
+			},
+			wantErr: vars.ErrInvalidGrant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb := newFakeRedis()
+			o := newTestOAuth(fakeClients{testClient.ID: testClient}, rdb)
+			code := &model.AuthorizationCode{
+				ClientID:      testClient.ID,
+				RedirectURI:   "https://polonium.ws/callback",
+				CodeChallenge: testChallenge,
+			}
+			rdb.codes["code"] = code
+			rdb.codes["bound"] = &model.AuthorizationCode{
+				ClientID:      code.ClientID,
+				RedirectURI:   code.RedirectURI,
+				CodeChallenge: code.CodeChallenge,
+				DPoPJKT:       "jkt",
+			}
+
+			_, err := o.RedeemAuthorizationCode(context.Background(), tt.client, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RedeemAuthorizationCode() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// a failed attempt burns the code
+			if _, ok := rdb.codes[tt.request.Code]; ok && tt.wantErr != vars.ErrUnauthorizedClient {
+				t.Error("code can be redeemed again")
+			}
+		})
+	}
+}
+
+func TestDecideDevice(t *testing.T) {
+	rdb := newFakeRedis()
+	o := newTestOAuth(fakeClients{testDeviceClient.ID: testDeviceClient}, rdb)
+	device := newTestDevice(t, o)
+	ctx := context.Background()
+
+	if _, err := o.DecideDevice(ctx, device.UserCode, "", true); !errors.Is(err, vars.ErrUserCodeNotFound) {
+		t.Fatalf("DecideDevice() before the review error = %v, want %v", err, vars.ErrUserCodeNotFound)
+	}
+
+	da, client, err := o.PendingDevice(ctx, device.UserCode, "user@example.com", "10.0.0.2")
+	if err != nil {
+		t.Fatalf("PendingDevice() error = %v", err)
+	}
+	if client.ID != testDeviceClient.ID || da.IP != "10.0.0.1" || da.Ticket == "" {
+		t.Fatalf("PendingDevice() = %+v of %s", da, client.ID)
+	}
+
+	if _, err := o.DecideDevice(ctx, device.UserCode, "other", true); !errors.Is(err, vars.ErrUserCodeNotFound) {
+		t.Fatalf("DecideDevice() with another ticket error = %v, want %v", err, vars.ErrUserCodeNotFound)
+	}
+
+	decided, err := o.DecideDevice(ctx, device.UserCode, da.Ticket, true)
+	if err != nil {
+		t.Fatalf("DecideDevice() error = %v", err)
+	}
+	if decided.Status != vars.DeviceAuthorizationApproved || decided.Email != "user@example.com" || decided.Ticket != "" {
+		t.Errorf("DecideDevice() = %+v", decided)
+	}
+
+	if _, err := o.DecideDevice(ctx, device.UserCode, da.Ticket, false); !errors.Is(err, vars.ErrUserCodeNotFound) {
+		t.Errorf("second DecideDevice() error = %v, want %v", err, vars.ErrUserCodeNotFound)
+	}
+}
+
+func TestPendingDeviceLimitsUnknownCodes(t *testing.T) {
+	rdb := newFakeRedis()
+	o := newTestOAuth(fakeClients{testDeviceClient.ID: testDeviceClient}, rdb)
+	device := newTestDevice(t, o)
+	ctx := context.Background()
+
+	for i := 0; i < o.userCodeAttempts; i++ {
+		if _, _, err := o.PendingDevice(ctx, "BCDF-GHJK", "user@example.com", "10.0.0.2"); !errors.Is(err, vars.ErrUserCodeNotFound) {
+			t.Fatalf("PendingDevice() of an unknown code error = %v, want %v", err, vars.ErrUserCodeNotFound)
+		}
+	}
+
+	if _, _, err := o.PendingDevice(ctx, device.UserCode, "user@example.com", "10.0.0.2"); !errors.Is(err, vars.ErrTooManyUserCodes) {
+		t.Fatalf("PendingDevice() after too many unknown codes error = %v, want %v", err, vars.ErrTooManyUserCodes)
+	}
+
+	if _, _, err := o.PendingDevice(ctx, device.UserCode, "user@example.com", "10.0.0.3"); err != nil {
+		t.Errorf("PendingDevice() from another ip error = %v", err)
+	}
+}
+
+func TestRedeemDeviceCodeSlowDown(t *testing.T) {
+	rdb := newFakeRedis()
+	o := newTestOAuth(fakeClients{testDeviceClient.ID: testDeviceClient}, rdb)
+	device := newTestDevice(t, o)
+	ctx := context.Background()
+
+	if _, err := o.RedeemDeviceCode(ctx, testDeviceClient, device.DeviceCode, ""); !errors.Is(err, vars.ErrAuthorizationPending) {
+		t.Fatalf("RedeemDeviceCode() error = %v, want %v", err, vars.ErrAuthorizationPending)
+	}
+	if got := rdb.polls[device.DeviceCode]; got != 5*time.Second {
+		t.Errorf("first poll interval = %s, want 5s", got)
+	}
+
+	for _, want := range []time.Duration{10 * time.Second, 15 * time.Second} {
+		if _, err := o.RedeemDeviceCode(ctx, testDeviceClient, device.DeviceCode, ""); !errors.Is(err, vars.ErrSlowDown) {
+			t.Fatalf("RedeemDeviceCode() error = %v, want %v", err, vars.ErrSlowDown)
+		}
+		if got := rdb.devices[device.DeviceCode].Interval; got != want {
+			t.Errorf("interval after slow_down = %s, want %s", got, want)
+		}
+	}
+
+	// the next poll after the longer interval waits for it again
+	delete(rdb.polls, device.DeviceCode)
+	if _, err := o.RedeemDeviceCode(ctx, testDeviceClient, device.DeviceCode, ""); !errors.Is(err, vars.ErrAuthorizationPending) {
+		t.Fatalf("RedeemDeviceCode() error = %v, want %v", err, vars.ErrAuthorizationPending)
+	}
+	if got := rdb.polls[device.DeviceCode]; got != 15*time.Second {
+		t.Errorf("poll interval = %s, want 15s", got)
+	}
+}
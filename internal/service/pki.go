@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog"
+)
+
+type (
+	IPKI interface {
+		CACertificate() ([]byte, error)
+		IssueCertificate(ctx context.Context, user, deployer, deployment, csr string) (*model.CertificateResponse, error)
+		RevokeCertificates(ctx context.Context, user, deployer, deployment, reason string) (int64, error)
+		CRL(ctx context.Context) ([]byte, time.Time, error)
+		CertificateStatus(ctx context.Context, serial string) (*model.CertificateStatusResponse, error)
+	}
+
+	pki struct {
+		authPg repository.IAuthPostgres
+		ca     *jwtAuth.X509CA
+	}
+)
+
+func NewPKI(authPg repository.IAuthPostgres, ca *jwtAuth.X509CA) IPKI {
+	return &pki{
+		authPg: authPg,
+		ca:     ca,
+	}
+}
+
+func (p *pki) CACertificate() ([]byte, error) {
+	return p.ca.Certificate()
+}
+
+// IssueCertificate signs the CSR of a deployment agent. The deployment must
+// belong to the deployer of the token and must not be revoked.
+func (p *pki) IssueCertificate(
+	ctx context.Context,
+	user, deployer, deployment, csr string,
+) (*model.CertificateResponse, error) {
+	row, err := p.loadDeployment(ctx, deployer, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := p.ca.Issue(csr, row)
+	if err != nil {
+		return nil, err
+	}
+
+	caPEM, err := p.ca.Certificate()
+	if err != nil {
+		return nil, err
+	}
+
+	issued := &model.DeploymentCertificate{
+		Serial:     jwtAuth.SerialString(cert.SerialNumber),
+		Deployment: row.ID,
+		Deployer:   row.Deployer,
+		SpiffeID:   cert.URIs[0].String(),
+		NotBefore:  cert.NotBefore,
+		NotAfter:   cert.NotAfter,
+	}
+
+	// a certificate that is not recorded could never be revoked
+	if err := p.authPg.SaveCertificate(ctx, issued); err != nil {
+		return nil, fmt.Errorf("cannot save certificate: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventX509CertIssued, user).
+		Str("deployer", row.Deployer).
+		Str("deployment", row.ID).
+		Str("serial", issued.Serial).
+		Time("not_after", issued.NotAfter).
+		Msg("deployment certificate issued")
+
+	return &model.CertificateResponse{
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})),
+		CA:          string(caPEM),
+		Serial:      issued.Serial,
+		SpiffeID:    issued.SpiffeID,
+		NotAfter:    issued.NotAfter,
+	}, nil
+}
+
+func (p *pki) RevokeCertificates(
+	ctx context.Context,
+	user, deployer, deployment, reason string,
+) (int64, error) {
+	if reason == "" {
+		reason = "cessation_of_operation"
+	}
+
+	code, ok := jwtAuth.RevocationReasons[reason]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", vars.ErrInvalidRevocationReason, reason)
+	}
+
+	// certificates of a deleted deployment are already reported as revoked
+	if _, err := p.loadDeployment(ctx, deployer, deployment); err != nil &&
+		!errors.Is(err, vars.ErrDeploymentRevoked) {
+		return 0, err
+	}
+
+	revoked, err := p.authPg.RevokeCertificates(ctx, deployment, code)
+	if err != nil {
+		return 0, fmt.Errorf("cannot revoke certificates: %v", err)
+	}
+
+	authEvent(zerolog.WarnLevel, vars.EventX509CertsRevoked, user).
+		Str("deployer", deployer).
+		Str("deployment", deployment).
+		Str("reason", reason).
+		Int64("revoked", revoked).
+		Msg("deployment certificates revoked")
+
+	return revoked, nil
+}
+
+func (p *pki) CRL(ctx context.Context) ([]byte, time.Time, error) {
+	revoked, err := p.authPg.ListRevokedCertificates(ctx, jwtAuth.RevocationCessationOfOperation)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("cannot list revoked certificates: %v", err)
+	}
+
+	return p.ca.CRL(revoked)
+}
+
+// CertificateStatus is an OCSP-like answer for a single serial.
+func (p *pki) CertificateStatus(ctx context.Context, serial string) (*model.CertificateStatusResponse, error) {
+	now := time.Now()
+	status := &model.CertificateStatusResponse{
+		Serial:     serial,
+		ThisUpdate: now,
+		NextUpdate: now.Add(p.ca.StatusValidity()),
+	}
+
+	cert, err := p.authPg.GetCertificate(ctx, serial, jwtAuth.RevocationCessationOfOperation)
+	if err != nil {
+		if errors.Is(err, vars.ErrCertificateNotFound) {
+			status.Status = "unknown"
+			return status, nil
+		}
+
+		return nil, fmt.Errorf("cannot get certificate: %v", err)
+	}
+
+	switch {
+	case cert.RevokedAt != nil:
+		status.Status = "revoked"
+		status.RevokedAt = cert.RevokedAt
+		status.RevocationReason = revocationReasonName(cert.RevocationReason)
+	case now.After(cert.NotAfter):
+		status.Status = "expired"
+	default:
+		status.Status = "good"
+	}
+
+	return status, nil
+}
+
+func (p *pki) loadDeployment(ctx context.Context, deployer, deployment string) (*model.Deployment, error) {
+	row, err := p.authPg.GetDeployment(ctx, deployment)
+	if err != nil {
+		if errors.Is(err, vars.ErrDeploymentNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("cannot get deployment from db: %v", err)
+	}
+
+	// a foreign deployment is reported as missing to not disclose it
+	if row.Deployer != deployer {
+		return nil, vars.ErrDeploymentNotFound
+	}
+
+	if row.State == vars.DeploymentStateRevoked {
+		return row, vars.ErrDeploymentRevoked
+	}
+
+	return row, nil
+}
+
+func revocationReasonName(code int) string {
+	for name, c := range jwtAuth.RevocationReasons {
+		if c == code {
+			return name
+		}
+	}
+
+	return "unspecified"
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/mxmrykov/polonium-auth/pkg/utils"
+	"github.com/rs/zerolog"
+)
+
+var (
+	registrableGrantTypes = []string{
+		vars.GrantTypeAuthorizationCode,
+		vars.GrantTypeClientCredentials,
+		vars.GrantTypeDeviceCode,
+		vars.GrantTypeTokenExchange,
+	}
+
+	// registrableScopes leaves out the scopes of first-party endpoints, such
+	// as introspect and ssh:sign.
+	registrableScopes = []string{
+		vars.ScopeOpenID,
+		vars.ScopeProfile,
+		vars.ScopeEmail,
+		vars.ScopeOfflineAccess,
+	}
+
+	registrableAuthMethods = []string{
+		vars.AuthMethodNone,
+		vars.AuthMethodClientSecretBasic,
+		vars.AuthMethodClientSecretPost,
+	}
+)
+
+type (
+	// IRegistration implements dynamic client registration (RFC 7591) and
+	// its management protocol (RFC 7592). Registered clients stay pending
+	// until an admin approves them, and their tokens are only meant for
+	// themselves and the userinfo endpoint: their audience is their own id.
+	IRegistration interface {
+		Register(ctx context.Context, owner string, metadata *model.ClientMetadata) (*model.ClientRegistration, error)
+		Get(ctx context.Context, id, token string) (*model.ClientRegistration, error)
+		Update(ctx context.Context, id, token string, metadata *model.ClientMetadata) (*model.ClientRegistration, error)
+		Delete(ctx context.Context, id, token string) error
+		List(ctx context.Context, status string) ([]*model.ClientRegistration, error)
+		Review(ctx context.Context, id, admin string, approved bool) error
+	}
+
+	registration struct {
+		authPg repository.IAuthPostgres
+	}
+)
+
+func NewRegistration(authPg repository.IAuthPostgres) IRegistration {
+	return &registration{
+		authPg: authPg,
+	}
+}
+
+// Register stores a pending client owned by the user. The client secret and
+// the registration access token are only returned here.
+func (r *registration) Register(
+	ctx context.Context,
+	owner string,
+	metadata *model.ClientMetadata,
+) (*model.ClientRegistration, error) {
+	if err := validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	var secret, secretHash string
+	if metadata.TokenEndpointAuthMethod != vars.AuthMethodNone {
+		var err error
+		if secret, err = utils.NewSecret(); err != nil {
+			return nil, err
+		}
+
+		hash, err := utils.Hash(secret)
+		if err != nil {
+			return nil, fmt.Errorf("cannot hash client secret: %v", err)
+		}
+		secretHash = hash
+	}
+
+	token, err := utils.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenHash, err := utils.Hash(token)
+	if err != nil {
+		return nil, fmt.Errorf("cannot hash registration access token: %v", err)
+	}
+
+	id := uuid.New().String()
+	client := &model.RegisteredClient{
+		Client: model.Client{
+			ID:           id,
+			SecretHash:   secretHash,
+			Scopes:       strings.Fields(metadata.Scope),
+			Audiences:    []string{id},
+			GrantTypes:   metadata.GrantTypes,
+			RedirectURIs: metadata.RedirectURIs,
+		},
+		Name:                    metadata.ClientName,
+		Owner:                   owner,
+		Status:                  vars.ClientStatusPending,
+		TokenEndpointAuthMethod: metadata.TokenEndpointAuthMethod,
+		RegistrationTokenHash:   tokenHash,
+		CreateDt:                time.Now(),
+	}
+
+	if err := r.authPg.RegisterClient(ctx, client); err != nil {
+		return nil, fmt.Errorf("cannot save client: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventClientRegistered, owner).
+		Str("client", client.ID).
+		Strs("grant_types", client.GrantTypes).
+		Strs("redirect_uris", client.RedirectURIs).
+		Str("scope", metadata.Scope).
+		Msg("client registered, waiting for approval")
+
+	registered := clientRegistration(client)
+	registered.ClientSecret = secret
+	registered.RegistrationAccessToken = token
+
+	return registered, nil
+}
+
+func (r *registration) Get(ctx context.Context, id, token string) (*model.ClientRegistration, error) {
+	client, err := r.authorize(ctx, id, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientRegistration(client), nil
+}
+
+// Update replaces the client metadata. A change of the redirect uris, grant
+// types or scopes sends the client back for approval.
+func (r *registration) Update(
+	ctx context.Context,
+	id, token string,
+	metadata *model.ClientMetadata,
+) (*model.ClientRegistration, error) {
+	client, err := r.authorize(ctx, id, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.ClientID != "" && metadata.ClientID != id {
+		return nil, fmt.Errorf("%w: client_id does not match", vars.ErrInvalidClientMetadata)
+	}
+
+	if metadata.TokenEndpointAuthMethod == "" {
+		metadata.TokenEndpointAuthMethod = client.TokenEndpointAuthMethod
+	}
+
+	if metadata.TokenEndpointAuthMethod != client.TokenEndpointAuthMethod {
+		return nil, fmt.Errorf("%w: token_endpoint_auth_method cannot be changed", vars.ErrInvalidClientMetadata)
+	}
+
+	if err := validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	scopes := strings.Fields(metadata.Scope)
+	if !slices.Equal(scopes, client.Scopes) ||
+		!slices.Equal(metadata.GrantTypes, client.GrantTypes) ||
+		!slices.Equal(metadata.RedirectURIs, client.RedirectURIs) {
+		client.Status = vars.ClientStatusPending
+	}
+
+	client.Name = metadata.ClientName
+	client.Scopes = scopes
+	client.GrantTypes = metadata.GrantTypes
+	client.RedirectURIs = metadata.RedirectURIs
+
+	if err := r.authPg.UpdateRegisteredClient(ctx, client); err != nil {
+		return nil, fmt.Errorf("cannot update client: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventClientUpdated, client.Owner).
+		Str("client", client.ID).
+		Str("status", client.Status).
+		Strs("grant_types", client.GrantTypes).
+		Strs("redirect_uris", client.RedirectURIs).
+		Str("scope", metadata.Scope).
+		Msg("client updated")
+
+	return clientRegistration(client), nil
+}
+
+func (r *registration) Delete(ctx context.Context, id, token string) error {
+	client, err := r.authorize(ctx, id, token)
+	if err != nil {
+		return err
+	}
+
+	if err := r.authPg.DeleteRegisteredClient(ctx, id); err != nil {
+		return fmt.Errorf("cannot delete client: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventClientDeleted, client.Owner).
+		Str("client", client.ID).
+		Msg("client deleted")
+
+	return nil
+}
+
+func (r *registration) List(ctx context.Context, status string) ([]*model.ClientRegistration, error) {
+	clients, err := r.authPg.ListRegisteredClients(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list clients: %v", err)
+	}
+
+	registrations := make([]*model.ClientRegistration, 0, len(clients))
+	for i := range clients {
+		registrations = append(registrations, clientRegistration(&clients[i]))
+	}
+
+	return registrations, nil
+}
+
+// Review approves or rejects a registered client. Rejecting an approved
+// client disables it.
+func (r *registration) Review(ctx context.Context, id, admin string, approved bool) error {
+	status := vars.ClientStatusRejected
+	if approved {
+		status = vars.ClientStatusApproved
+	}
+
+	if err := r.authPg.ReviewClient(ctx, id, status); err != nil {
+		if errors.Is(err, vars.ErrUnknownClient) {
+			return err
+		}
+
+		return fmt.Errorf("cannot review client: %v", err)
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventClientReviewed, admin).
+		Str("client", id).
+		Str("status", status).
+		Msg("client reviewed")
+
+	return nil
+}
+
+// authorize checks the registration access token of the client. Unknown
+// clients get the same error, so that client ids cannot be probed.
+func (r *registration) authorize(ctx context.Context, id, token string) (*model.RegisteredClient, error) {
+	client, err := r.authPg.GetRegisteredClient(ctx, id)
+	if err != nil {
+		if errors.Is(err, vars.ErrUnknownClient) {
+			return nil, vars.ErrInvalidRegistrationToken
+		}
+
+		return nil, fmt.Errorf("cannot get client from db: %v", err)
+	}
+
+	if client.RegistrationTokenHash == "" || !utils.CheckHash(token, client.RegistrationTokenHash) {
+		return nil, vars.ErrInvalidRegistrationToken
+	}
+
+	return client, nil
+}
+
+// validateMetadata fills in the defaults of RFC 7591 and rejects scopes a
+// registered client cannot ask for and grants a public client cannot use.
+func validateMetadata(metadata *model.ClientMetadata) error {
+	if metadata.TokenEndpointAuthMethod == "" {
+		metadata.TokenEndpointAuthMethod = vars.AuthMethodClientSecretBasic
+	}
+
+	if !slices.Contains(registrableAuthMethods, metadata.TokenEndpointAuthMethod) {
+		return fmt.Errorf(
+			"%w: unsupported token_endpoint_auth_method %s",
+			vars.ErrInvalidClientMetadata, metadata.TokenEndpointAuthMethod,
+		)
+	}
+
+	for _, scope := range strings.Fields(metadata.Scope) {
+		if !slices.Contains(registrableScopes, scope) {
+			return fmt.Errorf("%w: unsupported scope %s", vars.ErrInvalidClientMetadata, scope)
+		}
+	}
+
+	if len(metadata.GrantTypes) == 0 {
+		metadata.GrantTypes = []string{vars.GrantTypeAuthorizationCode}
+	}
+
+	for _, grantType := range metadata.GrantTypes {
+		if !slices.Contains(registrableGrantTypes, grantType) {
+			return fmt.Errorf("%w: unsupported grant type %s", vars.ErrInvalidClientMetadata, grantType)
+		}
+
+		public := metadata.TokenEndpointAuthMethod == vars.AuthMethodNone
+		if public && (grantType == vars.GrantTypeClientCredentials || grantType == vars.GrantTypeTokenExchange) {
+			return fmt.Errorf("%w: grant type %s needs a client secret", vars.ErrInvalidClientMetadata, grantType)
+		}
+	}
+
+	if slices.Contains(metadata.GrantTypes, vars.GrantTypeAuthorizationCode) && len(metadata.RedirectURIs) == 0 {
+		return fmt.Errorf("%w: authorization_code needs redirect uris", vars.ErrInvalidClientRedirectURI)
+	}
+
+	for _, uri := range metadata.RedirectURIs {
+		if !isRedirectURIValid(uri) {
+			return fmt.Errorf("%w: %s", vars.ErrInvalidClientRedirectURI, uri)
+		}
+	}
+
+	return nil
+}
+
+// isRedirectURIValid allows https uris, and http for loopback redirects of
+// native apps (RFC 8252, section 7.3).
+func isRedirectURIValid(raw string) bool {
+	uri, err := url.Parse(raw)
+	if err != nil || uri.Host == "" || uri.User != nil || uri.Fragment != "" {
+		return false
+	}
+
+	switch uri.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := uri.Hostname()
+		return host == "localhost" || net.ParseIP(host).IsLoopback()
+	}
+
+	return false
+}
+
+func clientRegistration(client *model.RegisteredClient) *model.ClientRegistration {
+	return &model.ClientRegistration{
+		ClientMetadata: model.ClientMetadata{
+			ClientID:                client.ID,
+			RedirectURIs:            client.RedirectURIs,
+			ClientName:              client.Name,
+			GrantTypes:              client.GrantTypes,
+			Scope:                   strings.Join(client.Scopes, " "),
+			TokenEndpointAuthMethod: client.TokenEndpointAuthMethod,
+		},
+		ClientIDIssuedAt: client.CreateDt.Unix(),
+		Owner:            client.Owner,
+		Status:           client.Status,
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata model.ClientMetadata
+		wantErr  error
+	}{
+		{
+			name:     "defaults",
+			metadata: model.ClientMetadata{RedirectURIs: []string{"https://app.example/callback"}},
+		},
+		{
+			name: "registrable scopes",
+			metadata: model.ClientMetadata{
+				RedirectURIs: []string{"https://app.example/callback"},
+				Scope:        "openid profile email offline_access",
+			},
+		},
+		{
+			name: "loopback redirect of a public client",
+			metadata: model.ClientMetadata{
+				RedirectURIs:            []string{"http://127.0.0.1:8400/callback"},
+				TokenEndpointAuthMethod: vars.AuthMethodNone,
+			},
+		},
+		{
+			name: "introspect scope",
+			metadata: model.ClientMetadata{
+				RedirectURIs: []string{"https://app.example/callback"},
+				Scope:        "openid introspect",
+			},
+			wantErr: vars.ErrInvalidClientMetadata,
+		},
+		{
+			name: "ssh:sign scope",
+			metadata: model.ClientMetadata{
+				RedirectURIs: []string{"https://app.example/callback"},
+				Scope:        "ssh:sign",
+			},
+			wantErr: vars.ErrInvalidClientMetadata,
+		},
+		{
+			name: "password grant",
+			metadata: model.ClientMetadata{
+				RedirectURIs: []string{"https://app.example/callback"},
+				GrantTypes:   []string{"password"},
+			},
+			wantErr: vars.ErrInvalidClientMetadata,
+		},
+		{
+			name: "public client with client credentials",
+			metadata: model.ClientMetadata{
+				GrantTypes:              []string{vars.GrantTypeClientCredentials},
+				TokenEndpointAuthMethod: vars.AuthMethodNone,
+			},
+			wantErr: vars.ErrInvalidClientMetadata,
+		},
+		{
+			name:     "unknown auth method",
+			metadata: model.ClientMetadata{TokenEndpointAuthMethod: "private_key_jwt"},
+			wantErr:  vars.ErrInvalidClientMetadata,
+		},
+		{name: "no redirect uris", metadata: model.ClientMetadata{}, wantErr: vars.ErrInvalidClientRedirectURI},
+		{
+			name:     "http redirect",
+			metadata: model.ClientMetadata{RedirectURIs: []string{"http://app.example/callback"}},
+			wantErr:  vars.ErrInvalidClientRedirectURI,
+		},
+		{
+			name:     "redirect with a fragment",
+			metadata: model.ClientMetadata{RedirectURIs: []string{"https://app.example/callback#token"}},
+			wantErr:  vars.ErrInvalidClientRedirectURI,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMetadata(&tt.metadata)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("validateMetadata() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterOwnAudience(t *testing.T) {
+	authPg := newFakePostgres()
+	registered, err := NewRegistration(authPg).Register(context.Background(), "owner@polonium.ws", &model.ClientMetadata{
+		RedirectURIs: []string{"https://app.example/callback"},
+		Scope:        "openid",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := authPg.clients[registered.ClientID]
+	if client == nil || !slices.Equal(client.Audiences, []string{registered.ClientID}) {
+		t.Fatalf("registered client = %+v, want its id as its only audience", client)
+	}
+
+	if client.Status != vars.ClientStatusPending || registered.ClientSecret == "" || registered.RegistrationAccessToken == "" {
+		t.Errorf("registration = %+v", registered)
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/config"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/mxmrykov/polonium-auth/pkg/utils"
+	"github.com/rs/zerolog"
+)
+
+type (
+	// ISAML is a SAML 2.0 identity provider for SP-initiated logins. Every
+	// tenant is an IdP entity of its own with its own service providers.
+	// AuthnRequest signatures are not checked: assertions only ever go to
+	// the assertion consumer services registered for the service provider.
+	ISAML interface {
+		Metadata(tenant string) ([]byte, error)
+		ParseRequest(tenant, request string, deflated bool) (*jwtAuth.SAMLAuthnRequest, error)
+		Respond(ctx context.Context, tenant, request string, deflated bool, email, ip string) (string, string, error)
+	}
+
+	saml struct {
+		authPg  repository.IAuthPostgres
+		idp     *jwtAuth.SAMLIdP
+		tenants map[string]map[string]config.SAMLServiceProvider
+		baseURL string
+	}
+)
+
+func NewSAML(authPg repository.IAuthPostgres, idp *jwtAuth.SAMLIdP, cfg config.SAML, baseURL string) ISAML {
+	tenants := make(map[string]map[string]config.SAMLServiceProvider, len(cfg.Tenants))
+	for _, tenant := range cfg.Tenants {
+		sps := make(map[string]config.SAMLServiceProvider, len(tenant.ServiceProviders))
+		for _, sp := range tenant.ServiceProviders {
+			sps[sp.EntityID] = sp
+		}
+		tenants[tenant.ID] = sps
+	}
+
+	return &saml{
+		authPg:  authPg,
+		idp:     idp,
+		tenants: tenants,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (s *saml) Metadata(tenant string) ([]byte, error) {
+	if _, ok := s.tenants[tenant]; !ok {
+		return nil, vars.ErrUnknownSAMLTenant
+	}
+
+	return s.idp.Metadata(s.entityID(tenant), s.baseURL+fmt.Sprintf(vars.PathSAMLSSO, tenant))
+}
+
+// ParseRequest decodes the AuthnRequest and resolves the assertion consumer
+// service to answer to. A request without one is answered at the first
+// service registered for the service provider.
+func (s *saml) ParseRequest(tenant, request string, deflated bool) (*jwtAuth.SAMLAuthnRequest, error) {
+	sps, ok := s.tenants[tenant]
+	if !ok {
+		return nil, vars.ErrUnknownSAMLTenant
+	}
+
+	r, err := jwtAuth.ParseAuthnRequest(request, deflated)
+	if err != nil {
+		return nil, err
+	}
+
+	sp, ok := sps[r.Issuer]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", vars.ErrUnknownServiceProvider, r.Issuer)
+	}
+
+	if r.Destination != "" && r.Destination != s.baseURL+fmt.Sprintf(vars.PathSAMLSSO, tenant) {
+		return nil, fmt.Errorf("%w: unexpected destination %s", vars.ErrInvalidSAMLRequest, r.Destination)
+	}
+
+	switch {
+	case r.ACSURL == "":
+		r.ACSURL = sp.ACSURLs[0]
+	case !slices.Contains(sp.ACSURLs, r.ACSURL):
+		return nil, fmt.Errorf("%w: %s", vars.ErrInvalidACSURL, r.ACSURL)
+	}
+
+	return r, nil
+}
+
+// Respond returns the assertion consumer service url and the signed
+// SAMLResponse for the user who has just logged in.
+func (s *saml) Respond(
+	ctx context.Context,
+	tenant, request string,
+	deflated bool,
+	email, ip string,
+) (string, string, error) {
+	r, err := s.ParseRequest(tenant, request, deflated)
+	if err != nil {
+		return "", "", err
+	}
+
+	user, err := s.authPg.GetUser(ctx, email)
+	if err != nil {
+		if errors.Is(err, vars.ErrUserNotFound) {
+			return "", "", err
+		}
+
+		return "", "", fmt.Errorf("cannot get user from db: %v", err)
+	}
+
+	if user.Banned {
+		return "", "", vars.ErrUserBanned
+	}
+
+	sp := s.tenants[tenant][r.Issuer]
+	response, err := s.idp.Response(&jwtAuth.SAMLAssertion{
+		Issuer:       s.entityID(tenant),
+		Audience:     r.Issuer,
+		Recipient:    r.ACSURL,
+		InResponseTo: r.ID,
+		NameID:       user.Email,
+		SessionIndex: utils.NewSession(),
+		AuthnInstant: time.Now(),
+		AuthnContext: sp.AuthnContext,
+		Attributes: []jwtAuth.SAMLAttribute{
+			{Name: "email", Values: []string{user.Email}},
+			{Name: "uid", Values: []string{user.Id}},
+		},
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventSAMLAssertionIssued, user.Id).
+		Str("tenant", tenant).
+		Str("sp", r.Issuer).
+		Str("acs", r.ACSURL).
+		Str("ip", ip).
+		Msg("saml assertion issued")
+
+	return r.ACSURL, response, nil
+}
+
+// entityID of a tenant is the url of its metadata, as is customary.
+func (s *saml) entityID(tenant string) string {
+	return s.baseURL + fmt.Sprintf(vars.PathSAMLMetadata, tenant)
+}
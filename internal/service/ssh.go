@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/model"
+	"github.com/mxmrykov/polonium-auth/internal/repository"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/ssh"
+)
+
+// minRSAKeyBits is the smallest RSA key the CA signs.
+const minRSAKeyBits = 2048
+
+type (
+	ISSH interface {
+		CAPublicKey() (string, error)
+		SignUserKey(ctx context.Context, email, publicKey string, sourceAddress []string) (*model.SSHCertificateResponse, error)
+	}
+
+	sshCA struct {
+		authPg repository.IAuthPostgres
+		ca     *jwtAuth.SSHCA
+	}
+)
+
+func NewSSH(authPg repository.IAuthPostgres, ca *jwtAuth.SSHCA) ISSH {
+	return &sshCA{
+		authPg: authPg,
+		ca:     ca,
+	}
+}
+
+func (s *sshCA) CAPublicKey() (string, error) {
+	return s.ca.PublicKey()
+}
+
+// SignUserKey issues a short-lived certificate for the public key in
+// authorized_keys format. DSA and short RSA keys are refused. The user is
+// reloaded so that a ban takes effect before the access token expires.
+func (s *sshCA) SignUserKey(
+	ctx context.Context,
+	email, publicKey string,
+	sourceAddress []string,
+) (*model.SSHCertificateResponse, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", vars.ErrInvalidPublicKey, err)
+	}
+
+	if err = checkKeyStrength(key); err != nil {
+		return nil, err
+	}
+
+	user, err := s.authPg.GetUser(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get user from db: %w", err)
+	}
+
+	if user.Banned {
+		return nil, vars.ErrUserBanned
+	}
+
+	cert, err := s.ca.Sign(user, key, sourceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	authEvent(zerolog.InfoLevel, vars.EventSSHCertIssued, user.Id).
+		Uint64("serial", cert.Serial).
+		Str("key", ssh.FingerprintSHA256(key)).
+		Strs("principals", cert.ValidPrincipals).
+		Strs("source_address", sourceAddress).
+		Msg("ssh certificate issued")
+
+	return &model.SSHCertificateResponse{
+		Certificate: string(ssh.MarshalAuthorizedKey(cert)),
+		Serial:      cert.Serial,
+		Principals:  cert.ValidPrincipals,
+		ValidBefore: int64(cert.ValidBefore),
+	}, nil
+}
+
+func checkKeyStrength(key ssh.PublicKey) error {
+	switch key.Type() {
+	case ssh.InsecureKeyAlgoDSA:
+		return fmt.Errorf("%w: dsa keys are not supported", vars.ErrInvalidPublicKey)
+	case ssh.KeyAlgoRSA:
+		cryptoKey, ok := key.(ssh.CryptoPublicKey)
+		if !ok {
+			return fmt.Errorf("%w: unreadable rsa key", vars.ErrInvalidPublicKey)
+		}
+
+		rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: unreadable rsa key", vars.ErrInvalidPublicKey)
+		}
+
+		if bits := rsaKey.N.BitLen(); bits < minRSAKeyBits {
+			return fmt.Errorf("%w: rsa key of %d bits, at least %d required", vars.ErrInvalidPublicKey, bits, minRSAKeyBits)
+		}
+	}
+
+	return nil
+}
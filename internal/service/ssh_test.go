@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"crypto/dsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	jwtAuth "github.com/mxmrykov/polonium-auth/internal/auth"
+	"github.com/mxmrykov/polonium-auth/internal/vars"
+	"golang.org/x/crypto/ssh"
+)
+
+func authorizedKey(t *testing.T, public any) string {
+	t.Helper()
+
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(ssh.MarshalAuthorizedKey(key))
+}
+
+func rsaKey(t *testing.T, bits int) string {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return authorizedKey(t, &private.PublicKey)
+}
+
+func TestSignUserKey(t *testing.T) {
+	ca := jwtAuth.NewSSHCA(&fakeVault{}, time.Hour)
+	if err := ca.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s := NewSSH(newFakePostgres(), ca)
+
+	ed25519Public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the parameters only need the size ssh accepts, the key is refused
+	// before anything is computed with it
+	p := new(big.Int).Lsh(big.NewInt(1), 1023)
+	dsaKey := authorizedKey(t, &dsa.PublicKey{
+		Parameters: dsa.Parameters{P: p.Add(p, big.NewInt(1)), Q: big.NewInt(7), G: big.NewInt(2)},
+		Y:          big.NewInt(3),
+	})
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{name: "ed25519", key: authorizedKey(t, ed25519Public)},
+		{name: "rsa 2048", key: rsaKey(t, 2048)},
+		{name: "rsa 1024", key: rsaKey(t, 1024), wantErr: vars.ErrInvalidPublicKey},
+		{name: "dsa", key: dsaKey, wantErr: vars.ErrInvalidPublicKey},
+		{name: "malformed", key: "ssh-ed25519 AAAA", wantErr: vars.ErrInvalidPublicKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.SignUserKey(context.Background(), "user@example.com", tt.key, nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SignUserKey() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SignUserKey() error = %v", err)
+			}
+
+			parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(resp.Certificate))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := parsed.(*ssh.Certificate); !ok {
+				t.Errorf("SignUserKey() returned %T, want a certificate", parsed)
+			}
+		})
+	}
+}
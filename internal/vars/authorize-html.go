@@ -0,0 +1,37 @@
+package vars
+
+const (
+	AuthorizeForm = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Sign in to {{.ClientID}}</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Segoe UI', Arial, sans-serif; background-color: #f6f9fc;">
+    <div style="max-width: 400px; margin: 80px auto; background-color: #ffffff; border-radius: 12px; box-shadow: 0 4px 12px rgba(0,0,0,0.1); padding: 40px;">
+        <h1 style="color: #333333; margin: 0 0 10px 0; font-size: 24px; font-weight: 600;">Sign in</h1>
+        <p style="color: #666666; margin: 0 0 30px 0; font-size: 15px;"><b>{{.ClientID}}</b> asks for access to your account{{if .Scope}} ({{.Scope}}){{end}}.</p>
+        {{if .Error}}<p style="color: #c0392b; margin: 0 0 20px 0; font-size: 14px;">{{.Error}}</p>{{end}}
+        <form method="post" action="{{.Action}}">
+            <input type="hidden" name="response_type" value="{{.ResponseType}}">
+            <input type="hidden" name="client_id" value="{{.ClientID}}">
+            <input type="hidden" name="redirect_uri" value="{{.RedirectURI}}">
+            <input type="hidden" name="scope" value="{{.Scope}}">
+            {{range .Audience}}<input type="hidden" name="audience" value="{{.}}">{{end}}
+            <input type="hidden" name="state" value="{{.State}}">
+            <input type="hidden" name="code_challenge" value="{{.CodeChallenge}}">
+            <input type="hidden" name="code_challenge_method" value="{{.CodeChallengeMethod}}">
+            <input type="hidden" name="dpop_jkt" value="{{.DPoPJKT}}">
+            <input type="hidden" name="nonce" value="{{.Nonce}}">
+            <input type="email" name="email" placeholder="Email" required autocomplete="username" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 12px; border: 1px solid #dddddd; border-radius: 6px;">
+            <input type="password" name="pwd" placeholder="Password" required autocomplete="current-password" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 12px; border: 1px solid #dddddd; border-radius: 6px;">
+            <input type="text" name="code" placeholder="Authenticator code" required inputmode="numeric" autocomplete="one-time-code" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 24px; border: 1px solid #dddddd; border-radius: 6px;">
+            <button type="submit" style="width: 100%; padding: 12px; border: 0; border-radius: 6px; background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: #ffffff; font-size: 16px; font-weight: 600;">Continue</button>
+        </form>
+    </div>
+</body>
+</html>
+`
+)
@@ -0,0 +1,31 @@
+package vars
+
+const (
+	ConsentForm = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Allow {{.ClientName}}</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Segoe UI', Arial, sans-serif; background-color: #f6f9fc;">
+    <div style="max-width: 400px; margin: 80px auto; background-color: #ffffff; border-radius: 12px; box-shadow: 0 4px 12px rgba(0,0,0,0.1); padding: 40px;">
+        <h1 style="color: #333333; margin: 0 0 10px 0; font-size: 24px; font-weight: 600;">Allow access</h1>
+        <p style="color: #666666; margin: 0 0 20px 0; font-size: 15px;"><b>{{.ClientName}}</b> is not a polonium app and asks for access to the account <b>{{.Email}}</b>.</p>
+        {{if .Scopes}}
+        <p style="color: #666666; margin: 0 0 10px 0; font-size: 15px;">It will be allowed to use:</p>
+        <ul style="color: #333333; margin: 0 0 30px 0; padding-left: 20px; font-size: 15px;">
+            {{range .Scopes}}<li>{{.}}</li>{{end}}
+        </ul>
+        {{end}}
+        <form method="post" action="{{.Action}}">
+            <input type="hidden" name="consent_id" value="{{.ID}}">
+            <button type="submit" name="decision" value="approve" style="width: 100%; padding: 12px; margin-bottom: 12px; border: 0; border-radius: 6px; background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: #ffffff; font-size: 16px; font-weight: 600;">Allow</button>
+            <button type="submit" name="decision" value="deny" style="width: 100%; padding: 12px; border: 1px solid #dddddd; border-radius: 6px; background: #ffffff; color: #666666; font-size: 16px;">Deny</button>
+        </form>
+    </div>
+</body>
+</html>
+`
+)
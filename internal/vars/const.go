@@ -5,6 +5,115 @@ const (
 )
 
 const (
-	HeaderAuthorization = "Authorization"
-	CookiePoloniumAuth  = "po-auth"
+	MFAMethodTOTP      = "totp"
+	MFAMethodFederated = "federated"
+)
+
+// Authentication methods (RFC 8176) and context class of a password and
+// TOTP login.
+const (
+	AMRPassword = "pwd"
+	AMROTP      = "otp"
+	AMRMFA      = "mfa"
+	ACRMFA      = "http://schemas.openid.net/pape/policies/2007/06/multi-factor"
+)
+
+const (
+	EventRefreshTokenReuse    = "refresh_token_reuse"
+	EventRefreshFamilyRevoked = "refresh_family_revoked"
+	EventSessionRevoked       = "session_revoked"
+	EventLogout               = "logout"
+	EventLogoutAll            = "logout_all"
+	EventTokenRevoked         = "token_revoked"
+	EventTokenExchanged       = "token_exchanged"
+	EventSSHCertIssued        = "ssh_cert_issued"
+	EventX509CertIssued       = "x509_cert_issued"
+	EventX509CertsRevoked     = "x509_certs_revoked"
+	EventAuthCodeIssued       = "authorization_code_issued"
+	EventClientTokenIssued    = "client_token_issued"
+	EventDeviceApproved       = "device_authorization_approved"
+	EventDeviceDenied         = "device_authorization_denied"
+	EventClientRegistered     = "client_registered"
+	EventClientUpdated        = "client_updated"
+	EventClientDeleted        = "client_deleted"
+	EventClientReviewed       = "client_reviewed"
+	EventConsentGranted       = "consent_granted"
+	EventConsentDenied        = "consent_denied"
+	EventConsentRevoked       = "consent_revoked"
+	EventFederatedLogin       = "federated_login"
+	EventFederatedLinked      = "federated_identity_linked"
+	EventFederatedSignup      = "federated_signup"
+	EventSAMLAssertionIssued  = "saml_assertion_issued"
+)
+
+const (
+	HeaderAuthorization   = "Authorization"
+	HeaderWWWAuthenticate = "WWW-Authenticate"
+	HeaderDPoP            = "DPoP"
+	HeaderDPoPNonce       = "DPoP-Nonce"
+	CookiePoloniumAuth    = "po-auth"
+
+	ContextClaims  = "claims"
+	ContextClient  = "client"
+	ContextDPoPJKT = "dpop_jkt"
+)
+
+const (
+	ScopeIntrospect    = "introspect"
+	ScopeOpenID        = "openid"
+	ScopeEmail         = "email"
+	ScopeProfile       = "profile"
+	ScopeOfflineAccess = "offline_access"
+	ScopeSSHSign       = "ssh:sign"
+)
+
+const (
+	DeploymentStateRevoked = "revoked"
+)
+
+const (
+	ClientStatusPending  = "pending"
+	ClientStatusApproved = "approved"
+	ClientStatusRejected = "rejected"
+)
+
+const (
+	DeviceAuthorizationPending  = "pending"
+	DeviceAuthorizationApproved = "approved"
+	DeviceAuthorizationDenied   = "denied"
+)
+
+const (
+	GrantTypeTokenExchange      = "urn:ietf:params:oauth:grant-type:token-exchange"
+	GrantTypeAuthorizationCode  = "authorization_code"
+	GrantTypeClientCredentials  = "client_credentials"
+	GrantTypeDeviceCode         = "urn:ietf:params:oauth:grant-type:device_code"
+	TokenTypeAccessToken        = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeBearer             = "Bearer"
+	TokenTypeDPoP               = "DPoP"
+	ResponseTypeCode            = "code"
+	CodeChallengeMethodS256     = "S256"
+	AuthMethodNone              = "none"
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+)
+
+const (
+	PathJWKS                     = "/.well-known/jwks.json"
+	PathOpenIDConfiguration      = "/.well-known/openid-configuration"
+	PathOAuthRevoke              = "/ext-auth/api/v1/oauth/revoke"
+	PathOAuthToken               = "/ext-auth/api/v1/oauth/token"
+	PathOAuthAuthorize           = "/ext-auth/api/v1/oauth/authorize"
+	PathOAuthConsent             = "/ext-auth/api/v1/oauth/authorize/consent"
+	PathOAuthUserInfo            = "/ext-auth/api/v1/oauth/userinfo"
+	PathOAuthDeviceAuthorization = "/ext-auth/api/v1/oauth/device_authorization"
+	PathOAuthRegister            = "/ext-auth/api/v1/oauth/register"
+	PathOAuthDevice              = "/ext-auth/api/v1/oauth/device"
+	PathFederationProviders      = "/ext-auth/api/v1/federation/providers"
+	PathFederationLogin          = "/ext-auth/api/v1/federation/%s/login"
+	PathFederationCallback       = "/ext-auth/api/v1/federation/%s/callback"
+	PathSAMLMetadata             = "/ext-auth/api/v1/saml/%s/metadata"
+	PathSAMLSSO                  = "/ext-auth/api/v1/saml/%s/sso"
+	PathSAMLLogin                = "/ext-auth/api/v1/saml/%s/sso/login"
+	PathPKICRL                   = "/ext-auth/api/v1/pki/crl"
 )
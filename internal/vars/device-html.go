@@ -0,0 +1,46 @@
+package vars
+
+const (
+	DeviceForm = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Connect a device</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Segoe UI', Arial, sans-serif; background-color: #f6f9fc;">
+    <div style="max-width: 400px; margin: 80px auto; background-color: #ffffff; border-radius: 12px; box-shadow: 0 4px 12px rgba(0,0,0,0.1); padding: 40px;">
+        <h1 style="color: #333333; margin: 0 0 10px 0; font-size: 24px; font-weight: 600;">Connect a device</h1>
+        {{if .Message}}
+        <p style="color: #666666; margin: 0; font-size: 15px;">{{.Message}}</p>
+        {{else if .Ticket}}
+        <p style="color: #666666; margin: 0 0 20px 0; font-size: 15px;"><b>{{.ClientName}}</b> asks for access to your account from a device at {{.DeviceIP}}. Only continue if you started this on your own device.</p>
+        {{if .Scopes}}
+        <p style="color: #666666; margin: 0 0 10px 0; font-size: 15px;">It will be allowed to use:</p>
+        <ul style="color: #333333; margin: 0 0 30px 0; padding-left: 20px; font-size: 15px;">
+            {{range .Scopes}}<li>{{.}}</li>{{end}}
+        </ul>
+        {{end}}
+        <form method="post" action="{{.Action}}">
+            <input type="hidden" name="user_code" value="{{.UserCode}}">
+            <input type="hidden" name="ticket" value="{{.Ticket}}">
+            <button type="submit" name="decision" value="approve" style="width: 100%; padding: 12px; margin-bottom: 12px; border: 0; border-radius: 6px; background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: #ffffff; font-size: 16px; font-weight: 600;">Connect</button>
+            <button type="submit" name="decision" value="deny" style="width: 100%; padding: 12px; border: 1px solid #dddddd; border-radius: 6px; background: #ffffff; color: #666666; font-size: 16px;">Deny</button>
+        </form>
+        {{else}}
+        <p style="color: #666666; margin: 0 0 30px 0; font-size: 15px;">Enter the code shown on your device and log in to see what asks for access to your account.</p>
+        {{if .Error}}<p style="color: #c0392b; margin: 0 0 20px 0; font-size: 14px;">{{.Error}}</p>{{end}}
+        <form method="post" action="{{.Action}}">
+            <input type="text" name="user_code" value="{{.UserCode}}" placeholder="XXXX-XXXX" required autocomplete="off" autocapitalize="characters" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 12px; border: 1px solid #dddddd; border-radius: 6px; font-size: 18px; letter-spacing: 4px; text-align: center;">
+            <input type="email" name="email" placeholder="Email" required autocomplete="username" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 12px; border: 1px solid #dddddd; border-radius: 6px;">
+            <input type="password" name="pwd" placeholder="Password" required autocomplete="current-password" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 12px; border: 1px solid #dddddd; border-radius: 6px;">
+            <input type="text" name="code" placeholder="Authenticator code" required inputmode="numeric" autocomplete="one-time-code" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 24px; border: 1px solid #dddddd; border-radius: 6px;">
+            <button type="submit" style="width: 100%; padding: 12px; border: 0; border-radius: 6px; background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: #ffffff; font-size: 16px; font-weight: 600;">Continue</button>
+        </form>
+        {{end}}
+    </div>
+</body>
+</html>
+`
+)
@@ -12,4 +12,57 @@ var (
 	ErrNoSuchVariableInVault       = errors.New("no such variable in vault")
 	ErrIncorrectPwd                = errors.New("incorrect password")
 	ErrUserAlreadyVerified         = errors.New("user is already verified")
+	ErrUserBanned                  = errors.New("user is banned")
+	ErrSessionNotFound             = errors.New("session does not exist or expired")
+	ErrInvalidRefreshToken         = errors.New("invalid refresh token")
+	ErrRefreshFamilyNotFound       = errors.New("refresh token family does not exist or expired")
+	ErrRefreshFamilyRevoked        = errors.New("refresh token family is revoked")
+	ErrRefreshTokenReused          = errors.New("refresh token reuse detected")
+	ErrTokenRevoked                = errors.New("token is revoked")
+	ErrRevocationCheckFailed       = errors.New("cannot check token revocation")
+	ErrInvalidClient               = errors.New("invalid client credentials")
+	ErrUnknownClient               = errors.New("unknown client")
+	ErrInvalidScope                = errors.New("requested scope is not allowed for client")
+	ErrInvalidAudience             = errors.New("requested audience is not allowed for client")
+	ErrInsufficientScope           = errors.New("token is missing required scope")
+	ErrUnauthorizedClient          = errors.New("client is not allowed to use grant type")
+	ErrInvalidDPoPProof            = errors.New("invalid dpop proof")
+	ErrDPoPProofReplayed           = errors.New("dpop proof is already used")
+	ErrUseDPoPNonce                = errors.New("dpop proof must carry a server nonce")
+	ErrDPoPKeyMismatch             = errors.New("dpop proof key does not match the token binding")
+	ErrUnsupportedGrantType        = errors.New("unsupported grant type")
+	ErrInvalidGrant                = errors.New("grant is invalid, expired or revoked")
+	ErrInvalidPublicKey            = errors.New("invalid ssh public key")
+	ErrInvalidSourceAddress        = errors.New("invalid certificate source address")
+	ErrInvalidCSR                  = errors.New("invalid certificate signing request")
+	ErrDeploymentNotFound          = errors.New("deployment does not exist")
+	ErrDeploymentRevoked           = errors.New("deployment is revoked")
+	ErrCertificateNotFound         = errors.New("certificate does not exist")
+	ErrInvalidRevocationReason     = errors.New("invalid revocation reason")
+	ErrInvalidRedirectURI          = errors.New("redirect uri is not registered for client")
+	ErrInvalidRequest              = errors.New("invalid authorization request")
+	ErrUnsupportedResponseType     = errors.New("unsupported response type")
+	ErrAuthCodeNotFound            = errors.New("authorization code does not exist or expired")
+	ErrDeviceCodeNotFound          = errors.New("device code does not exist or expired")
+	ErrUserCodeNotFound            = errors.New("user code does not exist or expired")
+	ErrTooManyUserCodes            = errors.New("too many unknown user codes entered")
+	ErrAuthorizationPending        = errors.New("authorization is pending")
+	ErrSlowDown                    = errors.New("device polls too often")
+	ErrAccessDenied                = errors.New("user denied the authorization")
+	ErrInvalidClientMetadata       = errors.New("invalid client metadata")
+	ErrInvalidClientRedirectURI    = errors.New("invalid client redirect uri")
+	ErrInvalidRegistrationToken    = errors.New("invalid registration access token")
+	ErrConsentNotFound             = errors.New("consent does not exist")
+	ErrConsentRequestNotFound      = errors.New("consent request does not exist or expired")
+	ErrExpiredToken                = errors.New("device code is expired")
+	ErrFederatedIdentityNotFound   = errors.New("federated identity is not linked")
+	ErrUnknownFederationProvider   = errors.New("federation provider does not exist")
+	ErrFederationStateNotFound     = errors.New("federation state does not exist or expired")
+	ErrFederatedEmailUnverified    = errors.New("upstream email is missing or not verified")
+	ErrFederatedDomainNotAllowed   = errors.New("email domain is not allowed for federation provider")
+	ErrFederatedAccountExists      = errors.New("federation provider cannot link existing accounts")
+	ErrInvalidSAMLRequest          = errors.New("invalid saml authn request")
+	ErrUnknownSAMLTenant           = errors.New("saml tenant does not exist")
+	ErrUnknownServiceProvider      = errors.New("saml service provider is not registered for tenant")
+	ErrInvalidACSURL               = errors.New("assertion consumer service url is not registered for service provider")
 )
@@ -6,5 +6,23 @@ const (
 	UsersGlobalLoginPwd = "users/global/login/pwd/%s"
 	UsersTOTPCodes      = "users/totp/codes/%s"
 
-	AuthSessionsUsers = "auth/sessions/users/%s"
+	AuthSessions         = "auth/sessions/%s"
+	AuthSessionsUsers    = "auth/sessions/users/%s"
+	AuthRefreshFamilies  = "auth/refresh/families/%s"
+	AuthRevokedTokens    = "auth/revoked/jti/%s"
+	AuthDPoPProofs       = "auth/dpop/jti/%s"
+	AuthDPoPNonces       = "auth/dpop/nonces/%s"
+	AuthOAuthCodes       = "auth/oauth/codes/%s"
+	AuthOAuthDeviceCodes = "auth/oauth/device/codes/%s"
+	AuthOAuthUserCodes   = "auth/oauth/device/user-codes/%s"
+	AuthOAuthConsents    = "auth/oauth/consents/%s"
+	AuthOAuthDevicePolls = "auth/oauth/device/polls/%s"
+	AuthOAuthUserCodeIPs = "auth/oauth/device/user-code-failures/%s"
+	AuthFederationStates = "auth/federation/states/%s"
+
+	AuthJWTSigningKeys  = "auth/jwt/signing-keys/%s"
+	AuthJWEAudienceKeys = "auth/jwe/audience-keys/%s"
+	AuthSSHCAKey        = "auth/ssh/ca"
+	AuthX509CA          = "auth/pki/ca"
+	AuthSAMLKey         = "auth/saml/idp"
 )
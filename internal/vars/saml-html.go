@@ -0,0 +1,47 @@
+package vars
+
+const (
+	SAMLLoginForm = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Sign in to {{.ServiceProvider}}</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Segoe UI', Arial, sans-serif; background-color: #f6f9fc;">
+    <div style="max-width: 400px; margin: 80px auto; background-color: #ffffff; border-radius: 12px; box-shadow: 0 4px 12px rgba(0,0,0,0.1); padding: 40px;">
+        <h1 style="color: #333333; margin: 0 0 10px 0; font-size: 24px; font-weight: 600;">Sign in</h1>
+        <p style="color: #666666; margin: 0 0 30px 0; font-size: 15px;">Sign in to continue to <b>{{.ServiceProvider}}</b>.</p>
+        {{if .Error}}<p style="color: #c0392b; margin: 0 0 20px 0; font-size: 14px;">{{.Error}}</p>{{end}}
+        <form method="post" action="{{.Action}}">
+            <input type="hidden" name="SAMLRequest" value="{{.SAMLRequest}}">
+            <input type="hidden" name="RelayState" value="{{.RelayState}}">
+            <input type="hidden" name="binding" value="{{.Binding}}">
+            <input type="email" name="email" placeholder="Email" required autocomplete="username" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 12px; border: 1px solid #dddddd; border-radius: 6px;">
+            <input type="password" name="pwd" placeholder="Password" required autocomplete="current-password" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 12px; border: 1px solid #dddddd; border-radius: 6px;">
+            <input type="text" name="code" placeholder="Authenticator code" required inputmode="numeric" autocomplete="one-time-code" style="width: 100%; box-sizing: border-box; padding: 12px; margin-bottom: 24px; border: 1px solid #dddddd; border-radius: 6px;">
+            <button type="submit" style="width: 100%; padding: 12px; border: 0; border-radius: 6px; background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: #ffffff; font-size: 16px; font-weight: 600;">Continue</button>
+        </form>
+    </div>
+</body>
+</html>
+`
+
+	SAMLPostForm = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Signing in</title>
+</head>
+<body onload="document.forms[0].submit()">
+    <form method="post" action="{{.ACSURL}}">
+        <input type="hidden" name="SAMLResponse" value="{{.SAMLResponse}}">
+        {{if .RelayState}}<input type="hidden" name="RelayState" value="{{.RelayState}}">{{end}}
+        <noscript><button type="submit">Continue</button></noscript>
+    </form>
+</body>
+</html>
+`
+)
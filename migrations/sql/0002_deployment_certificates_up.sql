@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+create table deployment_certificate (
+    serial text primary key,
+    deployment_id text not null,
+    deployer text not null,
+    spiffe_id text not null,
+    not_before timestamptz not null,
+    not_after timestamptz not null,
+    revoked_at timestamptz,
+    revocation_reason smallint,
+    create_dt timestamptz default now()
+);
+
+create index deployment_certificate_deployment_idx on deployment_certificate (deployment_id);
+create index deployment_certificate_not_after_idx on deployment_certificate (not_after);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE deployment_certificate;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+create table oauth_clients (
+    id text primary key,
+    secret_hash text not null default '',
+    scopes text[] not null default '{}',
+    audiences text[] not null default '{}',
+    grant_types text[] not null default '{}',
+    redirect_uris text[] not null default '{}',
+    create_dt timestamptz default now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE oauth_clients;
+-- +goose StatementEnd
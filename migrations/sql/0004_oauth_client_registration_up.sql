@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table oauth_clients
+    add column name text not null default '',
+    add column owner text not null default '',
+    add column status text not null default 'approved',
+    add column token_endpoint_auth_method text not null default 'client_secret_basic',
+    add column registration_token_hash text not null default '',
+    add column update_dt timestamptz default now();
+
+create index oauth_clients_status_idx on oauth_clients (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX oauth_clients_status_idx;
+
+alter table oauth_clients
+    drop column name,
+    drop column owner,
+    drop column status,
+    drop column token_endpoint_auth_method,
+    drop column registration_token_hash,
+    drop column update_dt;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+create table oauth_consents (
+    user_id text not null,
+    client_id text not null references oauth_clients (id) on delete cascade,
+    scopes text[] not null default '{}',
+    create_dt timestamptz default now(),
+    update_dt timestamptz default now(),
+    primary key (user_id, client_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE oauth_consents;
+-- +goose StatementEnd
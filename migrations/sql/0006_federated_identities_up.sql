@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+create table federated_identities (
+    provider text not null,
+    subject text not null,
+    user_email text not null references users (email) on delete cascade,
+    email text not null default '',
+    create_dt timestamptz default now(),
+    last_login_dt timestamptz default now(),
+    primary key (provider, subject),
+    unique (provider, user_email)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE federated_identities;
+-- +goose StatementEnd
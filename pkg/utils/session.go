@@ -1,7 +1,12 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math/big"
 	"strings"
+	"unicode"
 
 	"github.com/google/uuid"
 )
@@ -9,3 +14,55 @@ import (
 func NewSession() string {
 	return strings.Replace(uuid.New().String(), "-", "", -1)
 }
+
+func NewTokenID() string {
+	return uuid.New().String()
+}
+
+// NewSecret returns 256 random bits, url-safe and short enough for bcrypt,
+// for the values that are credentials on their own, such as authorization
+// codes and client secrets.
+func NewSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("cannot read random bytes: %v", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// userCodeAlphabet has no vowels, so that user codes never spell words,
+// and no characters that look alike.
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// NewUserCode returns a device user code like WDJB-MJHT, about 34 bits.
+func NewUserCode() (string, error) {
+	size := big.NewInt(int64(len(userCodeAlphabet)))
+
+	code := make([]byte, 0, 9)
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			code = append(code, '-')
+		}
+
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", fmt.Errorf("cannot read random bytes: %v", err)
+		}
+
+		code = append(code, userCodeAlphabet[n.Int64()])
+	}
+
+	return string(code), nil
+}
+
+// NormalizeUserCode accepts user codes typed in lower case, without the
+// dash or with extra spaces.
+func NormalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, code)
+}
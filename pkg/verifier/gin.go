@@ -0,0 +1,31 @@
+package verifier
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ContextClaims is the gin context key holding the claims.
+const ContextClaims = "polonium_claims"
+
+// Gin is the gin counterpart of Middleware. The claims are available both
+// through FromContext and ClaimsFromGin.
+func (v *Verifier) Gin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := v.verifyHeader(c.Request.Context(), c.GetHeader("Authorization"))
+		if err != nil {
+			status, challenge := httpError(err)
+			c.Header("WWW-Authenticate", challenge)
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), claims))
+		c.Set(ContextClaims, claims)
+		c.Next()
+	}
+}
+
+func ClaimsFromGin(c *gin.Context) (*Claims, bool) {
+	claims, ok := c.Value(ContextClaims).(*Claims)
+	return claims, ok
+}
@@ -0,0 +1,68 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor checks the `authorization` metadata of every call
+// and puts the claims into the handler context.
+func (v *Verifier) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := v.authorize(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (v *Verifier) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := v.authorize(stream.Context())
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &claimsStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type claimsStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *claimsStream) Context() context.Context {
+	return s.ctx
+}
+
+func (v *Verifier) authorize(ctx context.Context) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	claims, err := v.verifyHeader(ctx, header)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInsufficientScope):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, ErrNoToken), errors.Is(err, ErrInvalidToken),
+			errors.Is(err, ErrTokenInactive), errors.Is(err, ErrBoundToken):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return NewContext(ctx, claims), nil
+}
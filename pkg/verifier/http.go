@@ -0,0 +1,36 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Middleware rejects requests without a valid access token and puts the
+// claims into the request context, see FromContext.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := v.verifyHeader(r.Context(), r.Header.Get("Authorization"))
+		if err != nil {
+			status, challenge := httpError(err)
+			w.Header().Set("WWW-Authenticate", challenge)
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), claims)))
+	})
+}
+
+func httpError(err error) (int, string) {
+	switch {
+	case errors.Is(err, ErrNoToken):
+		return http.StatusUnauthorized, `Bearer realm="polonium"`
+	case errors.Is(err, ErrInsufficientScope):
+		return http.StatusForbidden, `Bearer realm="polonium", error="insufficient_scope"`
+	case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrTokenInactive), errors.Is(err, ErrBoundToken):
+		return http.StatusUnauthorized, fmt.Sprintf(`Bearer realm="polonium", error="invalid_token", error_description=%q`, err.Error())
+	}
+
+	return http.StatusServiceUnavailable, `Bearer realm="polonium"`
+}
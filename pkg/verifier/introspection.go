@@ -0,0 +1,64 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type (
+	// IntrospectionConfig points at the RFC 7662 endpoint of the private
+	// polonium server. The client must hold the `introspect` scope.
+	IntrospectionConfig struct {
+		URL          string
+		ClientID     string
+		ClientSecret string
+	}
+
+	introspector struct {
+		client *http.Client
+		cfg    *IntrospectionConfig
+	}
+
+	introspectionResponse struct {
+		Active bool `json:"active"`
+	}
+)
+
+func newIntrospector(client *http.Client, cfg *IntrospectionConfig) *introspector {
+	return &introspector{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+func (i *introspector) active(ctx context.Context, token string) (bool, error) {
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.cfg.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(i.cfg.ClientID, i.cfg.ClientSecret)
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var result introspectionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("cannot decode response: %v", err)
+	}
+
+	return result.Active, nil
+}
@@ -0,0 +1,152 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultJWKSCacheTTL = 5 * time.Minute
+	jwksRefreshInterval = 10 * time.Second
+)
+
+var signingAlgorithms = []string{"EdDSA", "ES256", "RS256"}
+
+// jwks caches the published keys for as long as the server allows and
+// refetches them early when a token names an unknown key. Concurrent
+// callers share one fetch, and a failed fetch keeps the cached keys in use.
+type jwks struct {
+	client  *http.Client
+	url     string
+	fetches singleflight.Group
+
+	mu        sync.RWMutex
+	byKid     map[string]jose.JSONWebKey
+	expiresAt time.Time
+	fetchedAt time.Time
+}
+
+func newJWKS(client *http.Client, url string) *jwks {
+	return &jwks{
+		client: client,
+		url:    url,
+		byKid:  map[string]jose.JSONWebKey{},
+	}
+}
+
+func (j *jwks) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	if kid == "" {
+		return nil, fmt.Errorf("token has no key id")
+	}
+
+	key, ok, stale := j.lookup(kid)
+	if stale {
+		// the cached key is still served when the refresh fails
+		if err := j.refresh(ctx); err != nil && !ok {
+			return nil, err
+		}
+		key, ok, _ = j.lookup(kid)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	return &key, nil
+}
+
+func (j *jwks) lookup(kid string) (jose.JSONWebKey, bool, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	key, ok := j.byKid[kid]
+	now := time.Now()
+	stale := now.After(j.expiresAt)
+	if !stale && !ok {
+		stale = now.Sub(j.fetchedAt) > jwksRefreshInterval
+	}
+
+	return key, ok, stale
+}
+
+// refresh fetches the keys once for all callers that need them at the same
+// time. After a failure the keys are not fetched again for
+// jwksRefreshInterval.
+func (j *jwks) refresh(ctx context.Context) error {
+	_, err, _ := j.fetches.Do("jwks", func() (interface{}, error) {
+		err := j.fetch(ctx)
+		if err != nil {
+			j.mu.Lock()
+			now := time.Now()
+			j.fetchedAt = now
+			if retry := now.Add(jwksRefreshInterval); j.expiresAt.Before(retry) {
+				j.expiresAt = retry
+			}
+			j.mu.Unlock()
+		}
+
+		return nil, err
+	})
+
+	return err
+}
+
+func (j *jwks) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot fetch jwks: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("cannot decode jwks: %v", err)
+	}
+
+	byKid := make(map[string]jose.JSONWebKey, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.Use == "" || key.Use == "sig" {
+			byKid[key.KeyID] = key
+		}
+	}
+
+	now := time.Now()
+	j.mu.Lock()
+	j.byKid, j.fetchedAt, j.expiresAt = byKid, now, now.Add(maxAge(resp.Header.Get("Cache-Control")))
+	j.mu.Unlock()
+
+	return nil
+}
+
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age=")
+		if !ok {
+			continue
+		}
+
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return defaultJWKSCacheTTL
+}
@@ -0,0 +1,94 @@
+package verifier
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const pasetoV4PublicHeader = "v4.public."
+
+var pasetoTimeClaims = []string{"exp", "nbf", "iat"}
+
+func (v *Verifier) decodePASETO(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token[len(pasetoV4PublicHeader):], ".")
+	if len(parts) != 2 {
+		return nil, errors.New("token has no footer")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(payload) <= ed25519.SignatureSize {
+		return nil, errors.New("malformed token payload")
+	}
+
+	footer, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed token footer")
+	}
+
+	var meta struct {
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(footer, &meta); err != nil {
+		return nil, errors.New("malformed token footer")
+	}
+
+	key, err := v.keys.key(ctx, meta.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	public, ok := key.Key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("paseto verification key is not an ed25519 key")
+	}
+
+	split := len(payload) - ed25519.SignatureSize
+	message, signature := payload[:split], payload[split:]
+	if !ed25519.Verify(public, pae([]byte(pasetoV4PublicHeader), message, footer, nil), signature) {
+		return nil, errors.New("token signature is invalid")
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(message, &fields); err != nil {
+		return nil, err
+	}
+
+	for _, name := range pasetoTimeClaims {
+		if value, ok := fields[name].(string); ok {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("claim %s is not an RFC 3339 time: %v", name, err)
+			}
+			fields[name] = t.Unix()
+		}
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := new(Claims)
+	if err := json.Unmarshal(raw, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func pae(pieces ...[]byte) []byte {
+	out := binary.LittleEndian.AppendUint64(nil, uint64(len(pieces))&(1<<63-1))
+	for _, piece := range pieces {
+		out = binary.LittleEndian.AppendUint64(out, uint64(len(piece))&(1<<63-1))
+		out = append(out, piece...)
+	}
+
+	return out
+}
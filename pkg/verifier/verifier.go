@@ -0,0 +1,282 @@
+// Package verifier checks polonium access tokens in downstream services.
+//
+// Tokens are verified offline against the published JWKS. JWT, PASETO
+// v4.public and encrypted (JWE) tokens are supported; HS256 tokens are not,
+// since their keys are never published. An introspection client can be set
+// to also reject tokens that were revoked before their expiry.
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	pathJWKS        = "/.well-known/jwks.json"
+	accessTokenType = "access"
+)
+
+var (
+	ErrNoToken           = errors.New("no access token provided")
+	ErrInvalidToken      = errors.New("invalid access token")
+	ErrInsufficientScope = errors.New("token is missing required scope")
+	ErrTokenInactive     = errors.New("token is revoked or inactive")
+	ErrBoundToken        = errors.New("token is bound to a dpop key")
+)
+
+type (
+	Config struct {
+		// BaseURL is the public polonium URL serving the JWKS.
+		BaseURL string
+		// Issuer is the expected `iss` claim.
+		Issuer string
+		// Audience is the expected `aud` claim, usually the service name.
+		Audience string
+		// Scopes are required on every token.
+		Scopes []string
+		// DecryptionKey is the private audience key for encrypted tokens.
+		DecryptionKey *jose.JSONWebKey
+		// Introspection enables an online revocation check.
+		Introspection *IntrospectionConfig
+		// HTTPClient is used for JWKS and introspection requests.
+		HTTPClient *http.Client
+		// Leeway tolerates clock skew when checking exp and nbf.
+		Leeway time.Duration
+	}
+
+	Claims struct {
+		UserID       string        `json:"user_id"`
+		Email        string        `json:"email"`
+		Deployer     string        `json:"deployer"`
+		Session      string        `json:"session"`
+		ClientID     string        `json:"client_id,omitempty"`
+		Scope        string        `json:"scope,omitempty"`
+		Confirmation *Confirmation `json:"cnf,omitempty"`
+		// Actor is set when a service acts for the user via token exchange.
+		Actor *Actor `json:"act,omitempty"`
+		jwt.RegisteredClaims
+	}
+
+	Confirmation struct {
+		JKT string `json:"jkt"`
+	}
+
+	Actor struct {
+		Sub string `json:"sub"`
+		Act *Actor `json:"act,omitempty"`
+	}
+
+	Verifier struct {
+		cfg           Config
+		keys          *jwks
+		introspection *introspector
+	}
+
+	claimsKey struct{}
+)
+
+func New(cfg Config) (*Verifier, error) {
+	if cfg.BaseURL == "" || cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("base url, issuer and audience are required")
+	}
+
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	v := &Verifier{
+		cfg:  cfg,
+		keys: newJWKS(cfg.HTTPClient, strings.TrimRight(cfg.BaseURL, "/")+pathJWKS),
+	}
+
+	if cfg.Introspection != nil {
+		v.introspection = newIntrospector(cfg.HTTPClient, cfg.Introspection)
+	}
+
+	return v, nil
+}
+
+// Verify checks the access token and every scope in Config.Scopes and
+// scopes. Errors wrap ErrInvalidToken, ErrInsufficientScope or
+// ErrTokenInactive.
+func (v *Verifier) Verify(ctx context.Context, token string, scopes ...string) (*Claims, error) {
+	if token == "" {
+		return nil, ErrNoToken
+	}
+
+	signed := token
+	if strings.Count(token, ".") == 4 {
+		decrypted, err := v.decrypt(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+		signed = decrypted
+	}
+
+	var (
+		claims *Claims
+		err    error
+	)
+
+	if strings.HasPrefix(signed, pasetoV4PublicHeader) {
+		claims, err = v.decodePASETO(ctx, signed)
+	} else {
+		claims, err = v.decodeJWT(ctx, signed)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if err := v.validate(claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	for _, scope := range append(slices.Clone(v.cfg.Scopes), scopes...) {
+		if !claims.HasScope(scope) {
+			return nil, fmt.Errorf("%w: %s", ErrInsufficientScope, scope)
+		}
+	}
+
+	if v.introspection != nil {
+		active, err := v.introspection.active(ctx, token)
+		if err != nil {
+			return nil, fmt.Errorf("cannot introspect token: %v", err)
+		}
+
+		if !active {
+			return nil, ErrTokenInactive
+		}
+	}
+
+	return claims, nil
+}
+
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(strings.Fields(c.Scope), scope)
+}
+
+// IsClient reports whether the token was issued to a client for itself with
+// the client_credentials grant, so it carries no user.
+func (c *Claims) IsClient() bool {
+	return c.UserID == "" && c.ClientID != "" && c.Subject == c.ClientID
+}
+
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+func (v *Verifier) decodeJWT(ctx context.Context, token string) (*Claims, error) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.keys.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		if key.Algorithm != t.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Method.Alg())
+		}
+
+		return key.Key, nil
+	}
+
+	claims := new(Claims)
+	_, err := jwt.ParseWithClaims(
+		token, claims, keyFunc,
+		jwt.WithValidMethods(signingAlgorithms),
+		jwt.WithoutClaimsValidation(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) validate(claims *Claims) error {
+	err := jwt.NewValidator(
+		jwt.WithIssuer(v.cfg.Issuer),
+		jwt.WithAudience(v.cfg.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.cfg.Leeway),
+	).Validate(claims)
+	if err != nil {
+		return err
+	}
+
+	if claims.Subject != accessTokenType && !claims.IsClient() {
+		return errors.New("not an access token")
+	}
+
+	return nil
+}
+
+func (v *Verifier) decrypt(token string) (string, error) {
+	if v.cfg.DecryptionKey == nil {
+		return "", errors.New("token is encrypted but no decryption key is configured")
+	}
+
+	encrypted, err := jose.ParseEncrypted(
+		token,
+		[]jose.KeyAlgorithm{jose.ECDH_ES},
+		[]jose.ContentEncryption{jose.A256GCM},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if kid := v.cfg.DecryptionKey.KeyID; kid != "" && encrypted.Header.KeyID != kid {
+		return "", fmt.Errorf("unknown encryption key: %s", encrypted.Header.KeyID)
+	}
+
+	signed, err := encrypted.Decrypt(v.cfg.DecryptionKey.Key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(signed), nil
+}
+
+func bearerToken(header string) (string, error) {
+	const prefix = "Bearer "
+
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", ErrNoToken
+	}
+
+	return strings.TrimSpace(header[len(prefix):]), nil
+}
+
+// verifyHeader is shared by the interceptors. Tokens bound to a DPoP key
+// are refused since their proofs are not checked here.
+func (v *Verifier) verifyHeader(ctx context.Context, header string) (*Claims, error) {
+	token, err := bearerToken(header)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := v.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Confirmation != nil {
+		return nil, ErrBoundToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,443 @@
+package verifier
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	signingKey struct {
+		kid     string
+		private ed25519.PrivateKey
+	}
+
+	// testServer publishes the JWKS and answers introspection requests
+	// like the polonium servers do.
+	testServer struct {
+		*httptest.Server
+
+		mu      sync.Mutex
+		keys    []*signingKey
+		revoked map[string]bool
+		down    bool
+		fetches atomic.Int32
+	}
+)
+
+func newSigningKey(t *testing.T, kid string) *signingKey {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &signingKey{kid: kid, private: private}
+}
+
+func newTestServer(t *testing.T, keys ...*signingKey) *testServer {
+	t.Helper()
+
+	s := &testServer{keys: keys, revoked: map[string]bool{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(pathJWKS, func(w http.ResponseWriter, _ *http.Request) {
+		s.fetches.Add(1)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var set jose.JSONWebKeySet
+		for _, key := range s.keys {
+			set.Keys = append(set.Keys, jose.JSONWebKey{
+				Key:       key.private.Public(),
+				KeyID:     key.kid,
+				Algorithm: jwt.SigningMethodEdDSA.Alg(),
+				Use:       "sig",
+			})
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(set)
+	})
+
+	mux.HandleFunc("/introspect", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "gateway" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		s.mu.Lock()
+		active := !s.revoked[r.PostFormValue("token")]
+		s.mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(introspectionResponse{Active: active})
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *testServer) publish(keys ...*signingKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *testServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *testServer) revoke(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[token] = true
+}
+
+func (s *testServer) verifier(t *testing.T, introspect bool) *Verifier {
+	t.Helper()
+
+	cfg := Config{BaseURL: s.URL, Issuer: "issuer", Audience: "api"}
+	if introspect {
+		cfg.Introspection = &IntrospectionConfig{URL: s.URL + "/introspect", ClientID: "gateway", ClientSecret: "secret"}
+	}
+
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+func testClaims() *Claims {
+	now := time.Now()
+	return &Claims{
+		UserID:  "user-id",
+		Session: "session",
+		Scope:   "read",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti",
+			Issuer:    "issuer",
+			Subject:   accessTokenType,
+			Audience:  jwt.ClaimStrings{"api"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+func (k *signingKey) sign(t *testing.T, claims *Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = k.kid
+
+	signed, err := token.SignedString(k.private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	key := newSigningKey(t, "first")
+	srv := newTestServer(t, key)
+	v := srv.verifier(t, false)
+
+	otherAudience := testClaims()
+	otherAudience.Audience = jwt.ClaimStrings{"billing"}
+
+	refresh := testClaims()
+	refresh.Subject = "refresh"
+
+	expired := testClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	otherIssuer := testClaims()
+	otherIssuer.Issuer = "other"
+
+	tests := []struct {
+		name   string
+		token  string
+		scopes []string
+		err    error
+	}{
+		{name: "valid", token: key.sign(t, testClaims()), scopes: []string{"read"}},
+		{name: "no token", err: ErrNoToken},
+		{name: "wrong audience", token: key.sign(t, otherAudience), err: ErrInvalidToken},
+		{name: "missing scope", token: key.sign(t, testClaims()), scopes: []string{"read", "write"}, err: ErrInsufficientScope},
+		{name: "refresh token", token: key.sign(t, refresh), err: ErrInvalidToken},
+		{name: "expired", token: key.sign(t, expired), err: ErrInvalidToken},
+		{name: "other issuer", token: key.sign(t, otherIssuer), err: ErrInvalidToken},
+		{name: "signed by another key", token: newSigningKey(t, "first").sign(t, testClaims()), err: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(context.Background(), tt.token, tt.scopes...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+
+			if tt.err == nil && claims.UserID != "user-id" {
+				t.Errorf("Verify() = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestVerifyKeyRollover(t *testing.T) {
+	first, second := newSigningKey(t, "first"), newSigningKey(t, "second")
+	srv := newTestServer(t, first)
+	v := srv.verifier(t, false)
+
+	if _, err := v.Verify(context.Background(), first.sign(t, testClaims())); err != nil {
+		t.Fatal(err)
+	}
+
+	// the successor is published while the cache still holds the first key
+	srv.publish(first, second)
+	token := second.sign(t, testClaims())
+
+	if _, err := v.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("unknown kid right after a fetch: got %v, want %v", err, ErrInvalidToken)
+	}
+
+	if fetches := srv.fetches.Load(); fetches != 1 {
+		t.Fatalf("jwks fetched %d times within the refresh interval, want 1", fetches)
+	}
+
+	v.keys.mu.Lock()
+	v.keys.fetchedAt = time.Now().Add(-jwksRefreshInterval)
+	v.keys.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.Verify(context.Background(), token)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("token of the successor key: %v", err)
+		}
+	}
+
+	if fetches := srv.fetches.Load(); fetches != 2 {
+		t.Errorf("jwks fetched %d times for one rollover, want 2", fetches)
+	}
+}
+
+func TestVerifyServesCachedKeysWhenRefreshFails(t *testing.T) {
+	key := newSigningKey(t, "first")
+	srv := newTestServer(t, key)
+	v := srv.verifier(t, false)
+	token := key.sign(t, testClaims())
+
+	if _, err := v.Verify(context.Background(), token); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.setDown(true)
+	v.keys.mu.Lock()
+	v.keys.expiresAt = time.Now().Add(-time.Second)
+	v.keys.mu.Unlock()
+
+	for range 3 {
+		if _, err := v.Verify(context.Background(), token); err != nil {
+			t.Fatalf("cached key after a failed refresh: %v", err)
+		}
+	}
+
+	// a failed refresh is not retried on every request
+	if fetches := srv.fetches.Load(); fetches != 2 {
+		t.Errorf("jwks fetched %d times, want 2", fetches)
+	}
+
+	if _, err := v.Verify(context.Background(), newSigningKey(t, "second").sign(t, testClaims())); err == nil {
+		t.Error("unknown key accepted while the jwks is down")
+	}
+}
+
+func TestVerifyIntrospection(t *testing.T) {
+	key := newSigningKey(t, "first")
+	srv := newTestServer(t, key)
+	v := srv.verifier(t, true)
+
+	token := key.sign(t, testClaims())
+	if _, err := v.Verify(context.Background(), token); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.revoke(token)
+	if _, err := v.Verify(context.Background(), token); !errors.Is(err, ErrTokenInactive) {
+		t.Errorf("revoked token: got %v, want %v", err, ErrTokenInactive)
+	}
+
+	bad, err := New(Config{
+		BaseURL:       srv.URL,
+		Issuer:        "issuer",
+		Audience:      "api",
+		Introspection: &IntrospectionConfig{URL: srv.URL + "/introspect", ClientID: "gateway", ClientSecret: "wrong"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bad.Verify(context.Background(), key.sign(t, testClaims())); err == nil || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("failed introspection: got %v, want an unavailable error", err)
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	key := newSigningKey(t, "first")
+	srv := newTestServer(t, key)
+	v := srv.verifier(t, true)
+
+	valid := key.sign(t, testClaims())
+	other := testClaims()
+	other.ID = "revoked"
+	revoked := key.sign(t, other)
+	srv.revoke(revoked)
+
+	bound := testClaims()
+	bound.Confirmation = &Confirmation{JKT: "jkt"}
+
+	unscoped := testClaims()
+	unscoped.Scope = ""
+	scoped, err := New(Config{BaseURL: srv.URL, Issuer: "issuer", Audience: "api", Scopes: []string{"read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		v      *Verifier
+		header string
+		status int
+		code   codes.Code
+	}{
+		{name: "valid", v: v, header: "Bearer " + valid, status: http.StatusOK, code: codes.OK},
+		{name: "no token", v: v, status: http.StatusUnauthorized, code: codes.Unauthenticated},
+		{name: "revoked", v: v, header: "Bearer " + revoked, status: http.StatusUnauthorized, code: codes.Unauthenticated},
+		{name: "dpop bound", v: v, header: "Bearer " + key.sign(t, bound), status: http.StatusUnauthorized, code: codes.Unauthenticated},
+		{name: "missing scope", v: scoped, header: "Bearer " + key.sign(t, unscoped), status: http.StatusForbidden, code: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := tt.v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if _, ok := FromContext(r.Context()); !ok {
+					t.Error("no claims in the request context")
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tt.header)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Middleware status = %d, want %d", rec.Code, tt.status)
+			}
+
+			router := gin.New()
+			router.GET("/", tt.v.Gin(), func(c *gin.Context) {
+				if _, ok := ClaimsFromGin(c); !ok {
+					t.Error("no claims in the gin context")
+				}
+			})
+
+			rec = httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Gin status = %d, want %d", rec.Code, tt.status)
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", tt.header))
+			_, err := tt.v.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+				if _, ok := FromContext(ctx); !ok {
+					t.Error("no claims in the grpc context")
+				}
+				return nil, nil
+			})
+
+			if code := status.Code(err); code != tt.code {
+				t.Errorf("UnaryServerInterceptor code = %v, want %v", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestPAE(t *testing.T) {
+	// test vectors of the PASETO specification, docs/01-Protocol-Versions/Common.md,
+	// shared with the tests of the issuing side in internal/auth
+	tests := []struct {
+		name   string
+		pieces [][]byte
+		want   string
+	}{
+		{name: "no pieces", want: "0000000000000000"},
+		{name: "one empty piece", pieces: [][]byte{{}}, want: "0100000000000000" + "0000000000000000"},
+		{name: "two empty pieces", pieces: [][]byte{{}, {}}, want: "0200000000000000" + "0000000000000000" + "0000000000000000"},
+		{
+			name:   "one piece",
+			pieces: [][]byte{[]byte("Paragon")},
+			want:   "0100000000000000" + "0700000000000000" + hex.EncodeToString([]byte("Paragon")),
+		},
+		{
+			name:   "two pieces",
+			pieces: [][]byte{[]byte("Paragon"), []byte("Initiative")},
+			want: "0200000000000000" +
+				"0700000000000000" + hex.EncodeToString([]byte("Paragon")) +
+				"0a00000000000000" + hex.EncodeToString([]byte("Initiative")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hex.EncodeToString(pae(tt.pieces...)); got != tt.want {
+				t.Errorf("pae() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}